@@ -49,7 +49,7 @@ func GetArgs(cmd *cobra.Command, productName string) internal.Args {
 	if err != nil {
 		panic(err)
 	}
-	args.QueryMappingFile, err = cmd.Flags().GetString(queryMapping)
+	args.QueryMappingFiles, err = cmd.Flags().GetStringSlice(queryMapping)
 	if err != nil {
 		panic(err)
 	}
@@ -57,10 +57,70 @@ func GetArgs(cmd *cobra.Command, productName string) internal.Args {
 	if err != nil {
 		panic(err)
 	}
+	args.QueryMappingSHA256s, err = cmd.Flags().GetStringSlice(queryMappingSHA256Arg)
+	if err != nil {
+		panic(err)
+	}
+	args.StrictMapping, err = cmd.Flags().GetBool(strictMappingArg)
+	if err != nil {
+		panic(err)
+	}
+	args.BranchTriageOnce, err = cmd.Flags().GetBool(branchTriageOnceArg)
+	if err != nil {
+		panic(err)
+	}
+	args.QueryRenamingSHA256, err = cmd.Flags().GetString(queryRenamingSHA256Arg)
+	if err != nil {
+		panic(err)
+	}
+	args.SignatureKeyFile, err = cmd.Flags().GetString(signatureKeyArg)
+	if err != nil {
+		panic(err)
+	}
+	args.Offline, err = cmd.Flags().GetBool(offlineArg)
+	if err != nil {
+		panic(err)
+	}
+	args.DataBundleFile, err = cmd.Flags().GetString(dataBundleArg)
+	if err != nil {
+		panic(err)
+	}
+	args.DataBundleKeyFile, err = cmd.Flags().GetString(dataBundleKeyArg)
+	if err != nil {
+		panic(err)
+	}
 	args.NestedTeams, err = cmd.Flags().GetBool(nestedTeams)
 	if err != nil {
 		panic(err)
 	}
+	args.TeamMappingFile, err = cmd.Flags().GetString(teamMapping)
+	if err != nil {
+		panic(err)
+	}
+	args.UserMappingFile, err = cmd.Flags().GetString(userMapping)
+	if err != nil {
+		panic(err)
+	}
+	args.RoleTranslationFile, err = cmd.Flags().GetString(roleTranslation)
+	if err != nil {
+		panic(err)
+	}
+	args.StateMappingFile, err = cmd.Flags().GetString(stateMapping)
+	if err != nil {
+		panic(err)
+	}
+	args.CustomFieldMappingFile, err = cmd.Flags().GetString(customFieldMapping)
+	if err != nil {
+		panic(err)
+	}
+	args.RedactionRulesFile, err = cmd.Flags().GetString(redactionRules)
+	if err != nil {
+		panic(err)
+	}
+	args.PresetCatalogFile, err = cmd.Flags().GetString(presetCatalog)
+	if err != nil {
+		panic(err)
+	}
 	args.IsDefaultProjectActiveSince = args.ProjectsActiveSince == emptyProjectsActiveSince
 	if args.IsDefaultProjectActiveSince {
 		args.ProjectsActiveSince = projectsActiveSinceDefaultValue
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/checkmarxDev/ast-sast-export/internal"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/export"
+	"github.com/spf13/cobra"
+)
+
+const (
+	engineConfigArg = "engine-config"
+	signingKeyArg   = "signing-key"
+	outputArg       = "output"
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(bundleDataCmd)
+	bundleDataCmd.Flags().String(queryMapping, queryMappingPathDefault, `query mapping file path or URL, or "embedded"`)
+	bundleDataCmd.Flags().String(renaming, renamingDefault, `query renaming file path or URL, or "embedded"`)
+	bundleDataCmd.Flags().String(engineConfigArg, databundle.EmbeddedSource, `engine configuration keys file path or URL, or "embedded"`)
+	bundleDataCmd.Flags().String(signingKeyArg, "",
+		"path to PEM file with the Ed25519 private key signing the bundle, generated if it doesn't exist")
+	bundleDataCmd.Flags().String(outputArg, "", "path to the data bundle file, defaults to a timestamped file in the current folder")
+	if err := bundleDataCmd.MarkFlagRequired(signingKeyArg); err != nil {
+		panic(err)
+	}
+}
+
+var bundleDataCmd = &cobra.Command{
+	Use:   "bundle-data",
+	Short: "Create a signed data bundle for exporting without internet access",
+	Long: `Create a signed data bundle with the query mapping, query renaming and engine configuration keys,
+for exporting from SAST servers without internet access. Example usage:
+
+cxsast_exporter bundle-data --query-mapping https://example.com/mapping.json --signing-key bundle.pem
+
+cxsast_exporter --user username --pass password --url http://localhost --offline \
+  --data-bundle cxsast_exporter-data-2021-09-10-15-42-35.json --data-bundle-key bundle.pem.pub
+`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		args := internal.BundleDataArgs{RunTime: time.Now()}
+		var err error
+		if args.QueryMappingFile, err = cmd.Flags().GetString(queryMapping); err != nil {
+			return err
+		}
+		if args.QueryRenamingFile, err = cmd.Flags().GetString(renaming); err != nil {
+			return err
+		}
+		if args.EngineConfigMappingFile, err = cmd.Flags().GetString(engineConfigArg); err != nil {
+			return err
+		}
+		if args.SigningKeyFile, err = cmd.Flags().GetString(signingKeyArg); err != nil {
+			return err
+		}
+		if args.OutputFile, err = cmd.Flags().GetString(outputArg); err != nil {
+			return err
+		}
+		if args.OutputFile == "" {
+			args.OutputFile = fmt.Sprintf("%s-data-%s.json", productName, args.RunTime.Format(export.DateTimeFormat))
+		}
+		return internal.BundleData(&args, cmd.OutOrStdout())
+	},
+}
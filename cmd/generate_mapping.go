@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/checkmarxDev/ast-sast-export/internal"
+	"github.com/spf13/cobra"
+)
+
+const (
+	compareArg              = "compare"
+	generatedMappingDefault = "mapping.json"
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(generateMappingCmd)
+	generateMappingCmd.Flags().String(userArg, "", "SAST admin username")
+	generateMappingCmd.Flags().String(passArg, "", "SAST admin password")
+	generateMappingCmd.Flags().String(urlArg, "", "SAST url")
+	generateMappingCmd.Flags().String(renaming, renamingDefault, `query renaming file path or URL, or "embedded"`)
+	generateMappingCmd.Flags().String(dataBundleArg, "", "path to data bundle created with bundle-data, instead of the embedded data")
+	generateMappingCmd.Flags().String(dataBundleKeyArg, "", "path to PEM file with the public key verifying the data bundle")
+	generateMappingCmd.Flags().String(outputArg, generatedMappingDefault, "path to the generated query mapping file")
+	generateMappingCmd.Flags().String(compareArg, "", `query mapping file path or URL, or "embedded", to compare the generated mapping with`)
+	generateMappingCmd.MarkFlagsRequiredTogether(dataBundleArg, dataBundleKeyArg)
+	for _, flag := range []string{userArg, passArg, urlArg} {
+		if err := generateMappingCmd.MarkFlagRequired(flag); err != nil {
+			panic(err)
+		}
+	}
+}
+
+var generateMappingCmd = &cobra.Command{
+	Use:   "generate-mapping",
+	Short: "Generate a query mapping file from the queries of a SAST instance",
+	Long: `Generate a query mapping file from the queries of a SAST instance, including custom queries. Example usage:
+
+cxsast_exporter generate-mapping --user username --pass password --url http://localhost \
+  --output mapping.json --compare embedded
+`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		args := internal.GenerateMappingArgs{}
+		var err error
+		if args.Username, err = cmd.Flags().GetString(userArg); err != nil {
+			return err
+		}
+		if args.Password, err = cmd.Flags().GetString(passArg); err != nil {
+			return err
+		}
+		if args.URL, err = cmd.Flags().GetString(urlArg); err != nil {
+			return err
+		}
+		if args.QueryRenamingFile, err = cmd.Flags().GetString(renaming); err != nil {
+			return err
+		}
+		if args.DataBundleFile, err = cmd.Flags().GetString(dataBundleArg); err != nil {
+			return err
+		}
+		if args.DataBundleKeyFile, err = cmd.Flags().GetString(dataBundleKeyArg); err != nil {
+			return err
+		}
+		if args.OutputFile, err = cmd.Flags().GetString(outputArg); err != nil {
+			return err
+		}
+		if args.CompareFile, err = cmd.Flags().GetString(compareArg); err != nil {
+			return err
+		}
+		return internal.GenerateMapping(&args, cmd.OutOrStdout())
+	},
+}
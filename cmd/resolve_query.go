@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"github.com/checkmarxDev/ast-sast-export/internal"
+	"github.com/spf13/cobra"
+)
+
+const (
+	languageArg    = "language"
+	groupArg       = "group"
+	nameArg        = "name"
+	sastQueryIDArg = "sast-id"
+	queryPathArg   = "query-path"
+)
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(resolveQueryCmd)
+	resolveQueryCmd.Flags().String(languageArg, "", "query language, e.g. Java")
+	resolveQueryCmd.Flags().String(groupArg, "", "query group, e.g. Java_High_Risk")
+	resolveQueryCmd.Flags().String(nameArg, "", "query name, e.g. SQL_Injection")
+	resolveQueryCmd.Flags().String(sastQueryIDArg, "", "SAST query id")
+	resolveQueryCmd.Flags().String(queryPathArg, "", `query path from a SAST report, e.g. "Java\Cx\Java High Risk\SQL Injection Version:1"`)
+	resolveQueryCmd.Flags().StringSlice(queryMapping, []string{queryMappingPathDefault},
+		`query mapping file path or URL, or "embedded", can be repeated, later mappings take precedence`)
+	resolveQueryCmd.Flags().StringSlice(renaming, []string{renamingDefault},
+		`query renaming file path or URL, or "embedded", can be repeated, later renamings take precedence`)
+	resolveQueryCmd.Flags().String(dataBundleArg, "", "path to data bundle created with bundle-data, instead of the embedded data")
+	resolveQueryCmd.Flags().String(dataBundleKeyArg, "", "path to PEM file with the public key verifying the data bundle")
+	resolveQueryCmd.MarkFlagsRequiredTogether(dataBundleArg, dataBundleKeyArg)
+	resolveQueryCmd.MarkFlagsOneRequired(languageArg, queryPathArg, sastQueryIDArg)
+}
+
+var resolveQueryCmd = &cobra.Command{
+	Use:   "resolve-query",
+	Short: "Explain how the AST query id of a SAST query is resolved",
+	Long: `Explain how the AST query id of a SAST query is resolved, without connecting to SAST. Example usage:
+
+cxsast_exporter resolve-query --language Java --group Java_High_Risk --name SQL_Injection --sast-id 5157 \
+  --query-mapping mapping.json --query-renaming renames.json
+`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		args := internal.ResolveQueryArgs{}
+		var err error
+		if args.Language, err = cmd.Flags().GetString(languageArg); err != nil {
+			return err
+		}
+		if args.Group, err = cmd.Flags().GetString(groupArg); err != nil {
+			return err
+		}
+		if args.Name, err = cmd.Flags().GetString(nameArg); err != nil {
+			return err
+		}
+		if args.SastQueryID, err = cmd.Flags().GetString(sastQueryIDArg); err != nil {
+			return err
+		}
+		if args.QueryPath, err = cmd.Flags().GetString(queryPathArg); err != nil {
+			return err
+		}
+		if args.QueryMappingFiles, err = cmd.Flags().GetStringSlice(queryMapping); err != nil {
+			return err
+		}
+		if args.QueryRenamingFiles, err = cmd.Flags().GetStringSlice(renaming); err != nil {
+			return err
+		}
+		if args.DataBundleFile, err = cmd.Flags().GetString(dataBundleArg); err != nil {
+			return err
+		}
+		if args.DataBundleKeyFile, err = cmd.Flags().GetString(dataBundleKeyArg); err != nil {
+			return err
+		}
+		return internal.ResolveQuery(&args, cmd.OutOrStdout())
+	},
+}
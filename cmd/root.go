@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/checkmarxDev/ast-sast-export/internal"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/export"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/logging"
 	"github.com/rs/zerolog"
@@ -25,9 +26,24 @@ const (
 	teamName                = "project-team"
 	queryMapping            = "query-mapping"
 	renaming                = "query-renaming"
-	queryMappingPathDefault = "https://raw.githubusercontent.com/Checkmarx/sast-to-ast-export/master/data/mapping.json"
-	renamingDefault         = "https://raw.githubusercontent.com/Checkmarx/sast-to-ast-export/refs/heads/master/data/renames.json"
+	queryMappingPathDefault = databundle.EmbeddedSource
+	renamingDefault         = databundle.EmbeddedSource
+	queryMappingSHA256Arg   = "query-mapping-sha256"
+	queryRenamingSHA256Arg  = "query-renaming-sha256"
+	strictMappingArg        = "strict-mapping"
+	branchTriageOnceArg     = "branch-triage-once"
+	signatureKeyArg         = "signature-key"
+	offlineArg              = "offline"
+	dataBundleArg           = "data-bundle"
+	dataBundleKeyArg        = "data-bundle-key"
 	nestedTeams             = "nested-teams"
+	teamMapping             = "team-mapping"
+	userMapping             = "user-mapping"
+	roleTranslation         = "role-translation"
+	stateMapping            = "state-mapping"
+	customFieldMapping      = "custom-field-mapping"
+	redactionRules          = "redaction-rules"
+	presetCatalog           = "preset-catalog"
 	simIDVersionArg         = "simIDVersion"
 	excludeFileArg          = "exclude-file"
 	addCustomExtArg         = "addCustomExt"
@@ -129,15 +145,37 @@ func init() {
 	rootCmd.Flags().StringP(userArg, "", "", "SAST username")
 	rootCmd.Flags().StringP(passArg, "", "", "SAST password")
 	rootCmd.Flags().StringP(urlArg, "", "", "SAST url")
-	rootCmd.Flags().StringP(queryMapping, "", queryMappingPathDefault, "path to file query mapping IDs from AST for triage")
-	rootCmd.Flags().StringP(renaming, "", renamingDefault, "path to file query renaming IDs from AST for overrides")
+	rootCmd.Flags().StringSliceP(queryMapping, "", []string{queryMappingPathDefault},
+		`path or URL to file query mapping IDs from AST for triage, "embedded" uses the data bundle; `+
+			"can be repeated, later mappings override earlier ones")
+	rootCmd.Flags().StringP(renaming, "", renamingDefault,
+		`path or URL to file query renaming IDs from AST for overrides, "embedded" uses the data bundle`)
+	rootCmd.Flags().StringSliceP(queryMappingSHA256Arg, "", []string{},
+		"expected SHA-256 hash of each query mapping file, in the same order")
+	rootCmd.Flags().Bool(strictMappingArg, false, "fail if query mapping conflicts affect triaged queries")
+	rootCmd.Flags().Bool(branchTriageOnceArg, false,
+		"export triage once per branch lineage, branched projects only get the triage that differs from their root project")
+	rootCmd.Flags().StringP(queryRenamingSHA256Arg, "", "", "expected SHA-256 hash of the query renaming file")
+	rootCmd.Flags().StringP(signatureKeyArg, "", "",
+		"path to PEM file with the public key verifying the detached signatures (.sig) of query mapping and renaming files")
+	rootCmd.Flags().Bool(offlineArg, false, "forbid downloading anything but SAST data")
+	rootCmd.Flags().StringP(dataBundleArg, "", "", "path to data bundle created with bundle-data, instead of the embedded data")
+	rootCmd.Flags().StringP(dataBundleKeyArg, "", "", "path to PEM file with the public key verifying the data bundle")
 	rootCmd.Flags().StringP(teamName, "", "", "team name filter")
 	rootCmd.Flags().StringP(projectsIDs, "", "", "project ID filter")
-	rootCmd.Flags().StringSliceP(exportArg, "", export.GetOptions(), "SAST export options")
+	rootCmd.Flags().StringSliceP(exportArg, "", export.GetDefaultOptions(),
+		"SAST export options, settings, scans and system are only exported if given")
 	rootCmd.Flags().IntP(projectsActiveSinceArg, "", emptyProjectsActiveSince, projectsActiveSinceUsage)
 	rootCmd.Flags().Bool(debugArg, false, "activate debug mode")
 	rootCmd.Flags().BoolP(verboseArg, "v", false, "enable verbose logging to console")
 	rootCmd.Flags().Bool(nestedTeams, false, "include original team structure without flattening")
+	rootCmd.Flags().StringP(teamMapping, "", "", "path to JSON file mapping SAST team paths to AST group paths")
+	rootCmd.Flags().StringP(userMapping, "", "", "path to CSV file mapping SAST usernames to the emails identifying them in AST")
+	rootCmd.Flags().StringP(roleTranslation, "", "", "path to JSON file overriding the translation of SAST roles and permissions to AST")
+	rootCmd.Flags().StringP(stateMapping, "", "", "path to JSON file mapping SAST result states to AST predefined or custom states")
+	rootCmd.Flags().StringP(customFieldMapping, "", "", "path to JSON file mapping SAST custom fields to AST project tags")
+	rootCmd.Flags().StringP(redactionRules, "", "", "path to JSON file with the fields to drop, mask, hash or keep in exported files")
+	rootCmd.Flags().StringP(presetCatalog, "", "", "path to JSON file with the AST presets to compare exported presets with")
 	rootCmd.Flags().IntVarP(
 		&simIDVersion,
 		simIDVersionArg,
@@ -171,4 +209,35 @@ func init() {
 	if err := rootCmd.MarkFlagFilename(excludeFileArg, "txt"); err != nil {
 		panic(err)
 	}
+	if err := rootCmd.MarkFlagFilename(teamMapping, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(userMapping, "csv"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(roleTranslation, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(stateMapping, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(customFieldMapping, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(redactionRules, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(presetCatalog, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(signatureKeyArg, "pub", "pem"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(dataBundleArg, "json"); err != nil {
+		panic(err)
+	}
+	if err := rootCmd.MarkFlagFilename(dataBundleKeyArg, "pub", "pem"); err != nil {
+		panic(err)
+	}
+	rootCmd.MarkFlagsRequiredTogether(dataBundleArg, dataBundleKeyArg)
 }
@@ -0,0 +1,19 @@
+// Package data embeds the query and engine configuration data the exporter uses by default,
+// so exports don't need to download it.
+package data
+
+import _ "embed" //nolint:revive
+
+var (
+	// QueryMapping maps SAST query ids to AST query ids
+	//go:embed mapping.json
+	QueryMapping []byte
+
+	// QueryRenaming lists the queries renamed in SAST, with their AST query ids before and after the rename
+	//go:embed renames.json
+	QueryRenaming []byte
+
+	// EngineConfigMapping has the keys of the SAST engine configurations
+	//go:embed EngineConfigMapping.json
+	EngineConfigMapping []byte
+)
@@ -4,10 +4,10 @@ import (
 	"encoding/xml"
 	"sort"
 	"strconv"
+	"sync"
 
-	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
-	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
 	"github.com/rs/zerolog/log"
 )
@@ -18,29 +18,46 @@ const (
 
 type (
 	Provider struct {
-		queryProvider interfaces.QueriesRepo
-		mapping       []querymapping.QueryMap
+		queryProvider   interfaces.QueriesRepo
+		queryIDResolver *queryid.Resolver
+		queries         *soap.GetQueryCollectionResponse
+		queriesErr      error
+		queriesOnce     sync.Once
 	}
 )
 
-func NewProvider(queryProvider interfaces.QueriesRepo, queryMappingProvider interfaces.QueryMappingRepo) (*Provider, error) {
+func NewProvider(queryProvider interfaces.QueriesRepo, queryIDResolver *queryid.Resolver) (*Provider, error) {
 	return &Provider{
-		queryProvider: queryProvider,
-		mapping:       queryMappingProvider.GetMapping(),
+		queryProvider:   queryProvider,
+		queryIDResolver: queryIDResolver,
 	}, nil
 }
 
-func (e *Provider) GetQueryID(language, name, group, sastQueryID string) (string, error) {
-	mappedAstID := e.getMappedID(sastQueryID)
-	if mappedAstID != "" {
-		return mappedAstID, nil
+// GetQueryID returns the AST query id of a SAST query
+func (e *Provider) GetQueryID(query queryid.Query) (string, error) {
+	resolution, err := e.ResolveQuery(query)
+	if err != nil {
+		return "", err
 	}
-	return common.GetAstQueryID(language, name, group)
+	return resolution.AstQueryID, nil
+}
+
+// ResolveQuery returns the AST query id of a SAST query and how it was resolved
+func (e *Provider) ResolveQuery(query queryid.Query) (*queryid.Resolution, error) {
+	return e.queryIDResolver.Resolve(query)
+}
+
+// GetQueriesList returns the SAST query collection, which is fetched once and shared by everything translating queries
+func (e *Provider) GetQueriesList() (*soap.GetQueryCollectionResponse, error) {
+	e.queriesOnce.Do(func() {
+		e.queries, e.queriesErr = e.queryProvider.GetQueriesList()
+	})
+	return e.queries, e.queriesErr
 }
 
 func (e *Provider) GetCustomQueriesList() (*soap.GetQueryCollectionResponse, error) {
 	var output soap.GetQueryCollectionResponse
-	queryResponse, err := e.queryProvider.GetQueriesList()
+	queryResponse, err := e.GetQueriesList()
 	if err != nil {
 		return nil, err
 	}
@@ -250,11 +267,7 @@ func (e *Provider) GetStateMapping() (map[string]string, error) {
 	return stateMapping, nil
 }
 
-func (e *Provider) getMappedID(sastID string) string {
-	for _, queryMap := range e.mapping {
-		if queryMap.SastID == sastID {
-			return queryMap.AstID
-		}
-	}
-	return ""
+// IsMappedQuery returns true if the query mapping has the AST query id of a SAST query
+func (e *Provider) IsMappedQuery(sastQueryID string) bool {
+	return e.queryIDResolver.IsMapped(sastQueryID)
 }
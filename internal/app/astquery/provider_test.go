@@ -8,15 +8,27 @@ import (
 	"testing"
 
 	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
-	mock_interfaces "github.com/checkmarxDev/ast-sast-export/test/mocks/app/ast_query_mapping"
 	mock_interfaces_queries "github.com/checkmarxDev/ast-sast-export/test/mocks/app/queries"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+func getQueryIDResolver(t *testing.T) *queryid.Resolver {
+	resolver := queryid.NewResolver()
+	resolver.AddMappings("mapping.json", []querymapping.QueryMap{{AstID: "11111", SastID: "100000"}})
+	renamesFile := filepath.Join("..", "..", "..", "data", "renames.json")
+	content, readErr := os.ReadFile(renamesFile)
+	require.NoError(t, readErr)
+	renames, err := common.ParseRenames(content, renamesFile)
+	require.NoError(t, err)
+	resolver.AddRenames(renamesFile, renames)
+	return resolver
+}
+
 type QueryIDTest struct {
 	Language, Group, Name, SastID, Expected string
 }
@@ -28,11 +40,6 @@ type CustomQueriesListTest struct {
 func TestProvider_GetQueryID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
-	queryMappingProvider := mock_interfaces.NewMockQueryMappingRepo(ctrl)
-
-	testRenameFile := filepath.Join("..", "..", "..", "data", "renames.json")
-	err := common.LoadRename(testRenameFile)
-	require.NoError(t, err, "Failed to load common rename map for test")
 
 	queryIDTests := []QueryIDTest{
 		{"Kotlin", "Kotlin_High_Risk", "Code_Injection", "1", "15158446363146771540"},
@@ -40,36 +47,100 @@ func TestProvider_GetQueryID(t *testing.T) {
 		{"Go", "General", "Find_Command_Injection_Sanitize", "3", "9498204717545098527"},
 		{"Go", "Custom", "Custom_query", "100000", "11111"},
 	}
-	queryMappingProvider.EXPECT().GetMapping().Return([]querymapping.QueryMap{{
-		AstID:  "11111",
-		SastID: "100000",
-	}}).Times(4)
 	for _, test := range queryIDTests {
 		testName := fmt.Sprintf("%s %s %s", test.Language, test.Group, test.Name)
 		t.Run(testName, func(t *testing.T) {
-			repo, repoErr := NewProvider(queryProvider, queryMappingProvider)
+			repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
 			assert.NoError(t, repoErr)
 
-			result, err := repo.GetQueryID(test.Language, test.Name, test.Group, test.SastID)
+			result, err := repo.GetQueryID(queryid.Query{
+				Language: test.Language, Group: test.Group, Name: test.Name, SastQueryID: test.SastID,
+			})
 			assert.NoError(t, err)
 			assert.Equal(t, test.Expected, result)
 		})
 	}
 }
 
-func TestProvider_GetCustomQueries(t *testing.T) {
-	var queriesObj, customQueriesObj soap.GetQueryCollectionResponse
+func TestProvider_GetQueryIDMatchesReportQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+	repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
+	assert.NoError(t, repoErr)
+	group := soap.CxWSQueryGroup{Name: "Java_High_Risk", LanguageName: "Java"}
+	query := soap.CxWSQuery{Name: "SQL_Injection", QueryID: 7}
+
+	collectionID, collectionErr := repo.GetQueryID(queryid.NewCollectionQuery(&group, &query))
+	reportResolution, reportErr := repo.ResolveQuery(queryid.Query{
+		Language: "Java", Group: "Java High Risk", Name: "SQL Injection", SastQueryID: "7",
+		QueryPath: `Java\Cx\Java High Risk\SQL Injection Version:3`,
+	})
+
+	withoutPathResolution, withoutPathErr := repo.ResolveQuery(queryid.Query{
+		Language: "Java", Group: "Java High Risk", Name: "SQL Injection", SastQueryID: "7",
+	})
+
+	assert.NoError(t, collectionErr)
+	assert.NoError(t, reportErr)
+	assert.NoError(t, withoutPathErr)
+	assert.Equal(t, queryid.HashSource, reportResolution.Source)
+	assert.Equal(t, collectionID, reportResolution.AstQueryID)
+	assert.Equal(t, collectionID, withoutPathResolution.AstQueryID)
+}
+
+func TestProvider_ResolveQuery(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
-	queryMappingProvider := mock_interfaces.NewMockQueryMappingRepo(ctrl)
-	queryMappingProvider.EXPECT().GetMapping().Return([]querymapping.QueryMap{{
-		AstID:  "11111",
-		SastID: "100000",
-	}}).Times(1)
-	repo, repoErr := NewProvider(queryProvider, queryMappingProvider)
+	repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
 	assert.NoError(t, repoErr)
 
+	mapped, mappedErr := repo.ResolveQuery(queryid.Query{Language: "Go", Group: "Custom", Name: "Custom_query", SastQueryID: "100000"})
+	assert.NoError(t, mappedErr)
+	assert.Equal(t, "11111", mapped.AstQueryID)
+	assert.Equal(t, queryid.MappingSource, mapped.Source)
+
+	hashed, hashedErr := repo.ResolveQuery(queryid.Query{
+		Language: "Kotlin", Group: "Kotlin_High_Risk", Name: "Code_Injection", SastQueryID: "1",
+	})
+	assert.NoError(t, hashedErr)
+	assert.Equal(t, "15158446363146771540", hashed.AstQueryID)
+	assert.Equal(t, queryid.HashSource, hashed.Source)
+}
+
+func TestProvider_IsMappedQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+	repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
+	assert.NoError(t, repoErr)
+
+	assert.True(t, repo.IsMappedQuery("100000"))
+	assert.False(t, repo.IsMappedQuery("1"))
+}
+
+func TestProvider_GetQueriesList(t *testing.T) {
+	var queriesObj soap.GetQueryCollectionResponse
+	ctrl := gomock.NewController(t)
+	queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+	queryProvider.EXPECT().GetQueriesList().Return(&queriesObj, nil).Times(1)
+	repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
+	assert.NoError(t, repoErr)
+
+	result, err := repo.GetQueriesList()
+	assert.NoError(t, err)
+	_, customErr := repo.GetCustomQueriesList()
+	assert.NoError(t, customErr)
+
+	assert.Same(t, &queriesObj, result)
+}
+
+func TestProvider_GetCustomQueries(t *testing.T) {
+	var queriesObj, customQueriesObj soap.GetQueryCollectionResponse
+
 	t.Run("Successful getting custom queries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+		repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
+		assert.NoError(t, repoErr)
 		queries, ioErr := os.ReadFile("../../../test/data/queries/queries.xml")
 		assert.NoError(t, ioErr)
 		customQueries, ioCustomErr := os.ReadFile("../../../test/data/queries/custom_queries.xml")
@@ -84,6 +155,10 @@ func TestProvider_GetCustomQueries(t *testing.T) {
 	})
 
 	t.Run("Error with getting custom queries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		queryProvider := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+		repo, repoErr := NewProvider(queryProvider, getQueryIDResolver(t))
+		assert.NoError(t, repoErr)
 		queryProvider.EXPECT().GetQueriesList().Return(nil, fmt.Errorf("failed getting custom queries")).Times(1)
 
 		_, err := repo.GetCustomQueriesList()
@@ -4,11 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
-	"io"
-	"net/http"
-	"os"
 	"strconv"
-	"strings"
 )
 
 const queryIDIntBase = 10
@@ -20,80 +16,24 @@ type RenameEntry struct {
 	NewPathAstID uint64 `json:"newPathAstID"`
 }
 
-var renameMap map[string]uint64
-
-// GetAstQueryID returns the query ID for AST
-func GetAstQueryID(language, name, group string) (string, error) {
-	if renameMap == nil {
-		return "", fmt.Errorf("rename data not loaded or initialization failed")
-	}
-
-	sourcePath := fmt.Sprintf("queries/%s/%s/%s/%s.cs", language, group, name, name)
-
-	if oldAstID, exists := renameMap[sourcePath]; exists {
-		return strconv.FormatUint(oldAstID, queryIDIntBase), nil
-	}
-
-	queryID, queryIDErr := hash(sourcePath)
-	if queryIDErr != nil {
-		return "", queryIDErr
-	}
-	return strconv.FormatUint(queryID, queryIDIntBase), nil
+// GetSourcePath returns the path of a query's source, which AST query ids are calculated from
+func GetSourcePath(language, group, name string) string {
+	return fmt.Sprintf("queries/%s/%s/%s/%s.cs", language, group, name, name)
 }
 
-func hash(s string) (uint64, error) {
+// HashSourcePath returns the AST query id calculated from a query's source path
+func HashSourcePath(sourcePath string) string {
 	h := fnv.New64()
-	_, err := h.Write([]byte(s))
-	return h.Sum64(), err
+	_, _ = h.Write([]byte(sourcePath))
+	return strconv.FormatUint(h.Sum64(), queryIDIntBase)
 }
 
-// LoadRename attempts to load the rename mapping from the specified source (file path or URL).
-// This function should be called ONCE during application initialization
-func LoadRename(renameSource string) error {
-	var data []byte
-	var err error
-
-	if renameSource == "" {
-		return fmt.Errorf("rename source (file path or URL) cannot be empty")
-	}
-
-	// Check if it's a URL or a local file path
-	if strings.HasPrefix(renameSource, "http://") || strings.HasPrefix(renameSource, "https://") {
-		// Load from URL
-		resp, httpErr := http.Get(renameSource) //nolint:gosec
-		if httpErr != nil {
-			return fmt.Errorf("failed to fetch rename data from URL '%s': %w", renameSource, httpErr)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("failed to fetch rename data from URL '%s': HTTP %d, Body: %s", renameSource, resp.StatusCode, string(bodyBytes))
-		}
-
-		data, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response body from URL '%s': %w", renameSource, err)
-		}
-	} else {
-		// Load from local file
-		data, err = os.ReadFile(renameSource)
-		if err != nil {
-			return fmt.Errorf("failed to read rename file '%s': %w", renameSource, err)
-		}
-	}
-
+// ParseRenames parses the query renames in data, read from the specified source
+func ParseRenames(data []byte, renameSource string) ([]RenameEntry, error) {
 	var entries []RenameEntry
 	if err := json.Unmarshal(data, &entries); err != nil {
-		return fmt.Errorf("failed to parse rename JSON data from source '%s': %w", renameSource, err)
-	}
-
-	currentRenameMap := make(map[string]uint64)
-	for _, entry := range entries {
-		currentRenameMap[entry.NewPath] = entry.OldPathAstID
+		return nil, fmt.Errorf("failed to parse rename JSON data from source '%s': %w", renameSource, err)
 	}
 
-	renameMap = currentRenameMap
-
-	return nil
+	return entries, nil
 }
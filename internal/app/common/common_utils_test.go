@@ -1,6 +1,7 @@
 package common
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -12,12 +13,7 @@ type AstQueryIDTest struct {
 	Language, Group, Name, Expected string
 }
 
-func TestGetAstQueryID(t *testing.T) {
-	testRenameFile := filepath.Join("..", "..", "..", "data", "renames.json")
-
-	err := LoadRename(testRenameFile)
-	require.NoError(t, err, "Failed to load rename map for test from ../../../data/renames.json")
-
+func TestHashSourcePath(t *testing.T) {
 	astQueryIDTests := []AstQueryIDTest{
 		{"Kotlin", "Kotlin_High_Risk", "Code_Injection", "15158446363146771540"},
 		{"CSharp", "General", "Find_SQL_Injection_Evasion_Attack", "8984835614866342550"},
@@ -25,8 +21,26 @@ func TestGetAstQueryID(t *testing.T) {
 	}
 
 	for _, test := range astQueryIDTests {
-		result, err := GetAstQueryID(test.Language, test.Name, test.Group)
-		assert.NoError(t, err)
+		result := HashSourcePath(GetSourcePath(test.Language, test.Group, test.Name))
 		assert.Equal(t, test.Expected, result)
 	}
 }
+
+func TestParseRenames(t *testing.T) {
+	t.Run("parses renames", func(t *testing.T) {
+		content, readErr := os.ReadFile(filepath.Join("..", "..", "..", "data", "renames.json"))
+		require.NoError(t, readErr)
+
+		result, err := ParseRenames(content, "renames.json")
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, result)
+		assert.Equal(t, "queries/Java/Java_Medium_Threat/Trust_Boundary_Violation/Trust_Boundary_Violation.cs", result[0].OldPath)
+		assert.Equal(t, uint64(5726913611564465136), result[0].OldPathAstID)
+	})
+	t.Run("fails if data is not valid", func(t *testing.T) {
+		_, err := ParseRenames([]byte("<html>Proxy error</html>"), "https://example.com/renames.json")
+
+		assert.ErrorContains(t, err, "failed to parse rename JSON data from source 'https://example.com/renames.json'")
+	})
+}
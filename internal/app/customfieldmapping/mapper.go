@@ -0,0 +1,166 @@
+package customfieldmapping
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// LowercaseTransform converts values to lower case
+	LowercaseTransform = "lowercase"
+	// UppercaseTransform converts values to upper case
+	UppercaseTransform = "uppercase"
+	// TrimTransform removes leading and trailing spaces from values
+	TrimTransform = "trim"
+)
+
+// Mapper maps SAST custom fields to CxOne project tags.
+// Fields no rule applies to become tags with the field name as key and the field value as value.
+type Mapper struct {
+	rules []Rule
+}
+
+// NewMapper creates a mapper applying the given rules
+func NewMapper(rules []Rule) *Mapper {
+	return &Mapper{rules: rules}
+}
+
+// Load creates a mapper with the rules in the custom field mapping file, or without rules if no file is given
+func Load(fileName string) (*Mapper, error) {
+	if fileName == "" {
+		return NewMapper(nil), nil
+	}
+	data, ioErr := os.ReadFile(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read custom field mapping file")
+	}
+	var mapSource MapSource
+	if jsonErr := json.Unmarshal(data, &mapSource); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse custom field mapping file")
+	}
+	if validateErr := validate(mapSource.Rules); validateErr != nil {
+		return nil, validateErr
+	}
+	return NewMapper(mapSource.Rules), nil
+}
+
+func validate(rules []Rule) error {
+	fields := map[string]bool{}
+	tagKeys := map[string]bool{}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Field) == "" {
+			return errors.Errorf("custom field mapping rule %d must have a field", i+1)
+		}
+		field := strings.ToLower(rule.Field)
+		if fields[field] {
+			return errors.Errorf("custom field mapping rule %d repeats field %s", i+1, rule.Field)
+		}
+		fields[field] = true
+		switch rule.Transform {
+		case "", LowercaseTransform, UppercaseTransform, TrimTransform:
+		default:
+			return errors.Errorf("custom field mapping rule %d has unknown transform %s", i+1, rule.Transform)
+		}
+		if rule.Drop {
+			continue
+		}
+		tagKey := rule.TagKey
+		if tagKey == "" {
+			tagKey = rule.Field
+		}
+		if tagKeys[tagKey] {
+			return errors.Errorf("custom field mapping rule %d repeats tag key %s", i+1, tagKey)
+		}
+		tagKeys[tagKey] = true
+	}
+	return nil
+}
+
+// GetTags returns the tags of the given custom field values.
+// If an unmapped field is named like the tag key of a mapped field, the mapped field wins and a warning is logged.
+func (m *Mapper) GetTags(fields []*rest.CustomField) map[string]string {
+	tags := map[string]string{}
+	tagFields := map[string]string{}
+	mappedTags := map[string]bool{}
+	for _, field := range fields {
+		rule := m.getRule(field.FieldName)
+		if rule != nil && rule.Drop {
+			continue
+		}
+		tagKey, tagValue := field.FieldName, field.FieldValue
+		if rule != nil {
+			tagKey, tagValue = getTagKey(rule), transform(field.FieldValue, rule)
+		}
+		if otherField, exists := tagFields[tagKey]; exists {
+			log.Warn().
+				Str("tagKey", tagKey).
+				Strs("fields", []string{otherField, field.FieldName}).
+				Msg("custom fields are exported as the same project tag, the mapped field is kept")
+			if rule == nil || mappedTags[tagKey] {
+				continue
+			}
+		}
+		tags[tagKey] = tagValue
+		tagFields[tagKey] = field.FieldName
+		mappedTags[tagKey] = rule != nil
+	}
+	return tags
+}
+
+// GetDefinitions returns the custom field definitions with the tag each field is exported as
+func (m *Mapper) GetDefinitions(customFields []*rest.CustomFieldDefinition) []*Definition {
+	out := make([]*Definition, 0, len(customFields))
+	for _, customField := range customFields {
+		definition := &Definition{
+			ID:          customField.ID,
+			Name:        customField.Name,
+			IsMandatory: customField.IsMandatory,
+			TagKey:      customField.Name,
+		}
+		if rule := m.getRule(customField.Name); rule != nil {
+			definition.Dropped = rule.Drop
+			definition.TagKey = getTagKey(rule)
+			if rule.Drop {
+				definition.TagKey = ""
+			}
+		}
+		out = append(out, definition)
+	}
+	return out
+}
+
+func (m *Mapper) getRule(field string) *Rule {
+	for i := range m.rules {
+		if strings.EqualFold(m.rules[i].Field, field) {
+			return &m.rules[i]
+		}
+	}
+	return nil
+}
+
+func getTagKey(rule *Rule) string {
+	if rule.TagKey != "" {
+		return rule.TagKey
+	}
+	return rule.Field
+}
+
+func transform(value string, rule *Rule) string {
+	switch rule.Transform {
+	case LowercaseTransform:
+		value = strings.ToLower(value)
+	case UppercaseTransform:
+		value = strings.ToUpper(value)
+	case TrimTransform:
+		value = strings.TrimSpace(value)
+	}
+	if newValue, exists := rule.Values[value]; exists {
+		return newValue
+	}
+	return value
+}
@@ -0,0 +1,113 @@
+package customfieldmapping
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "loads rules",
+			content: `{"rules": [{"field": "Owner", "tagKey": "owner", "transform": "lowercase"},
+				{"field": "Internal", "drop": true}]}`,
+		},
+		{name: "rule without field", content: `{"rules": [{"tagKey": "owner"}]}`,
+			expectedError: "custom field mapping rule 1 must have a field"},
+		{name: "repeated field", content: `{"rules": [{"field": "Owner"}, {"field": "owner", "tagKey": "team"}]}`,
+			expectedError: "custom field mapping rule 2 repeats field owner"},
+		{
+			name:          "repeated tag key",
+			content:       `{"rules": [{"field": "Owner", "tagKey": "team"}, {"field": "Team"}, {"field": "Group", "tagKey": "team"}]}`,
+			expectedError: "custom field mapping rule 3 repeats tag key team",
+		},
+		{name: "unknown transform", content: `{"rules": [{"field": "Owner", "transform": "reverse"}]}`,
+			expectedError: "custom field mapping rule 1 has unknown transform reverse"},
+		{name: "invalid json", content: `{`, expectedError: "could not parse custom field mapping file: unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "custom_field_mapping.json")
+			require.NoError(t, os.WriteFile(fileName, []byte(test.content), 0600))
+
+			result, err := Load(fileName)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.rules, 2)
+			}
+		})
+	}
+	t.Run("without file", func(t *testing.T) {
+		result, err := Load("")
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.rules)
+	})
+}
+
+func TestMapper_GetTags(t *testing.T) {
+	mapper := NewMapper([]Rule{
+		{Field: "owner", TagKey: "team", Transform: LowercaseTransform},
+		{Field: "Internal", Drop: true},
+		{Field: "Criticality", Transform: TrimTransform, Values: map[string]string{"1": "high", "2": "low"}},
+	})
+	fields := []*rest.CustomField{
+		{FieldName: "Owner", FieldValue: "Payments"},
+		{FieldName: "Internal", FieldValue: "yes"},
+		{FieldName: "Criticality", FieldValue: " 1 "},
+		{FieldName: "Region", FieldValue: "EU"},
+	}
+
+	result := mapper.GetTags(fields)
+
+	assert.Equal(t, map[string]string{"team": "payments", "Criticality": "high", "Region": "EU"}, result)
+}
+
+func TestMapper_GetTagsCollision(t *testing.T) {
+	mapper := NewMapper([]Rule{{Field: "owner", TagKey: "team"}})
+	expected := map[string]string{"team": "Payments"}
+	t.Run("keeps mapped field if unmapped field comes later", func(t *testing.T) {
+		fields := []*rest.CustomField{{FieldName: "Owner", FieldValue: "Payments"}, {FieldName: "team", FieldValue: "Billing"}}
+
+		result := mapper.GetTags(fields)
+
+		assert.Equal(t, expected, result)
+	})
+	t.Run("keeps mapped field if unmapped field comes first", func(t *testing.T) {
+		fields := []*rest.CustomField{{FieldName: "team", FieldValue: "Billing"}, {FieldName: "Owner", FieldValue: "Payments"}}
+
+		result := mapper.GetTags(fields)
+
+		assert.Equal(t, expected, result)
+	})
+}
+
+func TestMapper_GetDefinitions(t *testing.T) {
+	mapper := NewMapper([]Rule{{Field: "owner", TagKey: "team"}, {Field: "Internal", Drop: true}})
+	customFields := []*rest.CustomFieldDefinition{
+		{ID: 1, Name: "Owner", IsMandatory: true},
+		{ID: 2, Name: "Internal"},
+		{ID: 3, Name: "Region"},
+	}
+
+	result := mapper.GetDefinitions(customFields)
+
+	expected := []*Definition{
+		{ID: 1, Name: "Owner", IsMandatory: true, TagKey: "team"},
+		{ID: 2, Name: "Internal", Dropped: true},
+		{ID: 3, Name: "Region", TagKey: "Region"},
+	}
+	assert.Equal(t, expected, result)
+}
@@ -0,0 +1,29 @@
+package customfieldmapping
+
+type (
+	// Rule maps a SAST custom field to a CxOne project tag
+	Rule struct {
+		Field string `json:"field"`
+		// TagKey is the key of the tag, the field name if not set
+		TagKey string `json:"tagKey,omitempty"`
+		// Drop excludes the field from the tags
+		Drop bool `json:"drop,omitempty"`
+		// Transform changes the field value, it can be lowercase, uppercase or trim
+		Transform string `json:"transform,omitempty"`
+		// Values replaces field values, after the transformation
+		Values map[string]string `json:"values,omitempty"`
+	}
+
+	MapSource struct {
+		Rules []Rule `json:"rules"`
+	}
+
+	// Definition is a SAST custom field and the CxOne tag it's exported as
+	Definition struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		IsMandatory bool   `json:"isMandatory"`
+		TagKey      string `json:"tagKey,omitempty"`
+		Dropped     bool   `json:"dropped"`
+	}
+)
@@ -0,0 +1,162 @@
+package customquery
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+)
+
+const (
+	// SourceExtension is the extension of the query source files
+	SourceExtension = ".cs"
+
+	productPackageType = "Cx"
+	teamPackageType    = "Team"
+	projectPackageType = "Project"
+)
+
+// unsafePathChars are replaced in the path of query source files
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+type (
+	// Query describes a custom query and the file with its source code
+	Query struct {
+		SastQueryID      string     `json:"sastQueryId"`
+		Name             string     `json:"name"`
+		Language         string     `json:"language"`
+		Group            string     `json:"group"`
+		PackageType      string     `json:"packageType"`
+		PackageFullName  string     `json:"packageFullName"`
+		OwningTeamID     int        `json:"owningTeamId,omitempty"`
+		ProjectID        int        `json:"projectId,omitempty"`
+		QueryVersionCode int        `json:"queryVersionCode"`
+		Severity         int        `json:"severity"`
+		Cwe              int        `json:"cwe"`
+		Categories       []Category `json:"categories"`
+		IsExecutable     bool       `json:"isExecutable"`
+		IsEncrypted      bool       `json:"isEncrypted"`
+		Status           string     `json:"status"`
+		Type             string     `json:"type"`
+		// File is the path of the source file in the export, empty for encrypted queries
+		File   string `json:"file,omitempty"`
+		Source string `json:"-"`
+	}
+
+	// Category is a category of a custom query
+	Category struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+)
+
+// GetQueries returns the custom queries of the query groups, with their source files under dir.
+// Team and project packages include the owning team or project id in the level folder,
+// so overrides of the same query at different teams or projects don't overwrite each other.
+// Queries whose names sanitize to the same file get their SAST query id appended to it.
+func GetQueries(groups []soap.CxWSQueryGroup, dir string) []*Query {
+	queries := []*Query{}
+	for i := range groups {
+		group := groups[i]
+		if group.PackageType == productPackageType {
+			continue
+		}
+		for j := range group.Queries.CxWSQuery {
+			query := group.Queries.CxWSQuery[j]
+			customQuery := &Query{
+				SastQueryID:      strconv.Itoa(query.QueryID),
+				Name:             query.Name,
+				Language:         group.LanguageName,
+				Group:            group.Name,
+				PackageType:      group.PackageType,
+				PackageFullName:  group.PackageFullName,
+				OwningTeamID:     group.OwningTeam,
+				ProjectID:        group.ProjectID,
+				QueryVersionCode: query.QueryVersionCode,
+				Severity:         query.Severity,
+				Cwe:              query.Cwe,
+				Categories:       getCategories(query.Categories),
+				IsExecutable:     query.IsExecutable,
+				IsEncrypted:      query.IsEncrypted || group.IsEncrypted,
+				Status:           query.Status,
+				Type:             query.Type,
+			}
+			if !customQuery.IsEncrypted {
+				customQuery.File = path.Join(dir, getLevelFolder(&group), sanitize(group.LanguageName), sanitize(group.Name),
+					sanitize(query.Name)+SourceExtension)
+				customQuery.Source = query.Source
+			}
+			queries = append(queries, customQuery)
+		}
+	}
+	dedupeFiles(queries)
+	sort.SliceStable(queries, func(i, j int) bool {
+		return queries[i].File < queries[j].File
+	})
+	return queries
+}
+
+// dedupeFiles appends the SAST query id to the files shared by more than one query
+func dedupeFiles(queries []*Query) {
+	fileCount := map[string]int{}
+	for _, query := range queries {
+		if query.File != "" {
+			fileCount[query.File]++
+		}
+	}
+	for _, query := range queries {
+		if fileCount[query.File] > 1 {
+			query.File = fmt.Sprintf("%s_%s%s", strings.TrimSuffix(query.File, SourceExtension), query.SastQueryID, SourceExtension)
+		}
+	}
+}
+
+// GetDirs returns the folders holding the source files, parents first
+func GetDirs(queries []*Query) []string {
+	dirSet := map[string]bool{}
+	for _, query := range queries {
+		if query.File == "" {
+			continue
+		}
+		for dir := path.Dir(query.File); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dirSet[dir] = true
+		}
+	}
+	dirs := make([]string, 0, len(dirSet))
+	for dir := range dirSet {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func getLevelFolder(group *soap.CxWSQueryGroup) string {
+	switch group.PackageType {
+	case teamPackageType:
+		return fmt.Sprintf("%s_%d", teamPackageType, group.OwningTeam)
+	case projectPackageType:
+		return fmt.Sprintf("%s_%d", projectPackageType, group.ProjectID)
+	default:
+		return sanitize(group.PackageType)
+	}
+}
+
+func getCategories(categories soap.Categories) []Category {
+	output := make([]Category, 0, len(categories.CxQueryCategory))
+	for _, category := range categories.CxQueryCategory {
+		output = append(output, Category{ID: category.ID, Name: category.CategoryName, Type: category.CategoryType.Name})
+	}
+	return output
+}
+
+func sanitize(name string) string {
+	if name == "" {
+		return "_"
+	}
+	return unsafePathChars.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,95 @@
+package customquery
+
+import (
+	"encoding/xml"
+	"os"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQueries(t *testing.T) {
+	t.Run("reads queries from SAST query collection", func(t *testing.T) {
+		var queryResp soap.GetQueryCollectionResponse
+		data, ioErr := os.ReadFile("../../../test/data/queries/custom_queries.xml")
+		require.NoError(t, ioErr)
+		require.NoError(t, xml.Unmarshal(data, &queryResp))
+
+		result := GetQueries(queryResp.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup, "queries")
+
+		require.Len(t, result, 1)
+		assert.Equal(t, "queries/Corporate/CSharp/CSharp_High_Risk/SQL_Injection.cs", result[0].File)
+		assert.Equal(t, "result = base.SQL_Injection();", result[0].Source)
+		assert.Equal(t, "100000", result[0].SastQueryID)
+		assert.Equal(t, "CSharp:Corp:CSharp_High_Risk", result[0].PackageFullName)
+		assert.Equal(t, 89, result[0].Cwe)
+		assert.Equal(t, 3, result[0].Severity)
+		assert.True(t, result[0].IsExecutable)
+		assert.False(t, result[0].IsEncrypted)
+		assert.Len(t, result[0].Categories, 6)
+		assert.Equal(t, Category{ID: 13, Name: "A1-Injection", Type: "OWASP Top 10 2013"}, result[0].Categories[1])
+	})
+	t.Run("separates team and project overrides and skips source of encrypted queries", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{
+			{
+				Name: "Java_High_Risk", LanguageName: "Java", PackageType: "Project", ProjectID: 7,
+				Queries: soap.Queries{CxWSQuery: []soap.CxWSQuery{{QueryID: 3, Name: "SQL_Injection", Source: "project"}}},
+			},
+			{
+				Name: "Java_High_Risk", LanguageName: "Java", PackageType: "Team", OwningTeam: 2,
+				Queries: soap.Queries{CxWSQuery: []soap.CxWSQuery{
+					{QueryID: 2, Name: "SQL Injection/v2", Source: "team"},
+					{QueryID: 4, Name: "Secret", Source: "encrypted", IsEncrypted: true},
+				}},
+			},
+			{
+				Name: "Java_High_Risk", LanguageName: "Java", PackageType: "Cx",
+				Queries: soap.Queries{CxWSQuery: []soap.CxWSQuery{{QueryID: 1, Name: "SQL_Injection", Source: "product"}}},
+			},
+		}
+
+		result := GetQueries(groups, "queries")
+
+		require.Len(t, result, 3)
+		assert.Equal(t, "4", result[0].SastQueryID)
+		assert.Empty(t, result[0].File)
+		assert.Empty(t, result[0].Source)
+		assert.True(t, result[0].IsEncrypted)
+		assert.Equal(t, "queries/Project_7/Java/Java_High_Risk/SQL_Injection.cs", result[1].File)
+		assert.Equal(t, "queries/Team_2/Java/Java_High_Risk/SQL_Injection_v2.cs", result[2].File)
+		assert.Equal(t, 2, result[2].OwningTeamID)
+		expectedDirs := []string{
+			"queries",
+			"queries/Project_7",
+			"queries/Project_7/Java",
+			"queries/Project_7/Java/Java_High_Risk",
+			"queries/Team_2",
+			"queries/Team_2/Java",
+			"queries/Team_2/Java/Java_High_Risk",
+		}
+		assert.Equal(t, expectedDirs, GetDirs(result))
+	})
+	t.Run("appends the query id to files shared by more than one query", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{
+			{
+				Name: "Java_High_Risk", LanguageName: "Java", PackageType: "Corporate",
+				Queries: soap.Queries{CxWSQuery: []soap.CxWSQuery{
+					{QueryID: 5, Name: "SQL Injection", Source: "space"},
+					{QueryID: 6, Name: "SQL_Injection", Source: "underscore"},
+					{QueryID: 7, Name: "XSS", Source: "unique"},
+				}},
+			},
+		}
+
+		result := GetQueries(groups, "queries")
+
+		require.Len(t, result, 3)
+		assert.Equal(t, "queries/Corporate/Java/Java_High_Risk/SQL_Injection_5.cs", result[0].File)
+		assert.Equal(t, "space", result[0].Source)
+		assert.Equal(t, "queries/Corporate/Java/Java_High_Risk/SQL_Injection_6.cs", result[1].File)
+		assert.Equal(t, "underscore", result[1].Source)
+		assert.Equal(t, "queries/Corporate/Java/Java_High_Risk/XSS.cs", result[2].File)
+	})
+}
@@ -0,0 +1,171 @@
+package databundle
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/checkmarxDev/ast-sast-export/data"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EmbeddedSource is the source name of data taken from the bundle in use,
+	// which is the data embedded in the exporter unless a data bundle is given
+	EmbeddedSource = "embedded"
+
+	// QueryMappingFileName is the bundle file with the query mapping
+	QueryMappingFileName = "mapping.json"
+	// QueryRenamingFileName is the bundle file with the query renames
+	QueryRenamingFileName = "renames.json"
+	// EngineConfigMappingFileName is the bundle file with the engine configuration keys
+	EngineConfigMappingFileName = "EngineConfigMapping.json"
+
+	versionPrefix = "sha256:"
+	versionLength = 12
+	fileMode      = 0600
+)
+
+// Bundle is the data the exporter needs besides SAST, so it can run without internet access
+type Bundle struct {
+	Version   string            `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Sources   map[string]string `json:"sources"`
+	Files     map[string][]byte `json:"files"`
+	Signature []byte            `json:"signature,omitempty"`
+	// Origin describes where the bundle was loaded from
+	Origin string `json:"-"`
+}
+
+// GetFileNames returns the files every bundle has
+func GetFileNames() []string {
+	return []string{QueryMappingFileName, QueryRenamingFileName, EngineConfigMappingFileName}
+}
+
+// GetEmbedded returns the bundle with the data embedded in the exporter
+func GetEmbedded() *Bundle {
+	files := map[string][]byte{
+		QueryMappingFileName:        data.QueryMapping,
+		QueryRenamingFileName:       data.QueryRenaming,
+		EngineConfigMappingFileName: data.EngineConfigMapping,
+	}
+	version := GetVersion(files)
+	return &Bundle{
+		Version: version,
+		Sources: map[string]string{
+			QueryMappingFileName:        EmbeddedSource,
+			QueryRenamingFileName:       EmbeddedSource,
+			EngineConfigMappingFileName: EmbeddedSource,
+		},
+		Files:  files,
+		Origin: fmt.Sprintf("embedded data %s", version),
+	}
+}
+
+// New creates an unsigned bundle with the given files and the sources they were read from
+func New(files map[string][]byte, sources map[string]string, createdAt time.Time) *Bundle {
+	return &Bundle{
+		Version:   GetVersion(files),
+		CreatedAt: createdAt,
+		Sources:   sources,
+		Files:     files,
+	}
+}
+
+// GetVersion returns the version of bundle files, which changes whenever their content does
+func GetVersion(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fileHash := sha256.Sum256(files[name])
+		_, _ = fmt.Fprintf(h, "%s %x\n", name, fileHash)
+	}
+	return versionPrefix + hex.EncodeToString(h.Sum(nil))[:versionLength]
+}
+
+// GetFile returns the content of a bundle file
+func (b *Bundle) GetFile(name string) []byte {
+	return b.Files[name]
+}
+
+// Sign signs the bundle with the given private key
+func (b *Bundle) Sign(privateKey ed25519.PrivateKey) error {
+	payload, err := b.getSignedPayload()
+	if err != nil {
+		return err
+	}
+	b.Signature = ed25519.Sign(privateKey, payload)
+	return nil
+}
+
+// Verify checks the bundle was signed with the private key of the given public key and wasn't changed since
+func (b *Bundle) Verify(publicKey ed25519.PublicKey) error {
+	if len(b.Signature) == 0 {
+		return errors.New("data bundle is not signed")
+	}
+	payload, err := b.getSignedPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, b.Signature) {
+		return errors.New("data bundle signature is not valid")
+	}
+	if version := GetVersion(b.Files); version != b.Version {
+		return errors.Errorf("data bundle version %s doesn't match its files, expected %s", b.Version, version)
+	}
+	return nil
+}
+
+// Write writes the bundle to a file
+func (b *Bundle) Write(fileName string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, content, fileMode)
+}
+
+// getSignedPayload returns the bundle without its signature, which is what gets signed
+func (b *Bundle) getSignedPayload() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
+
+// Load reads a data bundle from a file and verifies its signature with the public key in publicKeyFile
+func Load(fileName, publicKeyFile string) (*Bundle, error) {
+	if publicKeyFile == "" {
+		return nil, errors.New("a public key is required to verify the data bundle")
+	}
+	publicKey, keyErr := ReadPublicKey(publicKeyFile)
+	if keyErr != nil {
+		return nil, keyErr
+	}
+	content, readErr := os.ReadFile(fileName)
+	if readErr != nil {
+		return nil, errors.Wrap(readErr, "could not read data bundle file")
+	}
+	var bundle Bundle
+	if jsonErr := json.Unmarshal(content, &bundle); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse data bundle file")
+	}
+	if verifyErr := bundle.Verify(publicKey); verifyErr != nil {
+		return nil, verifyErr
+	}
+	for _, name := range GetFileNames() {
+		if len(bundle.Files[name]) == 0 {
+			return nil, errors.Errorf("data bundle is missing %s", name)
+		}
+	}
+	bundle.Origin = fmt.Sprintf("data bundle %s %s", fileName, bundle.Version)
+	return &bundle, nil
+}
@@ -0,0 +1,143 @@
+package databundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/checkmarxDev/ast-sast-export/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestBundle(t *testing.T, dir string, files map[string][]byte) (bundleFile, publicKeyFile string) {
+	privateKey, err := GenerateKey(filepath.Join(dir, "key.pem"), filepath.Join(dir, "key.pub"))
+	require.NoError(t, err)
+	bundle := New(files, map[string]string{QueryMappingFileName: "mapping.json"}, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, bundle.Sign(privateKey))
+	bundleFile = filepath.Join(dir, "bundle.json")
+	require.NoError(t, bundle.Write(bundleFile))
+	return bundleFile, filepath.Join(dir, "key.pub")
+}
+
+func getTestFiles() map[string][]byte {
+	return map[string][]byte{
+		QueryMappingFileName:        []byte(`{"mappings": []}`),
+		QueryRenamingFileName:       []byte(`[]`),
+		EngineConfigMappingFileName: []byte(`{}`),
+	}
+}
+
+func TestGetEmbedded(t *testing.T) {
+	bundle := GetEmbedded()
+
+	assert.Equal(t, data.QueryMapping, bundle.GetFile(QueryMappingFileName))
+	assert.Equal(t, data.QueryRenaming, bundle.GetFile(QueryRenamingFileName))
+	assert.Equal(t, data.EngineConfigMapping, bundle.GetFile(EngineConfigMappingFileName))
+	assert.Regexp(t, `^sha256:[0-9a-f]{12}$`, bundle.Version)
+	assert.Equal(t, "embedded data "+bundle.Version, bundle.Origin)
+}
+
+func TestGetVersion(t *testing.T) {
+	files := getTestFiles()
+	version := GetVersion(files)
+
+	files[QueryRenamingFileName] = []byte(`[ ]`)
+
+	assert.Equal(t, "sha256:79251edbef34", version)
+	assert.NotEqual(t, version, GetVersion(files))
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("loads signed bundle", func(t *testing.T) {
+		dir := t.TempDir()
+		bundleFile, publicKeyFile := writeTestBundle(t, dir, getTestFiles())
+
+		result, err := Load(bundleFile, publicKeyFile)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(`[]`), result.GetFile(QueryRenamingFileName))
+		assert.Equal(t, GetVersion(getTestFiles()), result.Version)
+		assert.Equal(t, "mapping.json", result.Sources[QueryMappingFileName])
+		assert.Equal(t, "data bundle "+bundleFile+" "+result.Version, result.Origin)
+	})
+	t.Run("fails without public key", func(t *testing.T) {
+		_, err := Load("bundle.json", "")
+
+		assert.EqualError(t, err, "a public key is required to verify the data bundle")
+	})
+	t.Run("fails if bundle was signed with another key", func(t *testing.T) {
+		dir := t.TempDir()
+		bundleFile, _ := writeTestBundle(t, dir, getTestFiles())
+		_, keyErr := GenerateKey(filepath.Join(dir, "other.pem"), filepath.Join(dir, "other.pub"))
+		require.NoError(t, keyErr)
+
+		_, err := Load(bundleFile, filepath.Join(dir, "other.pub"))
+
+		assert.EqualError(t, err, "data bundle signature is not valid")
+	})
+	t.Run("fails if bundle was changed after signing", func(t *testing.T) {
+		dir := t.TempDir()
+		bundleFile, publicKeyFile := writeTestBundle(t, dir, getTestFiles())
+		bundle, loadErr := Load(bundleFile, publicKeyFile)
+		require.NoError(t, loadErr)
+		bundle.Files[QueryRenamingFileName] = []byte(`[{}]`)
+		require.NoError(t, bundle.Write(bundleFile))
+
+		_, err := Load(bundleFile, publicKeyFile)
+
+		assert.EqualError(t, err, "data bundle signature is not valid")
+	})
+	t.Run("fails if bundle is not signed", func(t *testing.T) {
+		dir := t.TempDir()
+		_, publicKeyFile := writeTestBundle(t, dir, getTestFiles())
+		bundleFile := filepath.Join(dir, "unsigned.json")
+		require.NoError(t, New(getTestFiles(), nil, time.Now()).Write(bundleFile))
+
+		_, err := Load(bundleFile, publicKeyFile)
+
+		assert.EqualError(t, err, "data bundle is not signed")
+	})
+	t.Run("fails if bundle is missing a file", func(t *testing.T) {
+		dir := t.TempDir()
+		files := getTestFiles()
+		delete(files, EngineConfigMappingFileName)
+		bundleFile, publicKeyFile := writeTestBundle(t, dir, files)
+
+		_, err := Load(bundleFile, publicKeyFile)
+
+		assert.EqualError(t, err, "data bundle is missing EngineConfigMapping.json")
+	})
+	t.Run("fails if bundle file is not valid", func(t *testing.T) {
+		dir := t.TempDir()
+		_, publicKeyFile := writeTestBundle(t, dir, getTestFiles())
+		bundleFile := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(bundleFile, []byte(`{`), 0600))
+
+		_, err := Load(bundleFile, publicKeyFile)
+
+		assert.EqualError(t, err, "could not parse data bundle file: unexpected end of JSON input")
+	})
+	t.Run("fails if public key file is not valid", func(t *testing.T) {
+		dir := t.TempDir()
+		bundleFile, _ := writeTestBundle(t, dir, getTestFiles())
+
+		_, err := Load(bundleFile, filepath.Join(dir, "key.pem"))
+
+		assert.EqualError(t, err, "public key file must have a PEM encoded PUBLIC KEY")
+	})
+}
+
+func TestReadPrivateKey(t *testing.T) {
+	dir := t.TempDir()
+	privateKey, err := GenerateKey(filepath.Join(dir, "key.pem"), filepath.Join(dir, "key.pub"))
+	require.NoError(t, err)
+
+	result, readErr := ReadPrivateKey(filepath.Join(dir, "key.pem"))
+
+	assert.NoError(t, readErr)
+	assert.Equal(t, privateKey, result)
+	_, missingErr := ReadPrivateKey(filepath.Join(dir, "missing.pem"))
+	assert.ErrorContains(t, missingErr, "could not read signing key file")
+}
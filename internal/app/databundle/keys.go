@@ -0,0 +1,89 @@
+package databundle
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	privateKeyBlockType = "PRIVATE KEY"
+	publicKeyBlockType  = "PUBLIC KEY"
+	publicKeyFileMode   = 0644
+)
+
+// GenerateKey creates an Ed25519 key pair and writes it to PEM files,
+// the same format as openssl genpkey -algorithm ed25519 and openssl pkey -pubout
+func GenerateKey(privateKeyFile, publicKeyFile string) (ed25519.PrivateKey, error) {
+	publicKey, privateKey, generateErr := ed25519.GenerateKey(rand.Reader)
+	if generateErr != nil {
+		return nil, errors.Wrap(generateErr, "could not generate signing key")
+	}
+	privateKeyBytes, privateKeyErr := x509.MarshalPKCS8PrivateKey(privateKey)
+	if privateKeyErr != nil {
+		return nil, errors.Wrap(privateKeyErr, "could not encode signing key")
+	}
+	publicKeyBytes, publicKeyErr := x509.MarshalPKIXPublicKey(publicKey)
+	if publicKeyErr != nil {
+		return nil, errors.Wrap(publicKeyErr, "could not encode public key")
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: privateKeyBlockType, Bytes: privateKeyBytes})
+	if writeErr := os.WriteFile(privateKeyFile, privateKeyPEM, fileMode); writeErr != nil {
+		return nil, errors.Wrap(writeErr, "could not write signing key file")
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: publicKeyBlockType, Bytes: publicKeyBytes})
+	if writeErr := os.WriteFile(publicKeyFile, publicKeyPEM, publicKeyFileMode); writeErr != nil {
+		return nil, errors.Wrap(writeErr, "could not write public key file")
+	}
+	return privateKey, nil
+}
+
+// ReadPrivateKey reads an Ed25519 private key from a PEM file
+func ReadPrivateKey(fileName string) (ed25519.PrivateKey, error) {
+	block, readErr := readPEM(fileName, privateKeyBlockType, "signing key")
+	if readErr != nil {
+		return nil, readErr
+	}
+	key, parseErr := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if parseErr != nil {
+		return nil, errors.Wrap(parseErr, "could not parse signing key file")
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key must be an Ed25519 key")
+	}
+	return privateKey, nil
+}
+
+// ReadPublicKey reads an Ed25519 public key from a PEM file
+func ReadPublicKey(fileName string) (ed25519.PublicKey, error) {
+	block, readErr := readPEM(fileName, publicKeyBlockType, "public key")
+	if readErr != nil {
+		return nil, readErr
+	}
+	key, parseErr := x509.ParsePKIXPublicKey(block.Bytes)
+	if parseErr != nil {
+		return nil, errors.Wrap(parseErr, "could not parse public key file")
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("public key must be an Ed25519 key")
+	}
+	return publicKey, nil
+}
+
+func readPEM(fileName, blockType, description string) (*pem.Block, error) {
+	content, readErr := os.ReadFile(fileName)
+	if readErr != nil {
+		return nil, errors.Wrapf(readErr, "could not read %s file", description)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != blockType {
+		return nil, errors.Errorf("%s file must have a PEM encoded %s", description, blockType)
+	}
+	return block, nil
+}
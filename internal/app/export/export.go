@@ -2,6 +2,7 @@ package export
 
 import (
 	"archive/zip"
+	"bufio"
 	"compress/flate"
 	"encoding/base64"
 	"encoding/json"
@@ -39,8 +40,14 @@ const (
 	TeamsFileName = "teams.json"
 	// ProjectsFileName projects file
 	ProjectsFileName = "projects.json"
+	// CustomFieldsFileName custom field definitions and the project tags they are exported as
+	CustomFieldsFileName = "custom_fields.json"
 	// QueriesFileName queries file
 	QueriesFileName = "queries.xml"
+	// QueriesDirName directory with the source code of each custom query
+	QueriesDirName = "queries"
+	// QueriesIndexFileName metadata of each custom query and the path of its source file
+	QueriesIndexFileName = "queries_index.json"
 	// PresetsDirName presets directory name
 	PresetsDirName = "presets"
 	// PresetsFileName presets file
@@ -49,6 +56,32 @@ const (
 	InstallationFileName = "installation.json"
 	// ResultsMappingFileName file
 	ResultsMappingFileName = "results_mapping.csv"
+	// ResultsMappingJSONFileName results mapping with the same data as the csv
+	ResultsMappingJSONFileName = "results_mapping.json"
+	// QueryResolutionFileName source of the AST query id of each triaged query
+	QueryResolutionFileName = "query_resolution.csv"
+	// UnmappedTeamsFileName teams not covered by the team mapping
+	UnmappedTeamsFileName = "unmapped_teams.csv"
+	// UserMappingReportFileName users merged or not identified by the user mapping
+	UserMappingReportFileName = "user_mapping_report.csv"
+	// RoleTranslationFileName translation of roles to CxOne roles and permissions
+	RoleTranslationFileName = "role_translation.json"
+	// PresetDiffFileName comparison of presets with the AST preset catalog
+	PresetDiffFileName = "preset_diff.json"
+	// TriageHistoryDirName directory with the triage history of the triaged results of each project
+	TriageHistoryDirName = "triage_history"
+	// ProjectSettingsDirName directory with the settings of each project, e.g. source control and issue tracker
+	ProjectSettingsDirName = "project_settings"
+	// ScanHistoryDirName directory with the scan history of each project
+	ScanHistoryDirName = "scan_history"
+	// BranchTriageDirName directory with the triage of each branched project that differs from its lineage root
+	BranchTriageDirName = "branch_triage"
+	// SystemSettingsFileName system-wide settings, e.g. SMTP, engine servers and authentication providers
+	SystemSettingsFileName = "system_settings.json"
+	// RedactionsFileName fields dropped, masked or hashed by the redaction rules, which have to be re-entered
+	RedactionsFileName = "redactions.json"
+	// StateMappingFileName translation of SAST result states and their permissions to CxOne states
+	StateMappingFileName = "state_mapping.json"
 	// CustomStatesFileName file
 	CustomStatesFileName = "custom_states.xml"
 	// CustomExtensionsFileName file
@@ -57,6 +90,12 @@ const (
 	ProjectExcludeSettingsFileName = "project_filters_exclusions.json"
 	// FlagsFileName name of the file containing CLI flags used
 	FlagsFileName = "cli_flags.json"
+	// QueryMappingConflictsFileName lists the SAST queries assigned different AST query ids by the query mappings
+	QueryMappingConflictsFileName = "query_mapping_conflicts.csv"
+	// DataSourcesFileName lists the source and hash of each data file used by the export
+	DataSourcesFileName = "data_sources.json"
+	// QueryOverridesFileName inheritance map of the custom query overrides and their collisions
+	QueryOverridesFileName = "query_overrides.json"
 	// DateTimeFormat the formal to use for DT
 	DateTimeFormat = "2006-01-02-15-04-05"
 
@@ -68,6 +107,7 @@ const (
 type Exporter interface {
 	AddFile(fileName string, data []byte) error
 	AddFileWithDataSource(fileName string, dataSource func() ([]byte, error)) error
+	AddFileWithWriter(fileName string, write func(writer io.Writer) error) error
 	CreateExportPackage(prefix, outputPath string) (string, string, error)
 	Clean() error
 	GetTmpDir() string
@@ -111,6 +151,33 @@ func (e *Export) AddFileWithDataSource(fileName string, dataSource func() ([]byt
 	return e.AddFile(fileName, content)
 }
 
+// AddFileWithWriter creates the specified file with content written by write, without holding it in memory.
+// The file is discarded if write fails.
+func (e *Export) AddFileWithWriter(fileName string, write func(writer io.Writer) error) error {
+	filePath := path.Join(e.tmpDir, fileName)
+	file, createErr := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePerm)
+	if createErr != nil {
+		return createErr
+	}
+	bufferedWriter := bufio.NewWriter(file)
+	writeErr := write(bufferedWriter)
+	if writeErr == nil {
+		writeErr = bufferedWriter.Flush()
+	}
+	closeErr := file.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		if removeErr := os.Remove(filePath); removeErr != nil {
+			log.Debug().Err(removeErr).Msgf("removing %s", fileName)
+		}
+		return writeErr
+	}
+	e.fileList = append(e.fileList, fileName)
+	return nil
+}
+
 // CreateExportPackage compresses and encrypts all files added so far
 func (e *Export) CreateExportPackage(prefix, outputPath string) (string, string, error) { //nolint:gocritic
 	keyFileName := path.Join(outputPath, CreateExportFileName(prefix, "key", "txt", e.runTime))
@@ -91,6 +91,50 @@ func TestExport_AddFileWithDataSource(t *testing.T) {
 	})
 }
 
+func TestExport_AddFileWithWriter(t *testing.T) {
+	prefix := "cxsast-test-export-add-file-with-writer"
+	runTime := time.Now()
+
+	t.Run("success case", func(t *testing.T) {
+		export, err := CreateExport(prefix, runTime)
+		assert.NoError(t, err)
+		defer func() {
+			closeErr := export.Clean()
+			assert.NoError(t, closeErr)
+		}()
+		write := func(writer io.Writer) error {
+			_, writeErr := io.WriteString(writer, "this is test1")
+			return writeErr
+		}
+		addErr := export.AddFileWithWriter("test1.txt", write)
+		assert.NoError(t, addErr)
+
+		expectedFileList := []string{"test1.txt"}
+		assert.Equal(t, expectedFileList, export.fileList)
+
+		content, ioErr := os.ReadFile(path.Join(export.tmpDir, "test1.txt"))
+		assert.NoError(t, ioErr)
+		assert.Equal(t, "this is test1", string(content))
+	})
+	t.Run("discards file if write fails", func(t *testing.T) {
+		export, err := CreateExport(prefix, runTime)
+		assert.NoError(t, err)
+		defer func() {
+			closeErr := export.Clean()
+			assert.NoError(t, closeErr)
+		}()
+		write := func(writer io.Writer) error {
+			_, _ = io.WriteString(writer, "this is ")
+			return fmt.Errorf("write error")
+		}
+		addErr := export.AddFileWithWriter("test1.txt", write)
+		assert.EqualError(t, addErr, "write error")
+
+		assert.Empty(t, export.fileList)
+		assert.NoFileExists(t, path.Join(export.tmpDir, "test1.txt"))
+	})
+}
+
 func TestExport_CreateExportPackage(t *testing.T) {
 	prefix := "cxsast-test-export-create-export-package"
 	runTime := time.Now()
@@ -19,9 +19,23 @@ const (
 	FiltersOption = "filters"
 	// CustomStates represents Custom States of query results
 	CustomStatesOption = "customStates"
+	// ProjectSettingsOption represents project settings, e.g. source control, schedule and issue tracker
+	ProjectSettingsOption = "settings"
+	// ScansOption represents the scan history of projects
+	ScansOption = "scans"
+	// SystemOption represents system-wide settings, e.g. SMTP, engine servers and authentication providers
+	SystemOption = "system"
 )
 
+// GetOptions returns every export option
 func GetOptions() []string {
+	return []string{UsersOption, TeamsOption, ResultsOption, ProjectsOption, QueriesOption,
+		PresetsOption, EngineConfigurationsOption, FiltersOption, CustomStatesOption, ProjectSettingsOption,
+		ScansOption, SystemOption}
+}
+
+// GetDefaultOptions returns the options exported when none are given, the other options have to be asked for
+func GetDefaultOptions() []string {
 	return []string{UsersOption, TeamsOption, ResultsOption, ProjectsOption, QueriesOption,
 		PresetsOption, EngineConfigurationsOption, FiltersOption, CustomStatesOption}
 }
@@ -1,15 +1,19 @@
 package export
 
 import (
-	"fmt"
-	"regexp"
+	"encoding/xml"
+	"io"
+	"path"
 	"strings"
 
+	"github.com/checkmarxDev/ast-sast-export/internal/app/customfieldmapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/report"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/teammapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/usermapping"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/common"
-
-	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
-
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -21,52 +25,139 @@ const (
 
 type TransformOptions struct {
 	NestedTeams bool
+	// TeamMapper maps teams to groups, flattening or keeping teams as they are according to NestedTeams if not set
+	TeamMapper *teammapping.Mapper
+	// UserMapper consolidates users, and maps user references in reports once users are consolidated
+	UserMapper *usermapping.Mapper
+	// CustomFieldMapper maps project custom fields to tags, fields become tags with the same name if not set
+	CustomFieldMapper *customfieldmapping.Mapper
+}
+
+func (e TransformOptions) getTeamMapper() *teammapping.Mapper {
+	if e.TeamMapper != nil {
+		return e.TeamMapper
+	}
+	return teammapping.NewMapper(nil, e.NestedTeams)
 }
 
-// TransformTeams flattens teams.
+func (e TransformOptions) keepsTeams() bool {
+	return e.NestedTeams && (e.TeamMapper == nil || !e.TeamMapper.HasRules())
+}
+
+// TransformTeams maps teams to groups, flattening them unless nested teams are kept.
+// Teams merged into the same group are exported once. The teams passed are left unchanged.
 func TransformTeams(teams []*rest.Team, options TransformOptions) []*rest.Team {
-	if options.NestedTeams {
+	if options.keepsTeams() {
 		return teams
 	}
+	mapper := options.getTeamMapper()
+	groupTeamIDs := getGroupTeamIDs(teams, mapper)
+	groupPathTeamIDs := map[string]int{}
+	for _, e := range teams {
+		if groupTeamIDs[e.ID] == e.ID {
+			groupPathTeamIDs[mapper.GetGroupPath(e.FullName)] = e.ID
+		}
+	}
 	out := make([]*rest.Team, 0)
 	for _, e := range teams {
-		e.ParendID = 0
-		e.Name = strings.ReplaceAll(strings.TrimLeft(e.FullName, "/"), "/", "_")
-		e.FullName = "/" + e.Name
-		out = append(out, e)
+		if groupTeamIDs[e.ID] != e.ID {
+			continue
+		}
+		groupPath := mapper.GetGroupPath(e.FullName)
+		group := *e
+		group.ParendID = groupPathTeamIDs[path.Dir(groupPath)]
+		group.Name = path.Base(groupPath)
+		group.FullName = groupPath
+		out = append(out, &group)
 	}
 	return out
 }
 
-// TransformUsers reassigns users in the context of flatten teams.
+// TransformUsers reassigns users to the groups of their teams, and consolidates users if there's a user mapper.
+// Users keep access to child teams mapped outside their team's group.
 // Note "teams" list passed must be the original, non-flattened, list
 func TransformUsers(users []*rest.User, teams []*rest.Team, options TransformOptions) []*rest.User {
-	if options.NestedTeams {
-		return users
+	if !options.keepsTeams() {
+		users = mapUserTeams(users, teams, options.getTeamMapper())
+	}
+	if options.UserMapper != nil {
+		return options.UserMapper.Consolidate(users)
+	}
+	return users
+}
+
+func mapUserTeams(users []*rest.User, teams []*rest.Team, mapper *teammapping.Mapper) []*rest.User {
+	groupTeamIDs := getGroupTeamIDs(teams, mapper)
+	groupPaths := map[int]string{}
+	for _, e := range teams {
+		groupPaths[e.ID] = mapper.GetGroupPath(e.FullName)
 	}
 	out := make([]*rest.User, 0)
 	for _, e := range users {
+		teamIDs := make([]int, 0, len(e.TeamIDs))
 		for _, teamID := range e.TeamIDs {
-			e.TeamIDs = append(e.TeamIDs, getAllChildTeamIDs(teamID, teams)...)
+			teamIDs = append(teamIDs, teamID)
+			for _, childTeamID := range getAllChildTeamIDs(teamID, teams) {
+				if !isGroupPathWithin(groupPaths[childTeamID], groupPaths[teamID]) {
+					teamIDs = append(teamIDs, childTeamID)
+				}
+			}
 		}
+		e.TeamIDs = getUniqueGroupTeamIDs(teamIDs, groupTeamIDs)
 		out = append(out, e)
 	}
 	return out
 }
 
-// TransformSamlTeamMappings updates team mapping in the context of flatten teams.
-func TransformSamlTeamMappings(samlTeamMappings []*rest.SamlTeamMapping, options TransformOptions) []*rest.SamlTeamMapping {
-	if options.NestedTeams {
+// TransformSamlTeamMappings maps the teams of SAML team mappings to groups.
+// Note "teams" list passed must be the original, non-flattened, list
+func TransformSamlTeamMappings(
+	samlTeamMappings []*rest.SamlTeamMapping, teams []*rest.Team, options TransformOptions,
+) []*rest.SamlTeamMapping {
+	if options.keepsTeams() {
 		return samlTeamMappings
 	}
+	mapper := options.getTeamMapper()
+	groupTeamIDs := getGroupTeamIDs(teams, mapper)
 	out := make([]*rest.SamlTeamMapping, 0)
 	for _, e := range samlTeamMappings {
-		e.TeamFullPath = "/" + strings.ReplaceAll(strings.TrimLeft(e.TeamFullPath, "/"), "/", "_")
+		e.TeamFullPath = mapper.GetGroupPath(e.TeamFullPath)
+		if groupTeamID, ok := groupTeamIDs[e.TeamID]; ok {
+			e.TeamID = groupTeamID
+		}
 		out = append(out, e)
 	}
 	return out
 }
 
+// TransformProjects assigns projects to the groups of their teams.
+// Note "teams" list passed must be the original, non-flattened, list
+func TransformProjects(projects []*rest.Project, teams []*rest.Team, options TransformOptions) []*rest.Project {
+	if options.keepsTeams() {
+		return projects
+	}
+	groupTeamIDs := getGroupTeamIDs(teams, options.getTeamMapper())
+	for _, e := range projects {
+		if groupTeamID, ok := groupTeamIDs[e.TeamID]; ok {
+			e.TeamID = groupTeamID
+		}
+	}
+	return projects
+}
+
+// TagProjects sets the tags of each project from its custom fields, keeping the custom field values
+func TagProjects(projects []*rest.Project, options TransformOptions) {
+	mapper := options.CustomFieldMapper
+	if mapper == nil {
+		mapper = customfieldmapping.NewMapper(nil)
+	}
+	for _, project := range projects {
+		if project.Configuration != nil && len(project.Configuration.CustomFields) > 0 {
+			project.Configuration.Tags = mapper.GetTags(project.Configuration.CustomFields)
+		}
+	}
+}
+
 // TransformXMLInstallationMappings updates installation mapping.
 func TransformXMLInstallationMappings(installationMappings *soap.GetInstallationSettingsResponse) []*common.InstallationMapping {
 	out := make([]*common.InstallationMapping, 0)
@@ -105,20 +196,109 @@ func TransformXMLInstallationMappings(installationMappings *soap.GetInstallation
 	return out
 }
 
-// TransformScanReport updates scan report in context of flatten teams.
-func TransformScanReport(xml []byte, options TransformOptions) ([]byte, error) {
-	if options.NestedTeams {
-		return xml, nil
-	}
-	var teamPath string
-	out := replaceKeyValue(xml, "TeamFullPathOnReportDate", func(s string) string {
-		teamPath = strings.ReplaceAll(s, "\\", "_")
-		return teamPath
-	})
-	out = replaceKeyValue(out, "Team", func(_ string) string {
-		return teamPath
+// TransformScanReport streams scan report from reader to writer, updating result states according to
+// stateMapping, team attributes according to the team mapping and user references of consolidated users.
+// It returns the report data needed for metadata, collected in the same pass.
+func TransformScanReport(
+	reader io.Reader, writer io.Writer, stateMapping map[string]string, options TransformOptions,
+) (*report.CxXMLResults, error) {
+	updatedStatesCount := 0
+	output, err := report.Stream(reader, writer, func(element *xml.StartElement) bool {
+		switch element.Name.Local {
+		case "CxXMLResults":
+			return !options.keepsTeams() && mapReportTeams(element, options.getTeamMapper())
+		case "Result":
+			changed := false
+			if updateResultState(element, stateMapping) {
+				updatedStatesCount++
+				changed = true
+			}
+			if options.UserMapper != nil && options.UserMapper.IsConsolidated() {
+				changed = mapReportUsers(element, options.UserMapper) || changed
+			}
+			return changed
+		}
+		return false
 	})
-	return out, nil
+	if err != nil {
+		return nil, err
+	}
+	if updatedStatesCount > 0 {
+		log.Info().Str("scanID", output.ScanID).Msgf("updated %d result states based on the state mapping", updatedStatesCount)
+	}
+	return output, nil
+}
+
+// mapReportTeams replaces the report team attributes with the group the team is mapped to.
+// Report team paths are like CxServer\SP\Company, with Team as the last segment.
+func mapReportTeams(element *xml.StartElement, mapper *teammapping.Mapper) bool {
+	teamPath := ""
+	for _, attr := range element.Attr {
+		if attr.Name.Local == "TeamFullPathOnReportDate" {
+			teamPath = attr.Value
+		}
+	}
+	if teamPath == "" {
+		return false
+	}
+	groupPath := mapper.GetGroupPath("/" + strings.ReplaceAll(teamPath, "\\", "/"))
+	reportGroupPath := strings.ReplaceAll(strings.TrimPrefix(groupPath, "/"), "/", "\\")
+	if reportGroupPath == teamPath {
+		return false
+	}
+	changed := false
+	for i := range element.Attr {
+		attr := &element.Attr[i]
+		value := ""
+		switch attr.Name.Local {
+		case "TeamFullPathOnReportDate":
+			value = reportGroupPath
+		case "Team":
+			value = path.Base(groupPath)
+		default:
+			continue
+		}
+		if attr.Value != "" && attr.Value != value {
+			attr.Value = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// mapReportUsers replaces the assigned user and the remark authors of a result with the users they were merged into
+func mapReportUsers(element *xml.StartElement, mapper *usermapping.Mapper) bool {
+	changed := false
+	for i := range element.Attr {
+		attr := &element.Attr[i]
+		value := attr.Value
+		switch attr.Name.Local {
+		case "AssignToUser":
+			value = mapper.GetUserName(attr.Value)
+		case "Remark":
+			value = mapper.MapRemark(attr.Value)
+		}
+		if value != attr.Value {
+			attr.Value = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// updateResultState replaces the result state with its mapped state, if any
+func updateResultState(element *xml.StartElement, stateMapping map[string]string) bool {
+	for i := range element.Attr {
+		attr := &element.Attr[i]
+		if attr.Name.Local != "state" {
+			continue
+		}
+		if newState, exists := stateMapping[attr.Value]; exists && newState != attr.Value {
+			attr.Value = newState
+			return true
+		}
+	}
+	return false
 }
 
 // TransformEngineServers just for SAST distributed architecture just for 9.4 or higher.
@@ -173,13 +353,39 @@ func getAllChildTeamIDs(root int, teams []*rest.Team) []int {
 	return out
 }
 
-// replaceKeyValue
-func replaceKeyValue(d []byte, key string, getValue func(string) string) []byte {
-	re := regexp.MustCompile(fmt.Sprintf(`(%s)="([^"]+)"`, key))
-	submatchCount := 2
-	return re.ReplaceAllFunc(d, func(entry []byte) []byte {
-		matches := re.FindAllSubmatch(entry, submatchCount)
-		value := getValue(string(matches[0][2]))
-		return []byte(fmt.Sprintf(`%s=%q`, key, value))
-	})
+// getGroupTeamIDs returns, for each team id, the id of the first team mapped to the same group
+func getGroupTeamIDs(teams []*rest.Team, mapper *teammapping.Mapper) map[int]int {
+	out := map[int]int{}
+	groupPathTeamIDs := map[string]int{}
+	for _, e := range teams {
+		groupPath := mapper.GetGroupPath(e.FullName)
+		if groupTeamID, ok := groupPathTeamIDs[groupPath]; ok {
+			out[e.ID] = groupTeamID
+			continue
+		}
+		groupPathTeamIDs[groupPath] = e.ID
+		out[e.ID] = e.ID
+	}
+	return out
+}
+
+// getUniqueGroupTeamIDs replaces team ids with the ids of their groups, removing repeated ones
+func getUniqueGroupTeamIDs(teamIDs []int, groupTeamIDs map[int]int) []int {
+	out := make([]int, 0, len(teamIDs))
+	seen := map[int]bool{}
+	for _, teamID := range teamIDs {
+		if groupTeamID, ok := groupTeamIDs[teamID]; ok {
+			teamID = groupTeamID
+		}
+		if !seen[teamID] {
+			seen[teamID] = true
+			out = append(out, teamID)
+		}
+	}
+	return out
+}
+
+// isGroupPathWithin returns true if groupPath is parentGroupPath or one of its child groups
+func isGroupPathWithin(groupPath, parentGroupPath string) bool {
+	return groupPath == parentGroupPath || strings.HasPrefix(groupPath, parentGroupPath+"/")
 }
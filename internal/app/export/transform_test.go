@@ -1,9 +1,17 @@
 package export
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/checkmarxDev/ast-sast-export/internal/app/customfieldmapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/teammapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/usermapping"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/common"
 
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
@@ -102,6 +110,28 @@ func TestTransformTeams(t *testing.T) {
 				{ID: 3, Name: "TeamC", FullName: "/TeamA/TeamC", ParendID: 1},
 			},
 		},
+		{
+			"team mapping with nested teams",
+			getMappedTeams(),
+			TransformOptions{NestedTeams: true, TeamMapper: newMockTeamMapper(true)},
+			[]*rest.Team{
+				{ID: 1, Name: "TeamA", FullName: "/TeamA", ParendID: 0},
+				{ID: 2, Name: "Legacy", FullName: "/Legacy", ParendID: 0},
+				{ID: 4, Name: "Renamed", FullName: "/TeamA/Renamed", ParendID: 1},
+				{ID: 5, Name: "TeamE", FullName: "/TeamA/Renamed/TeamE", ParendID: 4},
+			},
+		},
+		{
+			"team mapping with flatten teams",
+			getMappedTeams(),
+			TransformOptions{TeamMapper: newMockTeamMapper(false)},
+			[]*rest.Team{
+				{ID: 1, Name: "TeamA", FullName: "/TeamA", ParendID: 0},
+				{ID: 2, Name: "Legacy", FullName: "/Legacy", ParendID: 0},
+				{ID: 4, Name: "Renamed", FullName: "/TeamA/Renamed", ParendID: 1},
+				{ID: 5, Name: "Renamed_TeamE", FullName: "/TeamA/Renamed_TeamE", ParendID: 1},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
@@ -200,6 +230,34 @@ func TestTransformUsers(t *testing.T) {
 				{ID: 2, UserName: "Bob", TeamIDs: []int{2}},
 			},
 		},
+		{
+			"team mapping with nested teams",
+			[]*rest.User{
+				{ID: 1, UserName: "Alice", TeamIDs: []int{1}},
+				{ID: 2, UserName: "Bob", TeamIDs: []int{3}},
+				{ID: 3, UserName: "Charlie", TeamIDs: []int{2, 3}},
+			},
+			getMappedTeams(),
+			TransformOptions{NestedTeams: true, TeamMapper: newMockTeamMapper(true)},
+			[]*rest.User{
+				{ID: 1, UserName: "Alice", TeamIDs: []int{1, 2}},
+				{ID: 2, UserName: "Bob", TeamIDs: []int{2}},
+				{ID: 3, UserName: "Charlie", TeamIDs: []int{2}},
+			},
+		},
+		{
+			"team mapping with flatten teams",
+			[]*rest.User{
+				{ID: 1, UserName: "Alice", TeamIDs: []int{1}},
+				{ID: 2, UserName: "Bob", TeamIDs: []int{4}},
+			},
+			getMappedTeams(),
+			TransformOptions{TeamMapper: newMockTeamMapper(false)},
+			[]*rest.User{
+				{ID: 1, UserName: "Alice", TeamIDs: []int{1, 2}},
+				{ID: 2, UserName: "Bob", TeamIDs: []int{4, 5}},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
@@ -209,11 +267,29 @@ func TestTransformUsers(t *testing.T) {
 	}
 }
 
+func TestTransformUsersWithUserMapper(t *testing.T) {
+	users := []*rest.User{
+		{ID: 1, UserName: "Alice", Email: "alice@example.com", TeamIDs: []int{1}, AuthenticationProviderID: 1, Active: true},
+		{ID: 2, UserName: "CORP\\alice", Email: "Alice@example.com", TeamIDs: []int{2}, AuthenticationProviderID: 2, Active: true},
+	}
+	teams := []*rest.Team{
+		{ID: 1, Name: "TeamA", FullName: "/TeamA", ParendID: 0},
+		{ID: 2, Name: "TeamB", FullName: "/TeamA/TeamB", ParendID: 1},
+	}
+
+	result := TransformUsers(users, teams, TransformOptions{UserMapper: usermapping.NewMapper(nil)})
+
+	expected := []*rest.User{
+		{ID: 1, UserName: "Alice", Email: "alice@example.com", TeamIDs: []int{1, 2}, AuthenticationProviderID: 1, Active: true},
+	}
+	assert.Equal(t, expected, result)
+}
+
 func TestTransformSamlTeamMappings(t *testing.T) {
 	t.Run("no mappings", func(t *testing.T) {
 		var samlTeamMappings []*rest.SamlTeamMapping
 
-		result := TransformSamlTeamMappings(samlTeamMappings, TransformOptions{})
+		result := TransformSamlTeamMappings(samlTeamMappings, nil, TransformOptions{})
 
 		var expected []*rest.SamlTeamMapping
 		assert.ElementsMatch(t, expected, result)
@@ -224,7 +300,7 @@ func TestTransformSamlTeamMappings(t *testing.T) {
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 1, TeamFullPath: "/TeamA", SamlAttributeValue: "team"},
 		}
 
-		result := TransformSamlTeamMappings(samlTeamMappings, TransformOptions{})
+		result := TransformSamlTeamMappings(samlTeamMappings, nil, TransformOptions{})
 
 		expected := []*rest.SamlTeamMapping{
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 1, TeamFullPath: "/TeamA", SamlAttributeValue: "team"},
@@ -237,7 +313,7 @@ func TestTransformSamlTeamMappings(t *testing.T) {
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 2, TeamFullPath: "/TeamA/TeamB", SamlAttributeValue: "team"},
 		}
 
-		result := TransformSamlTeamMappings(samlTeamMappings, TransformOptions{})
+		result := TransformSamlTeamMappings(samlTeamMappings, nil, TransformOptions{})
 
 		expected := []*rest.SamlTeamMapping{
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 2, TeamFullPath: "/TeamA_TeamB", SamlAttributeValue: "team"},
@@ -250,7 +326,7 @@ func TestTransformSamlTeamMappings(t *testing.T) {
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 3, TeamFullPath: "/TeamA/TeamB/TeamC", SamlAttributeValue: "team"},
 		}
 
-		result := TransformSamlTeamMappings(samlTeamMappings, TransformOptions{})
+		result := TransformSamlTeamMappings(samlTeamMappings, nil, TransformOptions{})
 
 		expected := []*rest.SamlTeamMapping{
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 3, TeamFullPath: "/TeamA_TeamB_TeamC", SamlAttributeValue: "team"},
@@ -258,12 +334,28 @@ func TestTransformSamlTeamMappings(t *testing.T) {
 		assert.ElementsMatch(t, expected, result)
 	})
 
+	t.Run("team mapping", func(t *testing.T) {
+		samlTeamMappings := []*rest.SamlTeamMapping{
+			{ID: 1, SamlIdentityProviderID: 1, TeamID: 3, TeamFullPath: "/TeamA/TeamB/TeamC", SamlAttributeValue: "team"},
+			{ID: 2, SamlIdentityProviderID: 1, TeamID: 5, TeamFullPath: "/TeamA/TeamD/TeamE", SamlAttributeValue: "team"},
+		}
+
+		result := TransformSamlTeamMappings(samlTeamMappings, getMappedTeams(),
+			TransformOptions{NestedTeams: true, TeamMapper: newMockTeamMapper(true)})
+
+		expected := []*rest.SamlTeamMapping{
+			{ID: 1, SamlIdentityProviderID: 1, TeamID: 2, TeamFullPath: "/Legacy", SamlAttributeValue: "team"},
+			{ID: 2, SamlIdentityProviderID: 1, TeamID: 5, TeamFullPath: "/TeamA/Renamed/TeamE", SamlAttributeValue: "team"},
+		}
+		assert.ElementsMatch(t, expected, result)
+	})
+
 	t.Run("nested teams enabled", func(t *testing.T) {
 		samlTeamMappings := []*rest.SamlTeamMapping{
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 2, TeamFullPath: "/TeamA/TeamB", SamlAttributeValue: "team"},
 		}
 
-		result := TransformSamlTeamMappings(samlTeamMappings, TransformOptions{NestedTeams: true})
+		result := TransformSamlTeamMappings(samlTeamMappings, nil, TransformOptions{NestedTeams: true})
 
 		expected := []*rest.SamlTeamMapping{
 			{ID: 1, SamlIdentityProviderID: 1, TeamID: 2, TeamFullPath: "/TeamA/TeamB", SamlAttributeValue: "team"},
@@ -272,45 +364,240 @@ func TestTransformSamlTeamMappings(t *testing.T) {
 	})
 }
 
+func TestTransformProjects(t *testing.T) {
+	t.Run("assigns projects to the groups of their teams", func(t *testing.T) {
+		projects := []*rest.Project{{ID: 1, TeamID: 1}, {ID: 2, TeamID: 3}, {ID: 3, TeamID: 5}}
+
+		result := TransformProjects(projects, getMappedTeams(), TransformOptions{TeamMapper: newMockTeamMapper(false)})
+
+		expected := []*rest.Project{{ID: 1, TeamID: 1}, {ID: 2, TeamID: 2}, {ID: 3, TeamID: 5}}
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("keeps projects without team mapping", func(t *testing.T) {
+		projects := []*rest.Project{{ID: 1, TeamID: 3}}
+
+		result := TransformProjects(projects, getMappedTeams(), TransformOptions{})
+
+		assert.Equal(t, []*rest.Project{{ID: 1, TeamID: 3}}, result)
+	})
+}
+
+func TestTagProjects(t *testing.T) {
+	t.Run("maps custom fields to tags", func(t *testing.T) {
+		mapper := customfieldmapping.NewMapper([]customfieldmapping.Rule{{Field: "Owner", TagKey: "team"}})
+		projects := []*rest.Project{
+			{ID: 1, Configuration: &rest.Configuration{CustomFields: []*rest.CustomField{{FieldName: "Owner", FieldValue: "payments"}}}},
+			{ID: 2, Configuration: &rest.Configuration{}},
+			{ID: 3},
+		}
+
+		TagProjects(projects, TransformOptions{CustomFieldMapper: mapper})
+
+		assert.Equal(t, map[string]string{"team": "payments"}, projects[0].Configuration.Tags)
+		assert.Nil(t, projects[1].Configuration.Tags)
+		assert.Nil(t, projects[2].Configuration)
+	})
+
+	t.Run("keeps custom fields as tags without mapper", func(t *testing.T) {
+		projects := []*rest.Project{
+			{ID: 1, Configuration: &rest.Configuration{CustomFields: []*rest.CustomField{{FieldName: "Owner", FieldValue: "payments"}}}},
+		}
+
+		TagProjects(projects, TransformOptions{})
+
+		assert.Equal(t, map[string]string{"Owner": "payments"}, projects[0].Configuration.Tags)
+	})
+}
+
 func TestTransformScanReport(t *testing.T) {
 	t.Run("root team", func(t *testing.T) {
 		report := newMockScanReportXML("TeamA", "TeamA")
+		var output bytes.Buffer
 
-		result, err := TransformScanReport([]byte(report), TransformOptions{})
+		result, err := TransformScanReport(strings.NewReader(report), &output, nil, TransformOptions{})
 
 		assert.NoError(t, err)
-		assert.Equal(t, report, string(result))
+		assert.Equal(t, report, output.String())
+		assert.Equal(t, "1000000", result.ScanID)
 	})
 
 	t.Run("one level deep team", func(t *testing.T) {
 		report := newMockScanReportXML("TeamB", "TeamA\\TeamB")
+		var output bytes.Buffer
 
-		result, err := TransformScanReport([]byte(report), TransformOptions{})
+		_, err := TransformScanReport(strings.NewReader(report), &output, nil, TransformOptions{})
 
 		assert.NoError(t, err)
 		expected := newMockScanReportXML("TeamA_TeamB", "TeamA_TeamB")
-		assert.Equal(t, expected, string(result))
+		assert.Equal(t, expected, output.String())
 	})
 
 	t.Run("two levels deep team", func(t *testing.T) {
 		report := newMockScanReportXML("TeamC", "TeamA\\TeamB\\TeamC")
+		var output bytes.Buffer
 
-		result, err := TransformScanReport([]byte(report), TransformOptions{})
+		_, err := TransformScanReport(strings.NewReader(report), &output, nil, TransformOptions{})
 
 		assert.NoError(t, err)
 		expected := newMockScanReportXML("TeamA_TeamB_TeamC", "TeamA_TeamB_TeamC")
-		assert.Equal(t, expected, string(result))
+		assert.Equal(t, expected, output.String())
 	})
 
 	t.Run("nested teams enabled", func(t *testing.T) {
 		report := newMockScanReportXML("TeamB", "TeamA\\TeamB")
+		var output bytes.Buffer
 
-		result, err := TransformScanReport([]byte(report), TransformOptions{NestedTeams: true})
+		_, err := TransformScanReport(strings.NewReader(report), &output, nil, TransformOptions{NestedTeams: true})
 
 		assert.NoError(t, err)
 		expected := newMockScanReportXML("TeamB", "TeamA\\TeamB")
-		assert.Equal(t, expected, string(result))
+		assert.Equal(t, expected, output.String())
+	})
+
+	t.Run("team mapping with nested teams", func(t *testing.T) {
+		report := newMockScanReportXML("TeamC", "TeamA\\TeamB\\TeamC")
+		var output bytes.Buffer
+
+		_, err := TransformScanReport(strings.NewReader(report), &output, nil,
+			TransformOptions{NestedTeams: true, TeamMapper: newMockTeamMapper(true)})
+
+		assert.NoError(t, err)
+		expected := newMockScanReportXML("Legacy", "Legacy")
+		assert.Equal(t, expected, output.String())
+	})
+
+	t.Run("team mapping with flatten teams", func(t *testing.T) {
+		report := newMockScanReportXML("TeamE", "TeamA\\TeamD\\TeamE")
+		var output bytes.Buffer
+
+		_, err := TransformScanReport(strings.NewReader(report), &output, nil, TransformOptions{TeamMapper: newMockTeamMapper(false)})
+
+		assert.NoError(t, err)
+		expected := newMockScanReportXML("Renamed_TeamE", "TeamA\\Renamed_TeamE")
+		assert.Equal(t, expected, output.String())
 	})
+
+	t.Run("maps users of consolidated users", func(t *testing.T) {
+		report := `<CxXMLResults ScanId="1000000" Team="TeamA">
+  <Query id="1" name="Query1" group="Java_High_Risk" Language="Java">
+    <Result NodeId="1" state="1" AssignToUser="jsmith" Remark="J. Smith WebGoat, [Friday, February 9, 2024 2:12:40 PM]: Confirmed">
+      <Path ResultId="1" PathId="1" SimilarityId="1"/>
+    </Result>
+  </Query>
+</CxXMLResults>`
+		userMapper := usermapping.NewMapper(nil)
+		userMapper.Consolidate([]*rest.User{
+			{ID: 1, UserName: "john", FirstName: "John", LastName: "Smith", Email: "john@example.com",
+				AuthenticationProviderID: 1, Active: true},
+			{ID: 2, UserName: "jsmith", FirstName: "J.", LastName: "Smith", Email: "john@example.com",
+				AuthenticationProviderID: 2, Active: true},
+		})
+		var output bytes.Buffer
+
+		_, err := TransformScanReport(strings.NewReader(report), &output, nil, TransformOptions{NestedTeams: true, UserMapper: userMapper})
+
+		assert.NoError(t, err)
+		expected := strings.Replace(report, `AssignToUser="jsmith" Remark="J. Smith WebGoat`, `AssignToUser="john" Remark="John Smith WebGoat`, 1)
+		assert.Equal(t, expected, output.String())
+	})
+
+	t.Run("updates mapped result states", func(t *testing.T) {
+		report := `<?xml version="1.0" encoding="utf-8"?>
+<CxXMLResults ScanId="1000000" Team="TeamA">
+  <Query id="1" name="Query1" group="Java_High_Risk" Language="Java">
+    <Result NodeId="1" state="1" Remark="">
+      <Path ResultId="1" PathId="1" SimilarityId="1"/>
+    </Result>
+    <Result NodeId="2" state="5" Remark="">
+      <Path ResultId="1" PathId="2" SimilarityId="2"/>
+    </Result>
+  </Query>
+</CxXMLResults>`
+		stateMapping := map[string]string{"1": "1001", "2": "1002"}
+		var output bytes.Buffer
+
+		result, err := TransformScanReport(strings.NewReader(report), &output, stateMapping, TransformOptions{})
+
+		assert.NoError(t, err)
+		expected := strings.Replace(report, `<Result NodeId="1" state="1" Remark="">`, `<Result NodeId="1" state="1001" Remark="">`, 1)
+		assert.Equal(t, expected, output.String())
+		assert.Len(t, result.Queries, 1)
+		assert.Equal(t, "1001", result.Queries[0].Results[0].State)
+		assert.Equal(t, "5", result.Queries[0].Results[1].State)
+	})
+
+	t.Run("fails if report is invalid", func(t *testing.T) {
+		var output bytes.Buffer
+
+		_, err := TransformScanReport(strings.NewReader("<CxXMLResults><Query>"), &output, nil, TransformOptions{})
+
+		assert.EqualError(t, err, "report is incomplete")
+	})
+}
+
+// TestTransformScanReportSynthetic checks hand-written reports shaped like SAST reports against their expected output,
+// they cover report layouts and team paths, not specific SAST versions
+func TestTransformScanReportSynthetic(t *testing.T) {
+	stateMapping := map[string]string{"1": "5", "3": "6"}
+	for _, scenario := range []string{"nested-team", "proposed-not-exploitable", "team-with-space", "non-ascii-team", "root-team"} {
+		t.Run(scenario, func(t *testing.T) {
+			reportPath := path.Join("../../../test/data/sast/report/synthetic", scenario, "report.xml")
+			report, reportErr := os.ReadFile(reportPath)
+			assert.NoError(t, reportErr)
+			expected, expectedErr := os.ReadFile(path.Join(path.Dir(reportPath), "expected.xml"))
+			assert.NoError(t, expectedErr)
+
+			t.Run("remaps states and flattens teams", func(t *testing.T) {
+				var output bytes.Buffer
+
+				_, err := TransformScanReport(bytes.NewReader(report), &output, stateMapping, TransformOptions{})
+
+				assert.NoError(t, err)
+				assert.Equal(t, string(expected), output.String())
+			})
+
+			t.Run("keeps report unchanged without transformations", func(t *testing.T) {
+				var output bytes.Buffer
+
+				_, err := TransformScanReport(bytes.NewReader(report), &output, nil, TransformOptions{NestedTeams: true})
+
+				assert.NoError(t, err)
+				assert.Equal(t, string(report), output.String())
+			})
+		})
+	}
+}
+
+// TestTransformScanReportRealSamples checks that reports exported by SAST only change in the rewritten state attributes,
+// including the elements the report structs don't model. Each sample is an anonymized report of the given SAST version.
+func TestTransformScanReportRealSamples(t *testing.T) {
+	samples := []struct {
+		version, file string
+	}{
+		{version: "9.3.0.1139", file: "report1.xml"},
+	}
+	stateAttribute := regexp.MustCompile(`\bstate="[^"]*"`)
+	for _, sample := range samples {
+		t.Run(sample.version, func(t *testing.T) {
+			report, reportErr := os.ReadFile(path.Join("../../../test/data/sast/report", sample.file))
+			assert.NoError(t, reportErr)
+			assert.Contains(t, string(report), fmt.Sprintf(`CheckmarxVersion="%s"`, sample.version))
+			stateMapping := map[string]string{}
+			for _, state := range stateAttribute.FindAllString(string(report), -1) {
+				value := strings.TrimSuffix(strings.TrimPrefix(state, `state="`), `"`)
+				stateMapping[value] = "10" + value
+			}
+			var output bytes.Buffer
+
+			_, err := TransformScanReport(bytes.NewReader(report), &output, stateMapping, TransformOptions{NestedTeams: true})
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, string(report), output.String())
+			assert.Equal(t, stateAttribute.ReplaceAllString(string(report), `state=""`),
+				stateAttribute.ReplaceAllString(output.String(), `state=""`))
+		})
+	}
 }
 
 func TestTransformEngineServers(t *testing.T) {
@@ -421,28 +708,6 @@ func TestGetAllChildTeamIDs(t *testing.T) {
 	})
 }
 
-func TestReplaceKeyValue(t *testing.T) {
-	s := `a="1" b="2" c="3"`
-
-	t.Run("a", func(t *testing.T) {
-		result := replaceKeyValue([]byte(s), "a", func(a string) string {
-			return fmt.Sprintf(".%s.", a)
-		})
-
-		expected := `a=".1." b="2" c="3"`
-		assert.Equal(t, expected, string(result))
-	})
-
-	t.Run("b", func(t *testing.T) {
-		result := replaceKeyValue([]byte(s), "b", func(b string) string {
-			return fmt.Sprintf("-%s-", b)
-		})
-
-		expected := `a="1" b="-2-" c="3"`
-		assert.Equal(t, expected, string(result))
-	})
-}
-
 // nolint
 func TestTransformXMLInstallationMappings(t *testing.T) {
 	engineService := &soap.InstallationSetting{
@@ -669,3 +934,20 @@ func newMockScanReportXML(teamName, teamFullPath string) string {
 </CxXMLResults>
 `, teamFullPath, teamName)
 }
+
+func getMappedTeams() []*rest.Team {
+	return []*rest.Team{
+		{ID: 1, Name: "TeamA", FullName: "/TeamA", ParendID: 0},
+		{ID: 2, Name: "TeamB", FullName: "/TeamA/TeamB", ParendID: 1},
+		{ID: 3, Name: "TeamC", FullName: "/TeamA/TeamB/TeamC", ParendID: 2},
+		{ID: 4, Name: "TeamD", FullName: "/TeamA/TeamD", ParendID: 1},
+		{ID: 5, Name: "TeamE", FullName: "/TeamA/TeamD/TeamE", ParendID: 4},
+	}
+}
+
+func newMockTeamMapper(nestedTeams bool) *teammapping.Mapper {
+	return teammapping.NewMapper([]teammapping.Rule{
+		{TeamPath: "/TeamA/TeamB", GroupPath: "/Legacy", Merge: true},
+		{TeamPath: "/TeamA/TeamD", GroupPath: "/TeamA/Renamed"},
+	}, nestedTeams)
+}
@@ -0,0 +1,241 @@
+package fetch
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultMaxSize is the size limit of fetched resources
+	DefaultMaxSize = 32 << 20
+	// SignatureExtension is appended to a source to get its detached signature
+	SignatureExtension = ".sig"
+
+	ifNoneMatchHeader = "If-None-Match"
+	etagHeader        = "ETag"
+	cacheDirMode      = 0700
+	cacheFileMode     = 0600
+	cacheDataExt      = ".data"
+	cacheETagExt      = ".etag"
+)
+
+type (
+	// Adapter sends HTTP requests, retrying them like the SAST client does
+	Adapter interface {
+		Do(req *retryablehttp.Request) (*http.Response, error)
+	}
+
+	// Options are the checks a fetched resource must pass
+	Options struct {
+		// SHA256 pins the resource to a hex encoded SHA-256 hash
+		SHA256 string
+		// PublicKey verifies the detached signature found at the source with SignatureExtension appended
+		PublicKey ed25519.PublicKey
+	}
+
+	// Resource is the content of a file path or URL
+	Resource struct {
+		Source  string `json:"source"`
+		SHA256  string `json:"sha256"`
+		Content []byte `json:"-"`
+		// Cached is true if the content came from the local cache, because the server reported it unchanged
+		Cached bool `json:"cached,omitempty"`
+	}
+
+	// Fetcher reads resources from file paths and URLs, caching downloads by their ETag
+	Fetcher struct {
+		adapter  Adapter
+		cacheDir string
+		maxSize  int64
+	}
+)
+
+// NewFetcher creates a fetcher sending requests with adapter and caching downloads in cacheDir.
+// Downloads are not cached if cacheDir is empty.
+func NewFetcher(adapter Adapter, cacheDir string) *Fetcher {
+	return &Fetcher{adapter: adapter, cacheDir: cacheDir, maxSize: DefaultMaxSize}
+}
+
+// NewResource creates a resource with the given content
+func NewResource(source string, content []byte) *Resource {
+	hash := sha256.Sum256(content)
+	return &Resource{Source: source, SHA256: hex.EncodeToString(hash[:]), Content: content}
+}
+
+// IsRemote returns true if the source is a URL
+func IsRemote(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// Fetch reads a resource from a file path or URL and checks it against options
+func (f *Fetcher) Fetch(source string, options Options) (*Resource, error) {
+	if source == "" {
+		return nil, errors.New("source (file path or URL) cannot be empty")
+	}
+	resource, etag, err := f.read(source, true)
+	if err != nil {
+		return nil, err
+	}
+	if checkErr := CheckSHA256(resource, options.SHA256); checkErr != nil {
+		return nil, checkErr
+	}
+	if options.PublicKey != nil {
+		if signatureErr := f.verifySignature(resource, options.PublicKey); signatureErr != nil {
+			return nil, signatureErr
+		}
+	}
+	if etag != "" {
+		f.writeCache(source, etag, resource.Content)
+	}
+	return resource, nil
+}
+
+// CheckSHA256 returns an error unless the resource has the expected hash, or no hash is expected
+func CheckSHA256(resource *Resource, expected string) error {
+	if expected == "" || strings.EqualFold(resource.SHA256, expected) {
+		return nil
+	}
+	return errors.Errorf("sha256 of %s is %s, expected %s", resource.Source, resource.SHA256, expected)
+}
+
+// read returns the resource and, if it was downloaded and should be cached, its ETag
+func (f *Fetcher) read(source string, useCache bool) (resource *Resource, etag string, err error) {
+	if !IsRemote(source) {
+		content, readErr := f.readFile(source)
+		if readErr != nil {
+			return nil, "", readErr
+		}
+		return NewResource(source, content), "", nil
+	}
+	return f.download(source, useCache)
+}
+
+func (f *Fetcher) readFile(source string) ([]byte, error) {
+	file, openErr := os.Open(source)
+	if openErr != nil {
+		return nil, errors.Wrapf(openErr, "could not read %s", source)
+	}
+	defer file.Close()
+	return f.readLimited(source, file)
+}
+
+func (f *Fetcher) download(source string, useCache bool) (resource *Resource, etag string, err error) {
+	req, reqErr := retryablehttp.NewRequest(http.MethodGet, source, nil)
+	if reqErr != nil {
+		return nil, "", errors.Wrapf(reqErr, "could not create request for %s", source)
+	}
+	cachedETag, cachedContent := "", []byte(nil)
+	if useCache {
+		cachedETag, cachedContent = f.readCache(source)
+		if cachedETag != "" {
+			req.Header.Set(ifNoneMatchHeader, cachedETag)
+		}
+	}
+	resp, doErr := f.adapter.Do(req)
+	if doErr != nil {
+		return nil, "", errors.Wrapf(doErr, "could not download %s", source)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cachedETag != "":
+		log.Debug().Str("source", source).Str("etag", cachedETag).Msg("using cached download")
+		resource = NewResource(source, cachedContent)
+		resource.Cached = true
+		return resource, "", nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", errors.Errorf("could not download %s: unexpected HTTP status %d", source, resp.StatusCode)
+	case resp.ContentLength > f.maxSize:
+		return nil, "", errors.Errorf("%s exceeds the maximum size of %d bytes", source, f.maxSize)
+	}
+	content, readErr := f.readLimited(source, resp.Body)
+	if readErr != nil {
+		return nil, "", readErr
+	}
+	if useCache {
+		etag = resp.Header.Get(etagHeader)
+	}
+	return NewResource(source, content), etag, nil
+}
+
+func (f *Fetcher) readLimited(source string, reader io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, f.maxSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", source)
+	}
+	if int64(len(content)) > f.maxSize {
+		return nil, errors.Errorf("%s exceeds the maximum size of %d bytes", source, f.maxSize)
+	}
+	return content, nil
+}
+
+// verifySignature checks the resource against its detached Ed25519 signature, either raw or base64 encoded
+func (f *Fetcher) verifySignature(resource *Resource, publicKey ed25519.PublicKey) error {
+	signatureSource := resource.Source + SignatureExtension
+	signatureResource, _, readErr := f.read(signatureSource, false)
+	if readErr != nil {
+		return errors.Wrapf(readErr, "could not read signature of %s", resource.Source)
+	}
+	signature := signatureResource.Content
+	if len(signature) != ed25519.SignatureSize {
+		decoded, decodeErr := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+		if decodeErr != nil {
+			return errors.Errorf("signature %s must be raw or base64 encoded", signatureSource)
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(publicKey, resource.Content, signature) {
+		return errors.Errorf("signature of %s is not valid", resource.Source)
+	}
+	return nil
+}
+
+func (f *Fetcher) getCachePath(source string) string {
+	hash := sha256.Sum256([]byte(source))
+	return filepath.Join(f.cacheDir, hex.EncodeToString(hash[:]))
+}
+
+func (f *Fetcher) readCache(source string) (etag string, content []byte) {
+	if f.cacheDir == "" {
+		return "", nil
+	}
+	cachePath := f.getCachePath(source)
+	etagContent, etagErr := os.ReadFile(cachePath + cacheETagExt)
+	if etagErr != nil {
+		return "", nil
+	}
+	content, contentErr := os.ReadFile(cachePath + cacheDataExt)
+	if contentErr != nil {
+		return "", nil
+	}
+	return string(etagContent), content
+}
+
+func (f *Fetcher) writeCache(source, etag string, content []byte) {
+	if f.cacheDir == "" {
+		return
+	}
+	cachePath := f.getCachePath(source)
+	err := os.MkdirAll(f.cacheDir, cacheDirMode)
+	if err == nil {
+		err = os.WriteFile(cachePath+cacheDataExt, content, cacheFileMode)
+	}
+	if err == nil {
+		err = os.WriteFile(cachePath+cacheETagExt, []byte(etag), cacheFileMode)
+	}
+	if err != nil {
+		log.Debug().Err(err).Str("source", source).Msgf("could not cache download in %s", f.cacheDir)
+	}
+}
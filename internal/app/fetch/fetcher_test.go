@@ -0,0 +1,203 @@
+package fetch
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testURL     = "https://example.com/mapping.json"
+	testContent = `{"mappings": []}`
+	// otherHash is a SHA-256 that doesn't match testContent
+	otherHash = "a1ac4e0d2c0e3c3d6d19d42ecf40f2d3d29a0fb1fbe4fd5e0c2e2d6de0d7e3c4"
+)
+
+type adapterMock struct {
+	requests []*retryablehttp.Request
+	handler  func(*retryablehttp.Request) *http.Response
+}
+
+func (a *adapterMock) Do(req *retryablehttp.Request) (*http.Response, error) {
+	a.requests = append(a.requests, req)
+	return a.handler(req), nil
+}
+
+func makeResponse(statusCode int, body, etag string) *http.Response {
+	header := http.Header{}
+	if etag != "" {
+		header.Set(etagHeader, etag)
+	}
+	return &http.Response{
+		StatusCode:    statusCode,
+		Header:        header,
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func getHash(t *testing.T, content string) string {
+	t.Helper()
+	return NewResource("", []byte(content)).SHA256
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	t.Run("reads file", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "mapping.json")
+		require.NoError(t, os.WriteFile(fileName, []byte(testContent), 0600))
+
+		result, err := NewFetcher(nil, "").Fetch(fileName, Options{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, fileName, result.Source)
+		assert.Equal(t, []byte(testContent), result.Content)
+		assert.Equal(t, getHash(t, testContent), result.SHA256)
+	})
+	t.Run("downloads URL", func(t *testing.T) {
+		adapter := &adapterMock{handler: func(_ *retryablehttp.Request) *http.Response {
+			return makeResponse(http.StatusOK, testContent, "")
+		}}
+
+		result, err := NewFetcher(adapter, "").Fetch(testURL, Options{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(testContent), result.Content)
+		assert.False(t, result.Cached)
+	})
+	t.Run("fails on unexpected HTTP status", func(t *testing.T) {
+		adapter := &adapterMock{handler: func(_ *retryablehttp.Request) *http.Response {
+			return makeResponse(http.StatusBadGateway, "<html>Proxy error</html>", "")
+		}}
+
+		_, err := NewFetcher(adapter, "").Fetch(testURL, Options{})
+
+		assert.EqualError(t, err, "could not download https://example.com/mapping.json: unexpected HTTP status 502")
+	})
+	t.Run("fails if download is too big", func(t *testing.T) {
+		adapter := &adapterMock{handler: func(_ *retryablehttp.Request) *http.Response {
+			response := makeResponse(http.StatusOK, testContent, "")
+			response.ContentLength = -1
+			return response
+		}}
+		fetcher := NewFetcher(adapter, "")
+		fetcher.maxSize = 10
+
+		_, err := fetcher.Fetch(testURL, Options{})
+
+		assert.EqualError(t, err, "https://example.com/mapping.json exceeds the maximum size of 10 bytes")
+	})
+	t.Run("fails if file is too big", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "mapping.json")
+		require.NoError(t, os.WriteFile(fileName, []byte(testContent), 0600))
+		fetcher := NewFetcher(nil, "")
+		fetcher.maxSize = 10
+
+		_, err := fetcher.Fetch(fileName, Options{})
+
+		assert.ErrorContains(t, err, "exceeds the maximum size of 10 bytes")
+	})
+	t.Run("fails without source", func(t *testing.T) {
+		_, err := NewFetcher(nil, "").Fetch("", Options{})
+
+		assert.EqualError(t, err, "source (file path or URL) cannot be empty")
+	})
+	t.Run("checks pinned hash", func(t *testing.T) {
+		adapter := &adapterMock{handler: func(_ *retryablehttp.Request) *http.Response {
+			return makeResponse(http.StatusOK, testContent, "")
+		}}
+		fetcher := NewFetcher(adapter, "")
+
+		result, err := fetcher.Fetch(testURL, Options{SHA256: strings.ToUpper(getHash(t, testContent))})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(testContent), result.Content)
+
+		_, err = fetcher.Fetch(testURL, Options{SHA256: otherHash})
+		assert.EqualError(t, err, "sha256 of https://example.com/mapping.json is "+getHash(t, testContent)+", expected "+otherHash)
+	})
+	t.Run("caches downloads by ETag", func(t *testing.T) {
+		cacheDir := filepath.Join(t.TempDir(), "cache")
+		adapter := &adapterMock{handler: func(req *retryablehttp.Request) *http.Response {
+			if req.Header.Get(ifNoneMatchHeader) == `"v1"` {
+				return makeResponse(http.StatusNotModified, "", "")
+			}
+			return makeResponse(http.StatusOK, testContent, `"v1"`)
+		}}
+		fetcher := NewFetcher(adapter, cacheDir)
+
+		first, firstErr := fetcher.Fetch(testURL, Options{})
+		second, secondErr := fetcher.Fetch(testURL, Options{})
+
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.False(t, first.Cached)
+		assert.True(t, second.Cached)
+		assert.Equal(t, first.Content, second.Content)
+		assert.Equal(t, first.SHA256, second.SHA256)
+		assert.Equal(t, "", adapter.requests[0].Header.Get(ifNoneMatchHeader))
+		assert.Equal(t, `"v1"`, adapter.requests[1].Header.Get(ifNoneMatchHeader))
+	})
+	t.Run("doesn't cache downloads failing checks", func(t *testing.T) {
+		cacheDir := filepath.Join(t.TempDir(), "cache")
+		adapter := &adapterMock{handler: func(_ *retryablehttp.Request) *http.Response {
+			return makeResponse(http.StatusOK, testContent, `"v1"`)
+		}}
+		fetcher := NewFetcher(adapter, cacheDir)
+
+		_, err := fetcher.Fetch(testURL, Options{SHA256: otherHash})
+
+		assert.Error(t, err)
+		assert.NoDirExists(t, cacheDir)
+	})
+}
+
+func TestFetcher_FetchSignature(t *testing.T) {
+	publicKey, privateKey, keyErr := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, keyErr)
+	signature := ed25519.Sign(privateKey, []byte(testContent))
+	tests := []struct {
+		Name        string
+		Signature   []byte
+		ExpectedErr string
+	}{
+		{"verifies raw signature", signature, ""},
+		{"verifies base64 signature", []byte(base64.StdEncoding.EncodeToString(signature) + "\n"), ""},
+		{"fails on invalid signature", ed25519.Sign(privateKey, []byte("other")), "signature of %s is not valid"},
+		{"fails on malformed signature", []byte("not a signature"), "signature %s.sig must be raw or base64 encoded"},
+		{"fails without signature", nil, "could not read signature of %s"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "mapping.json")
+			require.NoError(t, os.WriteFile(fileName, []byte(testContent), 0600))
+			if test.Signature != nil {
+				require.NoError(t, os.WriteFile(fileName+SignatureExtension, test.Signature, 0600))
+			}
+
+			_, err := NewFetcher(nil, "").Fetch(fileName, Options{PublicKey: publicKey})
+
+			if test.ExpectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, strings.ReplaceAll(test.ExpectedErr, "%s", fileName))
+			}
+		})
+	}
+}
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, IsRemote("https://example.com/mapping.json"))
+	assert.True(t, IsRemote("http://example.com/mapping.json"))
+	assert.False(t, IsRemote("mapping.json"))
+	assert.False(t, IsRemote("embedded"))
+}
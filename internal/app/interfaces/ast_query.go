@@ -1,11 +1,17 @@
 package interfaces
 
-import "github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+import (
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+)
 
 type ASTQueryProvider interface {
-	GetQueryID(language, name, group, sastQueryID string) (string, error)
+	GetQueryID(query queryid.Query) (string, error)
+	GetQueriesList() (*soap.GetQueryCollectionResponse, error)
 	GetCustomQueriesList() (*soap.GetQueryCollectionResponse, error)
 	GetCustomStatesList() (*soap.GetResultStateListResponse, error)
 	GetStateMapping() (map[string]string, error)
 	GetRawCustomStatesList() (*soap.GetResultStateListResponse, error)
+	IsMappedQuery(sastQueryID string) bool
+	ResolveQuery(query queryid.Query) (*queryid.Resolution, error)
 }
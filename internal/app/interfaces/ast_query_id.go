@@ -1,7 +1,10 @@
 package interfaces
 
+import "github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+
 type ASTQueryIDProvider interface {
-	GetQueryID(language, name, group, sastQueryID string) (string, error)
+	GetQueryID(query queryid.Query) (string, error)
+	ResolveQuery(query queryid.Query) (*queryid.Resolution, error)
 }
 
 type ASTQuery struct {
@@ -9,7 +9,6 @@ type ExcludeFile struct {
 }
 
 type SourceFile struct {
-	ResultID   string
 	RemoteName string
 	LocalName  string
 }
@@ -0,0 +1,90 @@
+package lineage
+
+import (
+	"sort"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/resultsmapping"
+)
+
+type (
+	// Branch is a project created by branching another project
+	Branch struct {
+		ProjectID         int
+		OriginalProjectID int
+		BranchedOnScanID  int
+	}
+
+	// Lineage has the branched projects and the projects they were branched from
+	Lineage struct {
+		branches map[int]Branch
+	}
+
+	// Overrides is the triage of a branched project that differs from the triage of the root project of its lineage
+	Overrides struct {
+		ProjectID         int                     `json:"projectId"`
+		OriginalProjectID int                     `json:"originalProjectId"`
+		RootProjectID     int                     `json:"rootProjectId"`
+		BranchedOnScanID  int                     `json:"branchedOnScanId"`
+		Results           []*resultsmapping.Entry `json:"results"`
+	}
+)
+
+// New creates a lineage of the given branches
+func New(branches []Branch) *Lineage {
+	out := &Lineage{branches: map[int]Branch{}}
+	for _, branch := range branches {
+		out.branches[branch.ProjectID] = branch
+	}
+	return out
+}
+
+// GetBranch returns how a project was branched, or false if it isn't a branched project
+func (l *Lineage) GetBranch(projectID int) (Branch, bool) {
+	branch, ok := l.branches[projectID]
+	return branch, ok
+}
+
+// GetRoot returns the first project of the lineage of a project, which is the project itself if it isn't a branch
+func (l *Lineage) GetRoot(projectID int) int {
+	visited := map[int]bool{}
+	for {
+		branch, ok := l.branches[projectID]
+		if !ok || visited[projectID] {
+			return projectID
+		}
+		visited[projectID] = true
+		projectID = branch.OriginalProjectID
+	}
+}
+
+// GetOverrides returns the results of a branched project triaged differently than in the root project of its lineage,
+// or not triaged in it. Results are matched by query and SAST similarity id.
+func (l *Lineage) GetOverrides(projectID int, root, branch []*resultsmapping.Entry) *Overrides {
+	branchInfo := l.branches[projectID]
+	out := &Overrides{
+		ProjectID:         projectID,
+		OriginalProjectID: branchInfo.OriginalProjectID,
+		RootProjectID:     l.GetRoot(projectID),
+		BranchedOnScanID:  branchInfo.BranchedOnScanID,
+		Results:           []*resultsmapping.Entry{},
+	}
+	rootEntries := map[string]*resultsmapping.Entry{}
+	for _, entry := range root {
+		rootEntries[getKey(entry)] = entry
+	}
+	for _, entry := range branch {
+		rootEntry, exists := rootEntries[getKey(entry)]
+		if exists && rootEntry.State == entry.State && rootEntry.Severity == entry.Severity && rootEntry.Assignee == entry.Assignee {
+			continue
+		}
+		out.Results = append(out.Results, entry)
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool {
+		return getKey(out.Results[i]) < getKey(out.Results[j])
+	})
+	return out
+}
+
+func getKey(entry *resultsmapping.Entry) string {
+	return entry.QueryID + "/" + entry.SASTSimilarityID
+}
@@ -0,0 +1,56 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/resultsmapping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineage_GetRoot(t *testing.T) {
+	lineage := New([]Branch{
+		{ProjectID: 2, OriginalProjectID: 1, BranchedOnScanID: 1000},
+		{ProjectID: 3, OriginalProjectID: 2, BranchedOnScanID: 1001},
+		{ProjectID: 4, OriginalProjectID: 5},
+		{ProjectID: 5, OriginalProjectID: 4},
+	})
+
+	assert.Equal(t, 1, lineage.GetRoot(1))
+	assert.Equal(t, 1, lineage.GetRoot(2))
+	assert.Equal(t, 1, lineage.GetRoot(3))
+	assert.Equal(t, 4, lineage.GetRoot(4), "cycles stop at the starting project")
+	branch, ok := lineage.GetBranch(3)
+	assert.True(t, ok)
+	assert.Equal(t, Branch{ProjectID: 3, OriginalProjectID: 2, BranchedOnScanID: 1001}, branch)
+	_, ok = lineage.GetBranch(1)
+	assert.False(t, ok)
+}
+
+func TestLineage_GetOverrides(t *testing.T) {
+	lineage := New([]Branch{
+		{ProjectID: 2, OriginalProjectID: 1, BranchedOnScanID: 1000},
+		{ProjectID: 3, OriginalProjectID: 2, BranchedOnScanID: 1001},
+	})
+	root := []*resultsmapping.Entry{
+		{QueryID: "10", SASTSimilarityID: "-1", State: "1", Severity: "High"},
+		{QueryID: "10", SASTSimilarityID: "-2", State: "2", Severity: "High"},
+		{QueryID: "11", SASTSimilarityID: "-3", State: "2", Severity: "Low", Assignee: "alice"},
+	}
+	branch := []*resultsmapping.Entry{
+		{ProjectID: "3", QueryID: "11", SASTSimilarityID: "-3", State: "2", Severity: "Low", Assignee: "bob"},
+		{ProjectID: "3", QueryID: "10", SASTSimilarityID: "-1", State: "1", Severity: "High"},
+		{ProjectID: "3", QueryID: "10", SASTSimilarityID: "-2", State: "3", Severity: "High"},
+		{ProjectID: "3", QueryID: "12", SASTSimilarityID: "-4", State: "1", Severity: "Medium"},
+	}
+
+	result := lineage.GetOverrides(3, root, branch)
+
+	expected := &Overrides{
+		ProjectID:         3,
+		OriginalProjectID: 2,
+		RootProjectID:     1,
+		BranchedOnScanID:  1001,
+		Results:           []*resultsmapping.Entry{branch[2], branch[0], branch[3]},
+	}
+	assert.Equal(t, expected, result)
+}
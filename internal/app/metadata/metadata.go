@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/report"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/worker"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/similarity"
@@ -27,6 +28,9 @@ type Factory struct {
 	simIDVersion         int
 	rmvDir               string
 	customExtensions     string
+	similarityJobs       chan SimilarityCalculationJob
+	startWorkersOnce     sync.Once
+	closeOnce            sync.Once
 }
 
 func NewMetadataFactory(
@@ -40,164 +44,79 @@ func NewMetadataFactory(
 	customExtensions string,
 ) *Factory {
 	return &Factory{
-		astQueryIDProvider,
-		similarityIDProvider,
-		sourceProvider,
-		methodLineProvider,
-		tmpDir,
-		simIDVersion,
-		rmvDir,
-		customExtensions,
+		astQueryIDProvider:   astQueryIDProvider,
+		similarityIDProvider: similarityIDProvider,
+		sourceProvider:       sourceProvider,
+		methodLineProvider:   methodLineProvider,
+		tmpDir:               tmpDir,
+		simIDVersion:         simIDVersion,
+		rmvDir:               rmvDir,
+		customExtensions:     customExtensions,
+		similarityJobs:       make(chan SimilarityCalculationJob),
 	}
 }
 
-//nolint:funlen,gocyclo
+// GetMetadataRecord calculates similarity ids for every triaged path of the given queries.
+// All queries are prepared one after the other, but their similarity calculations are
+// handed to the factory's shared worker pool as soon as they're ready, so the calculation
+// of one query overlaps with the preparation of the next one.
 func (e *Factory) GetMetadataRecord(scanID string, queries []*Query) (*Record, error) {
-	output := &Record{Queries: []*RecordQuery{}}
+	e.startWorkersOnce.Do(e.startWorkers)
 
-	for queryIdx, query := range queries {
-		output.Queries = append(output.Queries, &RecordQuery{QueryID: query.QueryID})
-		astQueryID, astQueryIDErr := e.astQueryIDProvider.GetQueryID(query.Language, query.Name, query.Group, query.QueryID)
-		if astQueryIDErr != nil {
-			return nil, errors.Wrapf(
-				astQueryIDErr,
-				"could not get AST query id for language %s, group %s, and name %s",
-				query.Language,
-				query.Group,
-				query.Name,
-			)
-		}
-		methodLinesByPath, methodLineErr := e.methodLineProvider.GetMethodLinesByPath(scanID, query.QueryID)
-		if methodLineErr != nil {
-			return nil, errors.Wrap(methodLineErr, "could not get method lines")
-		}
-		var filesToDownload []interfaces.SourceFile
-		fileMap := make(map[string]interfaces.SourceFile)
-		for _, result := range query.Results {
-			firstFile := filepath.Join(result.ResultID, result.FirstNode.FileName)
-			lastFile := filepath.Join(result.ResultID, result.LastNode.FileName)
-
-			if ok1 := findSourceFile(result.ResultID, firstFile, filesToDownload); ok1 == nil {
-				sf := interfaces.SourceFile{
-					ResultID:   result.ResultID,
-					RemoteName: result.FirstNode.FileName,
-					LocalName:  filepath.Join(e.tmpDir, result.ResultID, result.FirstNode.FileName),
-				}
-				filesToDownload = append(filesToDownload, sf)
-				fileMap[result.ResultID+"|"+result.FirstNode.FileName] = sf
-			}
+	output := &Record{Queries: make([]*RecordQuery, 0, len(queries)), ScanID: scanID}
 
-			if ok2 := findSourceFile(result.ResultID, lastFile, filesToDownload); ok2 == nil {
-				sf := interfaces.SourceFile{
-					ResultID:   result.ResultID,
-					RemoteName: result.LastNode.FileName,
-					LocalName:  filepath.Join(e.tmpDir, result.ResultID, result.LastNode.FileName),
-				}
-				filesToDownload = append(filesToDownload, sf)
-				fileMap[result.ResultID+"|"+result.LastNode.FileName] = sf
-			}
-		}
-		downloadErr := e.sourceProvider.DownloadSourceFiles(scanID, filesToDownload, e.rmvDir)
-		if downloadErr != nil {
-			return nil, errors.Wrap(downloadErr, "could not download source code")
+	var pendingJobs sync.WaitGroup
+	similarityCalculationResults := make(chan SimilarityCalculationResult)
+	resultsByQuery := make([][]SimilarityCalculationResult, len(queries))
+	collectDone := make(chan struct{})
+	go func() {
+		for r := range similarityCalculationResults {
+			resultsByQuery[r.QueryIndex] = append(resultsByQuery[r.QueryIndex], r)
+			pendingJobs.Done()
 		}
+		close(collectDone)
+	}()
 
-		// produce calculation jobs
-		similarityCalculationJobs := make(chan SimilarityCalculationJob)
-		q := query
+	var prepareErr error
+	for queryIdx, query := range queries {
+		recordQuery := &RecordQuery{QueryID: query.QueryID}
+		output.Queries = append(output.Queries, recordQuery)
+		jobs, astQueryID, jobsErr := e.getSimilarityCalculationJobs(scanID, queryIdx, query, similarityCalculationResults)
+		if jobsErr != nil {
+			prepareErr = jobsErr
+			break
+		}
+		recordQuery.AstQueryID = astQueryID
+		pendingJobs.Add(len(jobs))
 		go func() {
-			for _, result := range q.Results {
-				firstSourceFile := fileMap[result.ResultID+"|"+result.FirstNode.FileName]
-				lastSourceFile := fileMap[result.ResultID+"|"+result.LastNode.FileName]
-				resultPath := findResultPath(result.PathID, methodLinesByPath)
-				if resultPath == nil {
-					log.Info().Msgf("Result path not found for ID: %s, on file name: %s and pathId %s",
-						result.ResultID, result.FirstNode.FileName, result.PathID)
-					continue
-				}
-				methodLines := resultPath.MethodLines
-				similarityCalculationJobs <- SimilarityCalculationJob{
-					result.ResultID, result.PathID,
-					firstSourceFile.LocalName, result.FirstNode.Name, result.FirstNode.Line, result.FirstNode.Column, methodLines[0],
-					lastSourceFile.LocalName, result.LastNode.Name, result.LastNode.Line, result.LastNode.Column, methodLines[len(methodLines)-1],
-					astQueryID, e.simIDVersion,
-				}
+			for i := range jobs {
+				e.similarityJobs <- jobs[i]
 			}
-			close(similarityCalculationJobs)
 		}()
+	}
 
-		// consume calculation jobs
-		var resultsMutex sync.Mutex
-		similarityCalculationResults := make([]SimilarityCalculationResult, 0, len(query.Results))
-		var wg sync.WaitGroup
-		for consumerID := 1; consumerID <= worker.GetNumCPU(); consumerID++ {
-			wg.Add(1)
-			// Worker goroutine
-			go func() {
-				defer wg.Done()
-				for job := range similarityCalculationJobs {
-					similarityID, similarityIDErr := e.similarityIDProvider.Calculate(
-						job.Filename1, job.Name1, job.Line1, job.Column1, job.MethodLine1,
-						job.Filename2, job.Name2, job.Line2, job.Column2, job.MethodLine2,
-						job.QueryID,
-						job.SimIDVersion,
-					)
-					result := SimilarityCalculationResult{
-						ResultID:     job.ResultID,
-						PathID:       job.PathID,
-						SimilarityID: similarityID,
-						Err:          similarityIDErr,
-					}
-					resultsMutex.Lock()
-					similarityCalculationResults = append(similarityCalculationResults, result)
-					resultsMutex.Unlock()
-				}
-			}()
-		}
+	// wait for the jobs already handed to the pool, even if preparation failed
+	// midway, so no worker is left blocked writing to the results channel
+	go func() {
+		pendingJobs.Wait()
+		close(similarityCalculationResults)
+	}()
+	<-collectDone
 
-		// Wait for all workers to finish
-		wg.Wait()
+	if prepareErr != nil {
+		return nil, prepareErr
+	}
 
-		// Sort results by ResultID and PathID
-		sort.Slice(similarityCalculationResults, func(i, j int) bool {
-			if similarityCalculationResults[i].ResultID == similarityCalculationResults[j].ResultID {
-				return similarityCalculationResults[i].PathID < similarityCalculationResults[j].PathID
-			}
-			return similarityCalculationResults[i].ResultID < similarityCalculationResults[j].ResultID
-		})
-		// build lookup maps for O(1) access during result handling
-		recordResultByID := make(map[string]*RecordResult)
-		recordPathByKey := make(map[string]*RecordPath)
-		origSimByKey := make(map[string]string)
+	for queryIdx, query := range queries {
+		origByKey := make(map[string]*Result, len(query.Results))
 		for _, orig := range query.Results {
-			origSimByKey[orig.ResultID+"|"+orig.PathID] = orig.SimilarityID
+			origByKey[getPathKey(orig.ResultID, orig.PathID)] = orig
 		}
-
-		// handle calculation results
-		for _, r := range similarityCalculationResults {
-			if r.Err != nil {
-				return nil, errors.Wrap(r.Err, "failed calculating similarity id")
-			}
-
-			recordResult, exists := recordResultByID[r.ResultID]
-			if !exists {
-				recordResult = &RecordResult{ResultID: r.ResultID}
-				output.Queries[queryIdx].Results = append(output.Queries[queryIdx].Results, recordResult)
-				recordResultByID[r.ResultID] = recordResult
-			}
-
-			pathKey := r.ResultID + "|" + r.PathID
-			if _, exists := recordPathByKey[pathKey]; !exists {
-				recordPath := &RecordPath{
-					PathID:           r.PathID,
-					SimilarityID:     r.SimilarityID,
-					ResultID:         r.ResultID,
-					SASTSimilarityID: origSimByKey[pathKey],
-				}
-				recordResult.Paths = append(recordResult.Paths, recordPath)
-				recordPathByKey[pathKey] = recordPath
-			}
+		recordResults, recordErr := getRecordResults(resultsByQuery[queryIdx], origByKey)
+		if recordErr != nil {
+			return nil, recordErr
 		}
+		output.Queries[queryIdx].Results = recordResults
 
 		// Sort query.Results by ResultID and PathID
 		sort.Slice(query.Results, func(i, j int) bool {
@@ -211,22 +130,187 @@ func (e *Factory) GetMetadataRecord(scanID string, queries []*Query) (*Record, e
 	return output, nil
 }
 
-func findSourceFile(resultID, remoteName string, sourceFiles []interfaces.SourceFile) *interfaces.SourceFile {
-	for _, v := range sourceFiles {
-		if v.RemoteName == remoteName && v.ResultID == resultID {
-			return &v
+// Close stops the similarity calculation workers.
+// It must only be called once no more metadata records will be requested.
+func (e *Factory) Close() {
+	e.closeOnce.Do(func() {
+		close(e.similarityJobs)
+	})
+}
+
+// startWorkers starts the similarity calculation pool shared by all queries and scans
+func (e *Factory) startWorkers() {
+	for consumerID := 1; consumerID <= worker.GetNumCPU(); consumerID++ {
+		go func() {
+			for job := range e.similarityJobs {
+				similarityID, similarityIDErr := e.similarityIDProvider.Calculate(
+					job.Filename1, job.Name1, job.Line1, job.Column1, job.MethodLine1,
+					job.Filename2, job.Name2, job.Line2, job.Column2, job.MethodLine2,
+					job.QueryID,
+					job.SimIDVersion,
+				)
+				job.Output <- SimilarityCalculationResult{
+					QueryIndex:   job.QueryIndex,
+					ResultID:     job.ResultID,
+					PathID:       job.PathID,
+					SimilarityID: similarityID,
+					Err:          similarityIDErr,
+				}
+			}
+		}()
+	}
+}
+
+// getSimilarityCalculationJobs fetches everything needed to calculate the similarity ids of a query's results,
+// and returns them with the AST query id of the query
+func (e *Factory) getSimilarityCalculationJobs(
+	scanID string, queryIdx int, query *Query, output chan<- SimilarityCalculationResult,
+) ([]SimilarityCalculationJob, string, error) {
+	astQuery, astQueryIDErr := e.astQueryIDProvider.ResolveQuery(queryid.Query{
+		Language:    query.Language,
+		Group:       query.Group,
+		Name:        query.Name,
+		SastQueryID: query.QueryID,
+		QueryPath:   query.QueryPath,
+	})
+	if astQueryIDErr != nil {
+		return nil, "", errors.Wrapf(
+			astQueryIDErr,
+			"could not get AST query id for language %s, group %s, and name %s",
+			query.Language,
+			query.Group,
+			query.Name,
+		)
+	}
+	methodLinesByPath, methodLineErr := e.methodLineProvider.GetMethodLinesByPath(scanID, query.QueryID)
+	if methodLineErr != nil {
+		return nil, "", errors.Wrap(methodLineErr, "could not get method lines")
+	}
+	resultPathByID := getResultPathIndex(methodLinesByPath)
+
+	// sources are stored once per scan and addressed by their remote path,
+	// so files shared by several results and queries are only fetched once
+	scanDir := filepath.Join(e.tmpDir, scanID)
+	filesToDownload := make([]interfaces.SourceFile, 0, len(query.Results))
+	fileMap := make(map[string]interfaces.SourceFile, len(query.Results))
+	addSourceFile := func(fileName string) {
+		if _, exists := fileMap[fileName]; exists {
+			return
+		}
+		sf := interfaces.SourceFile{
+			RemoteName: fileName,
+			LocalName:  filepath.Join(scanDir, fileName),
 		}
+		filesToDownload = append(filesToDownload, sf)
+		fileMap[fileName] = sf
 	}
-	return nil
+	for _, result := range query.Results {
+		addSourceFile(result.FirstNode.FileName)
+		addSourceFile(result.LastNode.FileName)
+	}
+	downloadErr := e.sourceProvider.DownloadSourceFiles(scanID, filesToDownload, e.rmvDir)
+	if downloadErr != nil {
+		return nil, "", errors.Wrap(downloadErr, "could not download source code")
+	}
+
+	jobs := make([]SimilarityCalculationJob, 0, len(query.Results))
+	for _, result := range query.Results {
+		resultPath, exists := resultPathByID[result.PathID]
+		if !exists || len(resultPath.MethodLines) == 0 {
+			log.Info().Msgf("Result path not found for ID: %s, on file name: %s and pathId %s",
+				result.ResultID, result.FirstNode.FileName, result.PathID)
+			continue
+		}
+		firstSourceFile := fileMap[result.FirstNode.FileName]
+		lastSourceFile := fileMap[result.LastNode.FileName]
+		methodLines := resultPath.MethodLines
+		jobs = append(jobs, SimilarityCalculationJob{
+			ResultID:     result.ResultID,
+			PathID:       result.PathID,
+			Filename1:    firstSourceFile.LocalName,
+			Name1:        result.FirstNode.Name,
+			Line1:        result.FirstNode.Line,
+			Column1:      result.FirstNode.Column,
+			MethodLine1:  methodLines[0],
+			Filename2:    lastSourceFile.LocalName,
+			Name2:        result.LastNode.Name,
+			Line2:        result.LastNode.Line,
+			Column2:      result.LastNode.Column,
+			MethodLine2:  methodLines[len(methodLines)-1],
+			QueryID:      astQuery.AstQueryID,
+			SimIDVersion: e.simIDVersion,
+			QueryIndex:   queryIdx,
+			Output:       output,
+		})
+	}
+	return jobs, astQuery.AstQueryID, nil
 }
 
-func findResultPath(pathID string, methodLines []*interfaces.ResultPath) *interfaces.ResultPath {
-	for _, v := range methodLines {
-		if v != nil && strings.TrimSpace(v.PathID) == pathID {
-			return v
+// getRecordResults groups calculated similarity ids by result, sorted by ResultID and PathID
+func getRecordResults(results []SimilarityCalculationResult, origByKey map[string]*Result) ([]*RecordResult, error) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].ResultID == results[j].ResultID {
+			return results[i].PathID < results[j].PathID
+		}
+		return results[i].ResultID < results[j].ResultID
+	})
+
+	var output []*RecordResult
+	recordResultByID := make(map[string]*RecordResult)
+	recordPathByKey := make(map[string]*RecordPath, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, errors.Wrap(r.Err, "failed calculating similarity id")
+		}
+
+		recordResult, exists := recordResultByID[r.ResultID]
+		if !exists {
+			recordResult = &RecordResult{ResultID: r.ResultID}
+			output = append(output, recordResult)
+			recordResultByID[r.ResultID] = recordResult
+		}
+
+		pathKey := getPathKey(r.ResultID, r.PathID)
+		if _, exists := recordPathByKey[pathKey]; !exists {
+			recordPath := &RecordPath{
+				PathID:       r.PathID,
+				SimilarityID: r.SimilarityID,
+				ResultID:     r.ResultID,
+			}
+			if orig, exists := origByKey[pathKey]; exists {
+				recordPath.SASTSimilarityID = orig.SimilarityID
+				recordPath.OriginalSeverity = orig.OriginalSeverity
+				recordPath.Severity = orig.Severity
+				recordPath.State = orig.State
+				recordPath.Assignee = orig.Assignee
+				recordPath.DetectionDate = orig.DetectionDate
+				recordPath.FileName = orig.FirstNode.FileName
+				recordPath.Line = orig.FirstNode.Line
+			}
+			recordResult.Paths = append(recordResult.Paths, recordPath)
+			recordPathByKey[pathKey] = recordPath
 		}
 	}
-	return nil
+	return output, nil
+}
+
+// getResultPathIndex indexes result paths by their trimmed path id; the first occurrence wins
+func getResultPathIndex(resultPaths []*interfaces.ResultPath) map[string]*interfaces.ResultPath {
+	output := make(map[string]*interfaces.ResultPath, len(resultPaths))
+	for _, v := range resultPaths {
+		if v == nil {
+			continue
+		}
+		pathID := strings.TrimSpace(v.PathID)
+		if _, exists := output[pathID]; !exists {
+			output[pathID] = v
+		}
+	}
+	return output
+}
+
+func getPathKey(resultID, pathID string) string {
+	return resultID + "|" + pathID
 }
 
 func GetQueriesFromReport(reportReader *report.CxXMLResults) []*Query {
@@ -234,10 +318,11 @@ func GetQueriesFromReport(reportReader *report.CxXMLResults) []*Query {
 	for i := 0; i < len(reportReader.Queries); i++ {
 		q := reportReader.Queries[i]
 		query := &Query{
-			QueryID:  q.ID,
-			Name:     q.Name,
-			Language: q.Language,
-			Group:    q.Group,
+			QueryID:   q.ID,
+			Name:      q.Name,
+			Language:  q.Language,
+			Group:     q.Group,
+			QueryPath: q.QueryPath,
 		}
 		for j := 0; j < len(q.Results); j++ {
 			r := q.Results[j]
@@ -250,9 +335,14 @@ func GetQueriesFromReport(reportReader *report.CxXMLResults) []*Query {
 				firstNode := p.PathNodes[0]
 				lastNode := p.PathNodes[len(p.PathNodes)-1]
 				query.Results = append(query.Results, &Result{
-					ResultID:     p.ResultID,
-					PathID:       p.PathID,
-					SimilarityID: p.SimilarityID,
+					ResultID:         p.ResultID,
+					PathID:           p.PathID,
+					SimilarityID:     p.SimilarityID,
+					OriginalSeverity: q.Severity,
+					Severity:         r.Severity,
+					State:            r.State,
+					Assignee:         r.AssignToUser,
+					DetectionDate:    r.DetectionDate,
 					FirstNode: Node{
 						FileName: firstNode.FileName,
 						Name:     firstNode.Name,
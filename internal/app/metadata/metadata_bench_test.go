@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+)
+
+const benchmarkPathCount = 50000
+
+type benchmarkQueryIDProvider struct{}
+
+func (e *benchmarkQueryIDProvider) GetQueryID(_ queryid.Query) (string, error) {
+	return "12532796926860742976", nil
+}
+
+func (e *benchmarkQueryIDProvider) ResolveQuery(_ queryid.Query) (*queryid.Resolution, error) {
+	return &queryid.Resolution{AstQueryID: "12532796926860742976", Source: queryid.HashSource}, nil
+}
+
+type benchmarkSimilarityIDProvider struct{}
+
+func (e *benchmarkSimilarityIDProvider) Calculate(
+	_, _, _, _, _,
+	_, _, _, _, _,
+	_ string,
+	_ int,
+) (string, error) {
+	return "1234567890", nil
+}
+
+type benchmarkSourceFileRepo struct{}
+
+func (e *benchmarkSourceFileRepo) DownloadSourceFiles(_ string, _ []interfaces.SourceFile, _ string) error {
+	return nil
+}
+
+type benchmarkMethodLineRepo struct {
+	pathsByQuery map[string][]*interfaces.ResultPath
+}
+
+func (e *benchmarkMethodLineRepo) GetMethodLines(_, _, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (e *benchmarkMethodLineRepo) GetMethodLinesByPath(_, queryID string) ([]*interfaces.ResultPath, error) {
+	return e.pathsByQuery[queryID], nil
+}
+
+// getBenchmarkReport generates pathCount triaged paths spread over queryCount queries,
+// with several paths per result and files shared between results like a real report
+func getBenchmarkReport(queryCount, pathCount int) ([]*Query, *benchmarkMethodLineRepo) {
+	pathsPerResult := 5
+	filesCount := 500
+	queries := make([]*Query, 0, queryCount)
+	methodLineRepo := &benchmarkMethodLineRepo{pathsByQuery: map[string][]*interfaces.ResultPath{}}
+	for q := 0; q < queryCount; q++ {
+		queryID := fmt.Sprintf("%d", 1000+q)
+		query := &Query{QueryID: queryID, Language: "Java", Name: fmt.Sprintf("Query_%d", q), Group: "Java_High_Risk"}
+		for p := q; p < pathCount; p += queryCount {
+			pathID := fmt.Sprintf("%d", p)
+			query.Results = append(query.Results, &Result{
+				PathID:       pathID,
+				ResultID:     fmt.Sprintf("%d", p/pathsPerResult),
+				SimilarityID: "-1234567890",
+				FirstNode:    Node{FileName: fmt.Sprintf("src/file%d.java", p%filesCount), Name: "a", Line: "1", Column: "1"},
+				LastNode:     Node{FileName: fmt.Sprintf("src/file%d.java", (p+1)%filesCount), Name: "b", Line: "2", Column: "2"},
+			})
+			methodLineRepo.pathsByQuery[queryID] = append(methodLineRepo.pathsByQuery[queryID], &interfaces.ResultPath{
+				PathID:      pathID,
+				MethodLines: []string{"10", "20"},
+			})
+		}
+		queries = append(queries, query)
+	}
+	return queries, methodLineRepo
+}
+
+func BenchmarkFactory_GetMetadataRecord(b *testing.B) {
+	for _, queryCount := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("%d paths in %d queries", benchmarkPathCount, queryCount), func(b *testing.B) {
+			queries, methodLineRepo := getBenchmarkReport(queryCount, benchmarkPathCount)
+			factory := NewMetadataFactory(
+				&benchmarkQueryIDProvider{},
+				&benchmarkSimilarityIDProvider{},
+				&benchmarkSourceFileRepo{},
+				methodLineRepo,
+				b.TempDir(),
+				0,
+				"",
+				"",
+			)
+			defer factory.Close()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := factory.GetMetadataRecord("1000000", queries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetResultPathIndex(b *testing.B) {
+	queries, methodLineRepo := getBenchmarkReport(1, benchmarkPathCount)
+	resultPaths := methodLineRepo.pathsByQuery[queries[0].QueryID]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index := getResultPathIndex(resultPaths)
+		for _, result := range queries[0].Results {
+			if _, exists := index[result.PathID]; !exists {
+				b.Fatalf("path %s not found", result.PathID)
+			}
+		}
+	}
+}
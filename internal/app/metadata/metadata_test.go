@@ -1,10 +1,13 @@
 package metadata
 
 import (
+	"fmt"
+	"path/filepath"
 	"sort"
 	"testing"
 
 	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 
 	mock_app_ast_query_id "github.com/checkmarxDev/ast-sast-export/test/mocks/app/ast_query_id"
 	mock_app_method_line "github.com/checkmarxDev/ast-sast-export/test/mocks/app/method_line"
@@ -46,6 +49,11 @@ func TestMetadataFactory_GetMetadataForQueryAndResult(t *testing.T) {
 			Line:     "129",
 			Column:   "28",
 		},
+		OriginalSeverity: "High",
+		Severity:         "Medium",
+		State:            "1",
+		Assignee:         "alice",
+		DetectionDate:    "4/21/2022 7:26:26 PM",
 	}
 	metaResult2Data := testResultData{
 		SimilarityID: "9492845843",
@@ -69,17 +77,26 @@ func TestMetadataFactory_GetMetadataForQueryAndResult(t *testing.T) {
 		},
 	}
 	metaQuery := &Query{
-		QueryID:  "6300",
-		Language: "Kotlin",
-		Name:     "SQL_Injection",
-		Group:    "Kotlin_High_Risk",
-		Results:  []*Result{&metaResult1, &metaResult2},
+		QueryID:   "6300",
+		Language:  "Kotlin",
+		Name:      "SQL_Injection",
+		Group:     "Kotlin_High_Risk",
+		QueryPath: `Kotlin\Cx\Kotlin High Risk\SQL Injection Version:1`,
+		Results:   []*Result{&metaResult1, &metaResult2},
 	}
 
 	ctrl := gomock.NewController(t)
 	tmpDir := t.TempDir()
 	astQueryIDProviderMock := mock_app_ast_query_id.NewMockASTQueryIDProvider(ctrl)
-	astQueryIDProviderMock.EXPECT().GetQueryID(metaQuery.Language, metaQuery.Name, metaQuery.Group, metaQuery.QueryID).Return(astQueryID, nil)
+	astQueryIDProviderMock.EXPECT().
+		ResolveQuery(queryid.Query{
+			Language:    metaQuery.Language,
+			Group:       metaQuery.Group,
+			Name:        metaQuery.Name,
+			SastQueryID: metaQuery.QueryID,
+			QueryPath:   metaQuery.QueryPath,
+		}).
+		Return(&queryid.Resolution{AstQueryID: astQueryID, Source: queryid.HashSource}, nil)
 	similarityIDProviderMock := mock_integration_similarity.NewMockIDProvider(ctrl)
 	similarityIDProviderMock.EXPECT().Calculate(
 		gomock.Any(), metaResult1.FirstNode.Name, metaResult1.FirstNode.Line, metaResult1.FirstNode.Column, metaResult1Data.MethodLines[0],
@@ -105,6 +122,7 @@ func TestMetadataFactory_GetMetadataForQueryAndResult(t *testing.T) {
 				var result []string
 				for _, v := range files {
 					result = append(result, v.RemoteName)
+					assert.Equal(t, filepath.Join(tmpDir, scanID, v.RemoteName), v.LocalName)
 				}
 				assert.ElementsMatch(t, expectedFiles, result)
 				assert.Equal(t, "", rmvdir)
@@ -120,6 +138,7 @@ func TestMetadataFactory_GetMetadataForQueryAndResult(t *testing.T) {
 		GetMethodLinesByPath(scanID, metaQuery.QueryID).
 		Return(methodLinesResult, nil)
 	metadata := NewMetadataFactory(astQueryIDProviderMock, similarityIDProviderMock, sourceProviderMock, methodLineProvider, tmpDir, 0, "", "")
+	defer metadata.Close()
 
 	result, err := metadata.GetMetadataRecord(scanID, []*Query{metaQuery})
 	assert.NoError(t, err)
@@ -137,18 +156,29 @@ func TestMetadataFactory_GetMetadataForQueryAndResult(t *testing.T) {
 								SimilarityID:     metaResult1Data.SimilarityID,
 								ResultID:         metaResult1.ResultID,
 								SASTSimilarityID: similarityID1,
+								OriginalSeverity: "High",
+								Severity:         "Medium",
+								State:            "1",
+								Assignee:         "alice",
+								DetectionDate:    "4/21/2022 7:26:26 PM",
+								FileName:         metaResult1.FirstNode.FileName,
+								Line:             "83",
 							},
 							{
 								PathID:           metaResult2.PathID,
 								SimilarityID:     metaResult2Data.SimilarityID,
 								ResultID:         metaResult2.ResultID,
 								SASTSimilarityID: similarityID2,
+								FileName:         "path/file1.kt",
+								Line:             "83",
 							},
 						},
 					},
 				},
+				AstQueryID: astQueryID,
 			},
 		},
+		ScanID: scanID,
 	}
 
 	sortRecordPaths(expectedResult)
@@ -166,3 +196,79 @@ func sortRecordPaths(record *Record) {
 		}
 	}
 }
+
+func TestMetadataFactory_GetMetadataRecordFailsIfAnyQueryFails(t *testing.T) {
+	scanID := "1000001"
+	firstQuery := &Query{
+		QueryID:  "6300",
+		Language: "Kotlin",
+		Name:     "SQL_Injection",
+		Group:    "Kotlin_High_Risk",
+		Results: []*Result{
+			{
+				PathID:    "2",
+				ResultID:  "1000002",
+				FirstNode: Node{FileName: "path/file1.kt", Name: "text", Line: "83", Column: "78"},
+				LastNode:  Node{FileName: "path/file1.kt", Name: "note", Line: "129", Column: "28"},
+			},
+		},
+	}
+	secondQuery := &Query{
+		QueryID:  "6301",
+		Language: "Kotlin",
+		Name:     "Code_Injection",
+		Group:    "Kotlin_High_Risk",
+	}
+
+	ctrl := gomock.NewController(t)
+	astQueryIDProviderMock := mock_app_ast_query_id.NewMockASTQueryIDProvider(ctrl)
+	astQueryIDProviderMock.EXPECT().
+		ResolveQuery(queryid.Query{
+			Language:    firstQuery.Language,
+			Group:       firstQuery.Group,
+			Name:        firstQuery.Name,
+			SastQueryID: firstQuery.QueryID,
+		}).
+		Return(&queryid.Resolution{AstQueryID: "1", Source: queryid.HashSource}, nil)
+	astQueryIDProviderMock.EXPECT().
+		ResolveQuery(queryid.Query{
+			Language:    secondQuery.Language,
+			Group:       secondQuery.Group,
+			Name:        secondQuery.Name,
+			SastQueryID: secondQuery.QueryID,
+		}).
+		Return(nil, fmt.Errorf("failed getting query id"))
+	similarityIDProviderMock := mock_integration_similarity.NewMockIDProvider(ctrl)
+	similarityIDProviderMock.EXPECT().
+		Calculate(
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(),
+		).
+		Return("1234", nil)
+	sourceProviderMock := mock_app_source_file.NewMockSourceFileRepo(ctrl)
+	sourceProviderMock.EXPECT().
+		DownloadSourceFiles(scanID, gomock.Any(), gomock.Eq("")).
+		DoAndReturn(func(_ string, files []interfaces.SourceFile, _ string) error {
+			// first and last node share the same file, so it's only downloaded once
+			assert.Len(t, files, 1)
+			return nil
+		})
+	methodLineProvider := mock_app_method_line.NewMockMethodLineRepo(ctrl)
+	methodLineProvider.EXPECT().
+		GetMethodLinesByPath(scanID, firstQuery.QueryID).
+		Return([]*interfaces.ResultPath{{PathID: "2", MethodLines: []string{"1", "2"}}}, nil)
+	metadata := NewMetadataFactory(
+		astQueryIDProviderMock, similarityIDProviderMock, sourceProviderMock, methodLineProvider, t.TempDir(), 0, "", "",
+	)
+	defer metadata.Close()
+
+	result, err := metadata.GetMetadataRecord(scanID, []*Query{firstQuery, secondQuery})
+
+	assert.Nil(t, result)
+	assert.EqualError(
+		t,
+		err,
+		"could not get AST query id for language Kotlin, group Kotlin_High_Risk, and name Code_Injection: failed getting query id",
+	)
+}
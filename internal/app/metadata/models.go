@@ -2,12 +2,15 @@ package metadata
 
 type (
 	Record struct {
-		Queries []*RecordQuery `json:"queries"`
+		Queries   []*RecordQuery `json:"queries"`
+		ProjectID string         `json:"-"`
+		ScanID    string         `json:"-"`
 	}
 
 	RecordQuery struct {
-		QueryID string          `json:"queryId"`
-		Results []*RecordResult `json:"results"`
+		QueryID    string          `json:"queryId"`
+		Results    []*RecordResult `json:"results"`
+		AstQueryID string          `json:"-"`
 	}
 
 	RecordResult struct {
@@ -20,14 +23,23 @@ type (
 		SimilarityID     string `json:"similarityId"`
 		ResultID         string `json:"-"`
 		SASTSimilarityID string `json:"-"`
+		// triage of the result and location of its first node, for the results mapping
+		OriginalSeverity string `json:"-"`
+		Severity         string `json:"-"`
+		State            string `json:"-"`
+		Assignee         string `json:"-"`
+		DetectionDate    string `json:"-"`
+		FileName         string `json:"-"`
+		Line             string `json:"-"`
 	}
 
 	Query struct {
-		QueryID  string
-		Language string
-		Name     string
-		Group    string
-		Results  []*Result
+		QueryID   string
+		Language  string
+		Name      string
+		Group     string
+		QueryPath string
+		Results   []*Result
 	}
 
 	Result struct {
@@ -36,6 +48,12 @@ type (
 		SimilarityID string
 		FirstNode    Node
 		LastNode     Node
+		// OriginalSeverity is the severity of the query, Severity the one the result was triaged to
+		OriginalSeverity string
+		Severity         string
+		State            string
+		Assignee         string
+		DetectionDate    string
 	}
 
 	Node struct {
@@ -51,10 +69,13 @@ type (
 		Filename2, Name2, Line2, Column2, MethodLine2,
 		QueryID string
 		SimIDVersion int
+		QueryIndex   int
+		Output       chan<- SimilarityCalculationResult
 	}
 
 	SimilarityCalculationResult struct {
 		Err                            error
+		QueryIndex                     int
 		ResultID, PathID, SimilarityID string
 	}
 )
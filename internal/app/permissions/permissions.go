@@ -15,6 +15,8 @@ const (
 	manageRolesPermission        = "manage-roles"
 	manageSystemSettings         = "manage-system-settings"
 	viewResults                  = "view-results"
+	updateAndDeleteProject       = "update-and-delete-project"
+	manageDataRetention          = "manage-data-retention"
 )
 
 var permissionDescription = map[interface{}]string{
@@ -24,6 +26,8 @@ var permissionDescription = map[interface{}]string{
 	manageSystemSettings:         "Sast > System Configuration > Manage System Settings",
 	manageAuthProviderPermission: "Access Control > General > Manage Authentication Providers",
 	manageRolesPermission:        "Access Control > General > Manage Roles",
+	updateAndDeleteProject:       "Sast > Projects & Scans > Update & Delete Project",
+	manageDataRetention:          "Sast > Data Retention > Manage Data Retention",
 }
 
 func GetFromExportOptions(exportOptions []string) []interface{} {
@@ -32,6 +36,9 @@ func GetFromExportOptions(exportOptions []string) []interface{} {
 	usersPermissions := []string{manageAuthProviderPermission, manageRolesPermission}
 	teamsPermissions := []string{manageAuthProviderPermission}
 	resultsPermissions := []string{useOdataPermission, generateScanReportPermission, viewResults}
+	projectSettingsPermissions := []string{updateAndDeleteProject, manageDataRetention}
+	scansPermissions := []string{useOdataPermission}
+	systemPermissions := []string{manageAuthProviderPermission, manageDataRetention}
 
 	for _, exportOption := range exportOptions {
 		switch exportOption {
@@ -41,6 +48,12 @@ func GetFromExportOptions(exportOptions []string) []interface{} {
 			output = append(output, teamsPermissions...)
 		case export.ResultsOption:
 			output = append(output, resultsPermissions...)
+		case export.ProjectSettingsOption:
+			output = append(output, projectSettingsPermissions...)
+		case export.ScansOption:
+			output = append(output, scansPermissions...)
+		case export.SystemOption:
+			output = append(output, systemPermissions...)
 		}
 	}
 	return sliceutils.Unique(sliceutils.ConvertStringToInterface(output))
@@ -74,6 +74,40 @@ func TestGetFromExportOptions(t *testing.T) {
 		}
 		assert.ElementsMatch(t, expected, result)
 	})
+
+	t.Run("project settings case", func(t *testing.T) {
+		exportOptions := []string{export.ProjectSettingsOption}
+		result := GetFromExportOptions(exportOptions)
+
+		expected := []interface{}{updateAndDeleteProject, manageDataRetention, manageSystemSettings}
+		assert.ElementsMatch(t, expected, result)
+	})
+
+	t.Run("scans case", func(t *testing.T) {
+		exportOptions := []string{export.ScansOption}
+		result := GetFromExportOptions(exportOptions)
+
+		expected := []interface{}{useOdataPermission, manageSystemSettings}
+		assert.ElementsMatch(t, expected, result)
+	})
+
+	t.Run("default options case", func(t *testing.T) {
+		result := GetFromExportOptions(export.GetDefaultOptions())
+
+		expected := []interface{}{
+			manageAuthProviderPermission, manageRolesPermission, useOdataPermission, generateScanReportPermission,
+			viewResults, manageSystemSettings,
+		}
+		assert.ElementsMatch(t, expected, result)
+	})
+
+	t.Run("system case", func(t *testing.T) {
+		exportOptions := []string{export.SystemOption}
+		result := GetFromExportOptions(exportOptions)
+
+		expected := []interface{}{manageAuthProviderPermission, manageDataRetention, manageSystemSettings}
+		assert.ElementsMatch(t, expected, result)
+	})
 }
 
 func TestGetAllFromJwtClaims(t *testing.T) {
@@ -183,6 +217,8 @@ func TestGetDescription(t *testing.T) {
 		{viewResults, "Sast > Scan Results > View Results"},
 		{manageAuthProviderPermission, "Access Control > General > Manage Authentication Providers"},
 		{manageRolesPermission, "Access Control > General > Manage Roles"},
+		{updateAndDeleteProject, "Sast > Projects & Scans > Update & Delete Project"},
+		{manageDataRetention, "Sast > Data Retention > Manage Data Retention"},
 	}
 	for _, test := range successTests {
 		testName := fmt.Sprintf("succeeds if %s exists", test[0])
@@ -0,0 +1,131 @@
+package preset
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/pkg/errors"
+)
+
+type (
+	// CatalogPreset is a CxOne preset and the AST query ids it has
+	CatalogPreset struct {
+		Name     string   `json:"name"`
+		QueryIDs []string `json:"queryIds"`
+	}
+
+	// Catalog is the list of presets available in CxOne
+	Catalog struct {
+		Presets []CatalogPreset `json:"presets"`
+	}
+
+	// Diff compares a SAST preset with the CxOne presets of a catalog.
+	// Missing and extra queries are relative to the catalog preset with the same name, if any.
+	Diff struct {
+		ID                 int      `json:"id"`
+		Name               string   `json:"name"`
+		Reproducible       bool     `json:"reproducible"`
+		IdenticalPresets   []string `json:"identicalPresets"`
+		CatalogPreset      string   `json:"catalogPreset,omitempty"`
+		MissingQueryIDs    []string `json:"missingQueryIds"`
+		ExtraQueryIDs      []string `json:"extraQueryIds"`
+		UnresolvedQueryIDs []int    `json:"unresolvedQueryIds"`
+	}
+)
+
+// LoadCatalog reads the CxOne preset catalog file, or returns nil if no file is given
+func LoadCatalog(fileName string) (*Catalog, error) {
+	if fileName == "" {
+		return nil, nil
+	}
+	data, ioErr := os.ReadFile(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read preset catalog file")
+	}
+	var catalog Catalog
+	if jsonErr := json.Unmarshal(data, &catalog); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse preset catalog file")
+	}
+	for i, e := range catalog.Presets {
+		if strings.TrimSpace(e.Name) == "" {
+			return nil, errors.Errorf("preset catalog entry %d must have a name", i+1)
+		}
+	}
+	return &catalog, nil
+}
+
+// Diff compares translated SAST presets with the catalog presets, sorted by SAST preset id.
+// A SAST preset is reproducible if all its queries have AST query ids and a catalog preset has exactly those queries.
+func (c *Catalog) Diff(presets []*soap.Preset) []Diff {
+	catalogQueryIDs := make([]map[string]bool, len(c.Presets))
+	for i, e := range c.Presets {
+		catalogQueryIDs[i] = toSet(e.QueryIDs)
+	}
+	out := make([]Diff, 0, len(presets))
+	for _, preset := range presets {
+		diff := Diff{
+			ID:                 preset.ID,
+			Name:               preset.Name,
+			IdenticalPresets:   []string{},
+			MissingQueryIDs:    []string{},
+			ExtraQueryIDs:      []string{},
+			UnresolvedQueryIDs: []int{},
+		}
+		astQueryIDs := map[string]bool{}
+		if preset.Queries != nil {
+			for _, query := range preset.Queries.Query {
+				if query.AstQueryID == "" {
+					diff.UnresolvedQueryIDs = append(diff.UnresolvedQueryIDs, query.SastQueryID)
+				} else {
+					astQueryIDs[query.AstQueryID] = true
+				}
+			}
+		}
+		if preset.MissingQueries != nil {
+			diff.UnresolvedQueryIDs = append(diff.UnresolvedQueryIDs, preset.MissingQueries.SastQueryID...)
+		}
+		for i, e := range c.Presets {
+			if isSameSet(astQueryIDs, catalogQueryIDs[i]) {
+				diff.IdenticalPresets = append(diff.IdenticalPresets, e.Name)
+			}
+			if diff.CatalogPreset == "" && strings.EqualFold(e.Name, preset.Name) {
+				diff.CatalogPreset = e.Name
+				diff.MissingQueryIDs = getMissing(astQueryIDs, catalogQueryIDs[i])
+				diff.ExtraQueryIDs = getMissing(catalogQueryIDs[i], astQueryIDs)
+			}
+		}
+		diff.Reproducible = len(diff.UnresolvedQueryIDs) == 0 && len(diff.IdenticalPresets) > 0
+		out = append(out, diff)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func toSet(values []string) map[string]bool {
+	out := make(map[string]bool, len(values))
+	for _, value := range values {
+		out[value] = true
+	}
+	return out
+}
+
+func isSameSet(a, b map[string]bool) bool {
+	return len(a) == len(b) && len(getMissing(a, b)) == 0
+}
+
+// getMissing returns the values of a that are not in b, sorted
+func getMissing(a, b map[string]bool) []string {
+	out := []string{}
+	for value := range a {
+		if !b[value] {
+			out = append(out, value)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,102 @@
+package preset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	t.Run("loads presets from file", func(t *testing.T) {
+		catalog, err := LoadCatalog("../../../test/data/presets/catalog.json")
+
+		assert.NoError(t, err)
+		assert.Len(t, catalog.Presets, 2)
+		assert.Equal(t, "ASD STIG", catalog.Presets[0].Name)
+	})
+	t.Run("returns nil without file", func(t *testing.T) {
+		catalog, err := LoadCatalog("")
+
+		assert.NoError(t, err)
+		assert.Nil(t, catalog)
+	})
+	t.Run("fails if file doesn't exist", func(t *testing.T) {
+		_, err := LoadCatalog("does_not_exist.json")
+
+		assert.ErrorContains(t, err, "could not read preset catalog file")
+	})
+	tests := []struct {
+		Name        string
+		Content     string
+		ExpectedErr string
+	}{
+		{"fails if file is not valid", `{"presets": [`, "could not parse preset catalog file: unexpected end of JSON input"},
+		{"fails if name is missing", `{"presets": [{"queryIds": ["1"]}]}`, "preset catalog entry 1 must have a name"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "catalog.json")
+			assert.NoError(t, os.WriteFile(fileName, []byte(test.Content), 0600))
+
+			_, err := LoadCatalog(fileName)
+
+			assert.EqualError(t, err, test.ExpectedErr)
+		})
+	}
+}
+
+func TestCatalog_Diff(t *testing.T) {
+	catalog := &Catalog{Presets: []CatalogPreset{
+		{Name: "ASD STIG", QueryIDs: []string{"1", "2"}},
+		{Name: "High and Medium", QueryIDs: []string{"2", "1"}},
+		{Name: "Custom", QueryIDs: []string{"1", "3"}},
+	}}
+	presets := []*soap.Preset{
+		{ID: 100000, Name: "custom", Queries: &soap.PresetQueries{Query: []soap.PresetQuery{
+			{SastQueryID: 11, AstQueryID: "1"},
+			{SastQueryID: 12, AstQueryID: "2"},
+			{SastQueryID: 13, Status: UnknownStatus},
+		}}, MissingQueries: &soap.PresetMissingQueries{SastQueryID: []int{14}}},
+		{ID: 1, Name: "ASD STIG", Queries: &soap.PresetQueries{Query: []soap.PresetQuery{
+			{SastQueryID: 11, AstQueryID: "1"},
+			{SastQueryID: 12, AstQueryID: "2"},
+		}}},
+		{ID: 2, Name: "Untranslated"},
+	}
+
+	result := catalog.Diff(presets)
+
+	expected := []Diff{
+		{
+			ID:                 1,
+			Name:               "ASD STIG",
+			Reproducible:       true,
+			IdenticalPresets:   []string{"ASD STIG", "High and Medium"},
+			CatalogPreset:      "ASD STIG",
+			MissingQueryIDs:    []string{},
+			ExtraQueryIDs:      []string{},
+			UnresolvedQueryIDs: []int{},
+		},
+		{
+			ID:                 2,
+			Name:               "Untranslated",
+			IdenticalPresets:   []string{},
+			MissingQueryIDs:    []string{},
+			ExtraQueryIDs:      []string{},
+			UnresolvedQueryIDs: []int{},
+		},
+		{
+			ID:                 100000,
+			Name:               "custom",
+			IdenticalPresets:   []string{"ASD STIG", "High and Medium"},
+			CatalogPreset:      "Custom",
+			MissingQueryIDs:    []string{"2"},
+			ExtraQueryIDs:      []string{"3"},
+			UnresolvedQueryIDs: []int{13, 14},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
@@ -1,20 +1,110 @@
 package preset
 
 import (
+	"strconv"
+	"sync"
+
 	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/rs/zerolog/log"
 )
 
-type Provider struct {
-	presetProvider interfaces.PresetProvider
-}
+const (
+	// MappedStatus is the status of queries translated by the query mapping
+	MappedStatus = "mapped"
+	// UnmappedStatus is the status of queries not in the query mapping, translated by their language, group and name
+	UnmappedStatus = "unmapped"
+	// UnknownStatus is the status of queries neither in the query mapping nor in the SAST query collection
+	UnknownStatus = "unknown"
+)
+
+type (
+	Provider struct {
+		presetProvider   interfaces.PresetProvider
+		astQueryProvider interfaces.ASTQueryProvider
+		queries          map[int]queryid.Query
+		queriesErr       error
+		queriesOnce      sync.Once
+	}
+)
 
-func NewProvider(presetProvider interfaces.PresetProvider) *Provider {
+// NewProvider creates a preset provider translating queries with the SAST query collection of the AST query provider
+func NewProvider(presetProvider interfaces.PresetProvider, astQueryProvider interfaces.ASTQueryProvider) *Provider {
 	return &Provider{
-		presetProvider: presetProvider,
+		presetProvider:   presetProvider,
+		astQueryProvider: astQueryProvider,
 	}
 }
 
+// GetPresetDetails returns the preset with the AST query each of its SAST queries translates to.
+// The preset is returned without translation if the SAST query collection can't be fetched.
+// Mapped queries missing from the collection are left out of the translation and listed as missing queries.
 func (e *Provider) GetPresetDetails(id int) (*soap.GetPresetDetailsResponse, error) {
-	return e.presetProvider.GetPresetDetails(id)
+	response, err := e.presetProvider.GetPresetDetails(id)
+	if err != nil {
+		return nil, err
+	}
+	queries, queriesErr := e.getQueries()
+	if queriesErr != nil {
+		log.Warn().Err(queriesErr).Int("presetID", id).Msg("could not get queries, preset is exported without AST query ids")
+		return response, nil
+	}
+	preset := &response.GetPresetDetailsResult.Preset
+	preset.Queries = &soap.PresetQueries{Query: make([]soap.PresetQuery, 0, len(preset.QueryIDs.Long))}
+	var missingQueryIDs []int
+	for _, sastQueryID := range preset.QueryIDs.Long {
+		isMapped := e.astQueryProvider.IsMappedQuery(strconv.Itoa(sastQueryID))
+		query, exists := queries[sastQueryID]
+		switch {
+		case !exists && isMapped:
+			missingQueryIDs = append(missingQueryIDs, sastQueryID)
+		case !exists:
+			preset.Queries.Query = append(preset.Queries.Query, soap.PresetQuery{SastQueryID: sastQueryID, Status: UnknownStatus})
+		default:
+			preset.Queries.Query = append(preset.Queries.Query, e.translateQuery(sastQueryID, query, isMapped))
+		}
+	}
+	if len(missingQueryIDs) > 0 {
+		log.Warn().Int("presetID", id).Ints("sastQueryIDs", missingQueryIDs).
+			Msg("preset has mapped queries missing from the SAST query collection, they are not translated")
+		preset.MissingQueries = &soap.PresetMissingQueries{SastQueryID: missingQueryIDs}
+	}
+	return response, nil
+}
+
+func (e *Provider) translateQuery(sastQueryID int, query queryid.Query, isMapped bool) soap.PresetQuery {
+	out := soap.PresetQuery{SastQueryID: sastQueryID, Status: UnmappedStatus}
+	if isMapped {
+		out.Status = MappedStatus
+	}
+	out.Language, out.Group, out.Name = query.Language, query.Group, query.Name
+	astQueryID, astQueryIDErr := e.astQueryProvider.GetQueryID(query)
+	if astQueryIDErr != nil {
+		log.Debug().Err(astQueryIDErr).Int("sastQueryID", sastQueryID).Msg("could not get AST query id")
+		out.Status = UnknownStatus
+		return out
+	}
+	out.AstQueryID = astQueryID
+	return out
+}
+
+// getQueries returns every SAST query, by SAST query id
+func (e *Provider) getQueries() (map[int]queryid.Query, error) {
+	e.queriesOnce.Do(func() {
+		response, err := e.astQueryProvider.GetQueriesList()
+		if err != nil {
+			e.queriesErr = err
+			return
+		}
+		e.queries = map[int]queryid.Query{}
+		groups := response.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup
+		for i := range groups {
+			for j := range groups[i].Queries.CxWSQuery {
+				query := &groups[i].Queries.CxWSQuery[j]
+				e.queries[query.QueryID] = queryid.NewCollectionQuery(&groups[i], query)
+			}
+		}
+	})
+	return e.queries, e.queriesErr
 }
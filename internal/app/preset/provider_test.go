@@ -0,0 +1,111 @@
+package preset
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	mock_interfaces_query_common "github.com/checkmarxDev/ast-sast-export/test/mocks/app/ast_query"
+	mock_preset_interfaces "github.com/checkmarxDev/ast-sast-export/test/mocks/app/preset"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func getPresetResponse(queryIDs ...int) *soap.GetPresetDetailsResponse {
+	var out soap.GetPresetDetailsResponse
+	out.GetPresetDetailsResult.Preset = soap.Preset{ID: 1, Name: "All", QueryIDs: soap.QueryIDs{Long: queryIDs}}
+	return &out
+}
+
+func getQueriesResponse() *soap.GetQueryCollectionResponse {
+	var out soap.GetQueryCollectionResponse
+	out.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup = []soap.CxWSQueryGroup{
+		{
+			Name:         "Java_High_Risk",
+			LanguageName: "Java",
+			Queries:      soap.Queries{CxWSQuery: []soap.CxWSQuery{{Name: "SQL_Injection", QueryID: 51}}},
+		},
+		{
+			Name:         "Java_Corp",
+			LanguageName: "Java",
+			Queries:      soap.Queries{CxWSQuery: []soap.CxWSQuery{{Name: "Corp_Injection", QueryID: 100001}}},
+		},
+	}
+	return &out
+}
+
+func TestProvider_GetPresetDetails(t *testing.T) {
+	sqlInjection := queryid.Query{Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", SastQueryID: "51"}
+	corpInjection := queryid.Query{Language: "Java", Group: "Java_Corp", Name: "Corp_Injection", SastQueryID: "100001"}
+	t.Run("translates preset queries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		presetRepo := mock_preset_interfaces.NewMockPresetProvider(ctrl)
+		astQueryProvider := mock_interfaces_query_common.NewMockASTQueryProvider(ctrl)
+		presetRepo.EXPECT().GetPresetDetails(1).Return(getPresetResponse(51, 100001, 99), nil).Times(1)
+		presetRepo.EXPECT().GetPresetDetails(2).Return(getPresetResponse(51), nil).Times(1)
+		astQueryProvider.EXPECT().GetQueriesList().Return(getQueriesResponse(), nil).Times(1)
+		astQueryProvider.EXPECT().IsMappedQuery("51").Return(false).Times(2)
+		astQueryProvider.EXPECT().IsMappedQuery("100001").Return(true).Times(1)
+		astQueryProvider.EXPECT().IsMappedQuery("99").Return(false).Times(1)
+		astQueryProvider.EXPECT().GetQueryID(sqlInjection).Return("1001", nil).Times(2)
+		astQueryProvider.EXPECT().GetQueryID(corpInjection).Return("2001", nil).Times(1)
+		provider := NewProvider(presetRepo, astQueryProvider)
+
+		result, err := provider.GetPresetDetails(1)
+		assert.NoError(t, err)
+		_, secondErr := provider.GetPresetDetails(2)
+		assert.NoError(t, secondErr)
+
+		expected := &soap.PresetQueries{Query: []soap.PresetQuery{
+			{SastQueryID: 51, AstQueryID: "1001", Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", Status: UnmappedStatus},
+			{SastQueryID: 100001, AstQueryID: "2001", Language: "Java", Group: "Java_Corp", Name: "Corp_Injection", Status: MappedStatus},
+			{SastQueryID: 99, Status: UnknownStatus},
+		}}
+		assert.Equal(t, expected, result.GetPresetDetailsResult.Preset.Queries)
+	})
+	t.Run("leaves out mapped queries missing from the query collection", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		presetRepo := mock_preset_interfaces.NewMockPresetProvider(ctrl)
+		astQueryProvider := mock_interfaces_query_common.NewMockASTQueryProvider(ctrl)
+		presetRepo.EXPECT().GetPresetDetails(1).Return(getPresetResponse(51, 100002), nil).Times(1)
+		astQueryProvider.EXPECT().GetQueriesList().Return(getQueriesResponse(), nil).Times(1)
+		astQueryProvider.EXPECT().IsMappedQuery("51").Return(false).Times(1)
+		astQueryProvider.EXPECT().IsMappedQuery("100002").Return(true).Times(1)
+		astQueryProvider.EXPECT().GetQueryID(sqlInjection).Return("1001", nil).Times(1)
+		provider := NewProvider(presetRepo, astQueryProvider)
+
+		result, err := provider.GetPresetDetails(1)
+
+		assert.NoError(t, err)
+		expected := &soap.PresetQueries{Query: []soap.PresetQuery{
+			{SastQueryID: 51, AstQueryID: "1001", Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", Status: UnmappedStatus},
+		}}
+		assert.Equal(t, expected, result.GetPresetDetailsResult.Preset.Queries)
+		assert.Equal(t, &soap.PresetMissingQueries{SastQueryID: []int{100002}}, result.GetPresetDetailsResult.Preset.MissingQueries)
+	})
+	t.Run("returns preset without translation if queries can't be fetched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		presetRepo := mock_preset_interfaces.NewMockPresetProvider(ctrl)
+		astQueryProvider := mock_interfaces_query_common.NewMockASTQueryProvider(ctrl)
+		presetRepo.EXPECT().GetPresetDetails(1).Return(getPresetResponse(51), nil).Times(1)
+		astQueryProvider.EXPECT().GetQueriesList().Return(nil, fmt.Errorf("failed getting queries")).Times(1)
+		provider := NewProvider(presetRepo, astQueryProvider)
+
+		result, err := provider.GetPresetDetails(1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.GetPresetDetailsResult.Preset.Queries)
+	})
+	t.Run("fails if preset can't be fetched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		presetRepo := mock_preset_interfaces.NewMockPresetProvider(ctrl)
+		astQueryProvider := mock_interfaces_query_common.NewMockASTQueryProvider(ctrl)
+		presetRepo.EXPECT().GetPresetDetails(1).Return(nil, fmt.Errorf("failed getting preset")).Times(1)
+		provider := NewProvider(presetRepo, astQueryProvider)
+
+		_, err := provider.GetPresetDetails(1)
+
+		assert.EqualError(t, err, "failed getting preset")
+	})
+}
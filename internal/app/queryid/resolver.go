@@ -0,0 +1,181 @@
+package queryid
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MappingSource is the source of AST query ids taken from a query mapping
+	MappingSource = "mapping"
+	// RenameSource is the source of AST query ids kept from before a query was renamed
+	RenameSource = "rename"
+	// HashSource is the source of AST query ids calculated from the query's source path
+	HashSource = "hash"
+
+	queryPathSeparator = `\`
+	minQueryPathParts  = 4
+)
+
+var queryPathVersion = regexp.MustCompile(`\s+Version:\d+$`)
+
+type (
+	// Query identifies a SAST query
+	Query struct {
+		Language, Group, Name, SastQueryID string
+		// QueryPath is the path SAST reports give queries, like Java\Cx\Java High Risk\SQL Injection Version:1
+		QueryPath string
+	}
+
+	// Resolution is an AST query id and how it was resolved
+	Resolution struct {
+		AstQueryID  string `json:"astQueryId"`
+		Source      string `json:"source"`
+		Origin      string `json:"origin,omitempty"`
+		SourcePath  string `json:"sourcePath,omitempty"`
+		Explanation string `json:"explanation"`
+	}
+
+	mappingLayer struct {
+		origin string
+		astIDs map[string]string
+	}
+
+	renameLayer struct {
+		origin string
+		astIDs map[string]uint64
+	}
+
+	// Resolver resolves the AST query ids of SAST queries from layers of query mappings and renames.
+	// Layers added later take precedence over layers added before them.
+	Resolver struct {
+		mappingLayers []mappingLayer
+		renameLayers  []renameLayer
+	}
+)
+
+// NewCollectionQuery identifies a query of the SAST query collection, which has no query path,
+// so collection and report queries are resolved from the same input
+func NewCollectionQuery(group *soap.CxWSQueryGroup, query *soap.CxWSQuery) Query {
+	return Query{
+		Language:    group.LanguageName,
+		Group:       group.Name,
+		Name:        query.Name,
+		SastQueryID: strconv.Itoa(query.QueryID),
+	}
+}
+
+// NewResolver creates a resolver without mappings or renames, which calculates every AST query id
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// AddMappings adds a layer of query mappings, identified by their origin
+func (r *Resolver) AddMappings(origin string, mappings []querymapping.QueryMap) {
+	astIDs := make(map[string]string, len(mappings))
+	for _, e := range mappings {
+		if _, exists := astIDs[e.SastID]; !exists {
+			astIDs[e.SastID] = e.AstID
+		}
+	}
+	r.mappingLayers = append(r.mappingLayers, mappingLayer{origin: origin, astIDs: astIDs})
+}
+
+// AddRenames adds a layer of query renames, identified by their origin
+func (r *Resolver) AddRenames(origin string, renames []common.RenameEntry) {
+	astIDs := make(map[string]uint64, len(renames))
+	for _, e := range renames {
+		astIDs[e.NewPath] = e.OldPathAstID
+	}
+	r.renameLayers = append(r.renameLayers, renameLayer{origin: origin, astIDs: astIDs})
+}
+
+// IsMapped returns true if a query mapping has the AST query id of a SAST query
+func (r *Resolver) IsMapped(sastQueryID string) bool {
+	_, _, found := r.findMapping(sastQueryID)
+	return found
+}
+
+// Resolve returns the AST query id of a query. A query mapping entry for the SAST query id is used first,
+// then a rename entry for the query's source path, and otherwise the id is calculated from the source path.
+// The source path is built from the query path if it has one, or from the language, group and name.
+func (r *Resolver) Resolve(query Query) (*Resolution, error) {
+	if astID, origin, found := r.findMapping(query.SastQueryID); found {
+		return &Resolution{
+			AstQueryID:  astID,
+			Source:      MappingSource,
+			Origin:      origin,
+			Explanation: fmt.Sprintf("SAST query %s is mapped to AST query %s by %s", query.SastQueryID, astID, origin),
+		}, nil
+	}
+	language, group, name, pathOrigin := getPathParts(query)
+	if language == "" || group == "" || name == "" {
+		return nil, errors.Errorf("query %s must have a language, group and name, or a query path", query.SastQueryID)
+	}
+	sourcePath := common.GetSourcePath(language, group, name)
+	if astID, origin, found := r.findRename(sourcePath); found {
+		return &Resolution{
+			AstQueryID: fmt.Sprintf("%d", astID),
+			Source:     RenameSource,
+			Origin:     origin,
+			SourcePath: sourcePath,
+			Explanation: fmt.Sprintf("%s, built from %s, was renamed and keeps its AST query id from before the rename in %s",
+				sourcePath, pathOrigin, origin),
+		}, nil
+	}
+	return &Resolution{
+		AstQueryID:  common.HashSourcePath(sourcePath),
+		Source:      HashSource,
+		SourcePath:  sourcePath,
+		Explanation: fmt.Sprintf("AST query id is calculated from %s, built from %s", sourcePath, pathOrigin),
+	}, nil
+}
+
+func (r *Resolver) findMapping(sastQueryID string) (astID, origin string, found bool) {
+	if sastQueryID == "" {
+		return "", "", false
+	}
+	for i := len(r.mappingLayers) - 1; i >= 0; i-- {
+		if astID, exists := r.mappingLayers[i].astIDs[sastQueryID]; exists {
+			return astID, r.mappingLayers[i].origin, true
+		}
+	}
+	return "", "", false
+}
+
+func (r *Resolver) findRename(sourcePath string) (astID uint64, origin string, found bool) {
+	for i := len(r.renameLayers) - 1; i >= 0; i-- {
+		if astID, exists := r.renameLayers[i].astIDs[sourcePath]; exists {
+			return astID, r.renameLayers[i].origin, true
+		}
+	}
+	return 0, "", false
+}
+
+// getPathParts returns the language, group and name of a query's source path, preferring its query path,
+// which has the group and name with spaces instead of underscores and a version suffix.
+// Both are normalized the same way, so a query resolves to the same id with or without its query path.
+func getPathParts(query Query) (language, group, name, pathOrigin string) {
+	parts := strings.Split(strings.TrimSpace(query.QueryPath), queryPathSeparator)
+	if len(parts) >= minQueryPathParts {
+		language = parts[0]
+		group = normalizePathPart(parts[len(parts)-2])
+		name = normalizePathPart(queryPathVersion.ReplaceAllString(parts[len(parts)-1], ""))
+		if language != "" && group != "" && name != "" {
+			return language, group, name, fmt.Sprintf("query path %s", query.QueryPath)
+		}
+	}
+	return strings.TrimSpace(query.Language), normalizePathPart(query.Group), normalizePathPart(query.Name),
+		"language, group and name"
+}
+
+func normalizePathPart(part string) string {
+	return strings.ReplaceAll(strings.TrimSpace(part), " ", "_")
+}
@@ -0,0 +1,131 @@
+package queryid
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestResolver(t *testing.T) *Resolver {
+	resolver := NewResolver()
+	resolver.AddMappings("mapping.json", []querymapping.QueryMap{{SastID: "100000", AstID: "11111"}, {SastID: "1", AstID: "1"}})
+	resolver.AddMappings("custom.json", []querymapping.QueryMap{{SastID: "1", AstID: "22222"}})
+	renamesFile := filepath.Join("..", "..", "..", "data", "renames.json")
+	content, readErr := os.ReadFile(renamesFile)
+	require.NoError(t, readErr)
+	renames, err := common.ParseRenames(content, renamesFile)
+	require.NoError(t, err)
+	resolver.AddRenames(renamesFile, renames)
+	return resolver
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := getTestResolver(t)
+	tests := []struct {
+		Name     string
+		Query    Query
+		Expected Resolution
+	}{
+		{
+			Name:  "resolves from mapping",
+			Query: Query{Language: "Go", Group: "Custom", Name: "Custom_query", SastQueryID: "100000"},
+			Expected: Resolution{
+				AstQueryID:  "11111",
+				Source:      MappingSource,
+				Origin:      "mapping.json",
+				Explanation: "SAST query 100000 is mapped to AST query 11111 by mapping.json",
+			},
+		},
+		{
+			Name:  "resolves from last mapping layer",
+			Query: Query{Language: "Go", Group: "Custom", Name: "Custom_query", SastQueryID: "1"},
+			Expected: Resolution{
+				AstQueryID:  "22222",
+				Source:      MappingSource,
+				Origin:      "custom.json",
+				Explanation: "SAST query 1 is mapped to AST query 22222 by custom.json",
+			},
+		},
+		{
+			Name: "resolves from renames",
+			Query: Query{
+				Language:    "Java",
+				Group:       "Java_Low_Visibility",
+				Name:        "Trust_Boundary_Violation_in_Session_Variables",
+				SastQueryID: "646",
+			},
+			Expected: Resolution{
+				AstQueryID: "5726913611564465136",
+				Source:     RenameSource,
+				Origin:     filepath.Join("..", "..", "..", "data", "renames.json"),
+				SourcePath: "queries/Java/Java_Low_Visibility/Trust_Boundary_Violation_in_Session_Variables/" +
+					"Trust_Boundary_Violation_in_Session_Variables.cs",
+				Explanation: "queries/Java/Java_Low_Visibility/Trust_Boundary_Violation_in_Session_Variables/" +
+					"Trust_Boundary_Violation_in_Session_Variables.cs, built from language, group and name, was renamed and keeps " +
+					"its AST query id from before the rename in " + filepath.Join("..", "..", "..", "data", "renames.json"),
+			},
+		},
+		{
+			Name:  "calculates from language, group and name",
+			Query: Query{Language: "Kotlin", Group: "Kotlin_High_Risk", Name: "Code_Injection", SastQueryID: "2"},
+			Expected: Resolution{
+				AstQueryID: "15158446363146771540",
+				Source:     HashSource,
+				SourcePath: "queries/Kotlin/Kotlin_High_Risk/Code_Injection/Code_Injection.cs",
+				Explanation: "AST query id is calculated from queries/Kotlin/Kotlin_High_Risk/Code_Injection/Code_Injection.cs, " +
+					"built from language, group and name",
+			},
+		},
+		{
+			Name: "calculates from query path",
+			Query: Query{
+				Language:    "Kotlin",
+				Group:       "Old_Group",
+				Name:        "Old_Name",
+				SastQueryID: "2",
+				QueryPath:   `Kotlin\Cx\Kotlin High Risk\Code Injection Version:1`,
+			},
+			Expected: Resolution{
+				AstQueryID: "15158446363146771540",
+				Source:     HashSource,
+				SourcePath: "queries/Kotlin/Kotlin_High_Risk/Code_Injection/Code_Injection.cs",
+				Explanation: "AST query id is calculated from queries/Kotlin/Kotlin_High_Risk/Code_Injection/Code_Injection.cs, " +
+					`built from query path Kotlin\Cx\Kotlin High Risk\Code Injection Version:1`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			result, err := resolver.Resolve(test.Query)
+
+			assert.NoError(t, err)
+			assert.Equal(t, &test.Expected, result)
+		})
+	}
+	t.Run("fails without language, group and name", func(t *testing.T) {
+		_, err := resolver.Resolve(Query{SastQueryID: "3", Name: "Code_Injection"})
+
+		assert.EqualError(t, err, "query 3 must have a language, group and name, or a query path")
+	})
+}
+
+func TestResolver_IsMapped(t *testing.T) {
+	resolver := getTestResolver(t)
+
+	assert.True(t, resolver.IsMapped("100000"))
+	assert.False(t, resolver.IsMapped("646"))
+	assert.False(t, resolver.IsMapped(""))
+}
+
+func TestNewResolver(t *testing.T) {
+	result, err := NewResolver().Resolve(Query{Language: "Go", Group: "General", Name: "Find_Command_Injection_Sanitize"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, common.HashSourcePath(common.GetSourcePath("Go", "General", "Find_Command_Injection_Sanitize")), result.AstQueryID)
+	assert.Equal(t, "9498204717545098527", result.AstQueryID)
+}
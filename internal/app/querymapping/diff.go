@@ -0,0 +1,74 @@
+package querymapping
+
+import (
+	"sort"
+	"strconv"
+)
+
+type (
+	// Diff lists how a query mapping differs from a previous one
+	Diff struct {
+		Added   []QueryMap   `json:"added"`
+		Removed []QueryMap   `json:"removed"`
+		Changed []ChangedMap `json:"changed"`
+	}
+
+	// ChangedMap is a SAST query mapped to a different AST query id than before
+	ChangedMap struct {
+		SastID        string `json:"sastId"`
+		AstID         string `json:"astId"`
+		PreviousAstID string `json:"previousAstId"`
+	}
+)
+
+// GetDiff compares a query mapping with a previous one. The first entry of a SAST query is used in both.
+func GetDiff(previous, current []QueryMap) Diff {
+	previousIDs := getFirstAstIDs(previous)
+	currentIDs := getFirstAstIDs(current)
+	diff := Diff{Added: []QueryMap{}, Removed: []QueryMap{}, Changed: []ChangedMap{}}
+	for sastID, astID := range currentIDs {
+		previousAstID, exists := previousIDs[sastID]
+		if !exists {
+			diff.Added = append(diff.Added, QueryMap{SastID: sastID, AstID: astID})
+		} else if previousAstID != astID {
+			diff.Changed = append(diff.Changed, ChangedMap{SastID: sastID, AstID: astID, PreviousAstID: previousAstID})
+		}
+	}
+	for sastID, astID := range previousIDs {
+		if _, exists := currentIDs[sastID]; !exists {
+			diff.Removed = append(diff.Removed, QueryMap{SastID: sastID, AstID: astID})
+		}
+	}
+	SortBySastID(diff.Added)
+	SortBySastID(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return isLowerSastID(diff.Changed[i].SastID, diff.Changed[j].SastID)
+	})
+	return diff
+}
+
+// SortBySastID sorts query mapping entries by SAST query id, numerically when the ids are numbers
+func SortBySastID(mappings []QueryMap) {
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return isLowerSastID(mappings[i].SastID, mappings[j].SastID)
+	})
+}
+
+func isLowerSastID(a, b string) bool {
+	aNumber, aErr := strconv.Atoi(a)
+	bNumber, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		return aNumber < bNumber
+	}
+	return a < b
+}
+
+func getFirstAstIDs(mappings []QueryMap) map[string]string {
+	astIDs := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		if _, exists := astIDs[mapping.SastID]; !exists {
+			astIDs[mapping.SastID] = mapping.AstID
+		}
+	}
+	return astIDs
+}
@@ -0,0 +1,40 @@
+package querymapping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDiff(t *testing.T) {
+	previous := []QueryMap{
+		{SastID: "10", AstID: "110"},
+		{SastID: "2", AstID: "102"},
+		{SastID: "3", AstID: "103"},
+		{SastID: "3", AstID: "999"},
+		{SastID: "4", AstID: "104"},
+	}
+	current := []QueryMap{
+		{SastID: "9", AstID: "109"},
+		{SastID: "3", AstID: "203"},
+		{SastID: "4", AstID: "104"},
+		{SastID: "100", AstID: "200"},
+	}
+
+	result := GetDiff(previous, current)
+
+	expected := Diff{
+		Added:   []QueryMap{{SastID: "9", AstID: "109"}, {SastID: "100", AstID: "200"}},
+		Removed: []QueryMap{{SastID: "2", AstID: "102"}, {SastID: "10", AstID: "110"}},
+		Changed: []ChangedMap{{SastID: "3", AstID: "203", PreviousAstID: "103"}},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestSortBySastID(t *testing.T) {
+	mappings := []QueryMap{{SastID: "b"}, {SastID: "10"}, {SastID: "9"}, {SastID: "a"}}
+
+	SortBySastID(mappings)
+
+	assert.Equal(t, []QueryMap{{SastID: "9"}, {SastID: "10"}, {SastID: "a"}, {SastID: "b"}}, mappings)
+}
@@ -4,9 +4,20 @@ type (
 	QueryMap struct {
 		AstID  string `json:"astId"`
 		SastID string `json:"sastId"`
+		// Origin is the mapping source the entry comes from
+		Origin string `json:"origin,omitempty"`
 	}
 
 	MapSource struct {
 		Mappings []QueryMap `json:"mappings"`
 	}
+
+	// Conflict is a SAST query assigned different AST query ids by mapping sources
+	Conflict struct {
+		SastID           string `json:"sastId"`
+		AstID            string `json:"astId"`
+		Origin           string `json:"origin"`
+		OverriddenAstID  string `json:"overriddenAstId"`
+		OverriddenOrigin string `json:"overriddenOrigin"`
+	}
 )
@@ -2,111 +2,120 @@ package querymapping
 
 import (
 	"encoding/json"
-	"io"
-	"net/http"
-	"net/url"
-	"os"
-	"path"
-
-	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
-
-	"github.com/rs/zerolog/log"
+	"fmt"
+	"sort"
+	"strings"
 )
 
-type RetryableHTTPAdapter interface {
-	Get(url string) (*http.Response, error)
-}
+// CalculatedOrigin is the origin of mappings added for queries without a mapping entry
+const CalculatedOrigin = "calculated"
 
 type (
+	// Provider merges layers of query mappings. An entry of a later layer overrides the entry of an earlier one
+	// for the same SAST query, while within a layer the first entry is kept. Both are reported as conflicts
+	// if the AST query ids differ.
 	Provider struct {
 		queryMappings []QueryMap
+		index         map[string]int
+		conflicts     []Conflict
 	}
-)
 
-const (
-	tmpDirPrefix = "query_mapping_"
-	fileName     = "mapping.json"
+	// ConflictError is returned when mapping conflicts affect triaged queries and conflicts aren't allowed
+	ConflictError struct {
+		Conflicts []Conflict
+	}
 )
 
-func NewProvider(queryMappingPath string, client RetryableHTTPAdapter) (*Provider, error) {
+// NewProvider creates a provider without query mappings
+func NewProvider() *Provider {
+	return &Provider{index: map[string]int{}}
+}
+
+// Parse returns the query mapping entries in data
+func Parse(data []byte) ([]QueryMap, error) {
 	var mapSource MapSource
-	tmpDir := ""
-	_, urlErr := url.ParseRequestURI(queryMappingPath)
-	if urlErr == nil {
-		var tmpFileErr error
-		queryMappingPath, tmpDir, tmpFileErr = createTmpFile(queryMappingPath, client)
-		if tmpFileErr != nil {
-			removeTmpDir(tmpDir)
-			return nil, tmpFileErr
-		}
-	}
-	data, err := os.ReadFile(queryMappingPath)
-	removeTmpDir(tmpDir)
-	if err != nil {
-		return nil, err
-	}
 	if jsonErr := json.Unmarshal(data, &mapSource); jsonErr != nil {
 		return nil, jsonErr
 	}
+	return mapSource.Mappings, nil
+}
+
+// AddLayer merges a layer of query mappings, identified by their origin
+func (p *Provider) AddLayer(origin string, mappings []QueryMap) {
+	added := make(map[string]bool, len(mappings))
+	for _, mapping := range mappings {
+		mapping.Origin = origin
+		i, exists := p.index[mapping.SastID]
+		if !exists {
+			p.index[mapping.SastID] = len(p.queryMappings)
+			p.queryMappings = append(p.queryMappings, mapping)
+			added[mapping.SastID] = true
+			continue
+		}
+		current := p.queryMappings[i]
+		if added[mapping.SastID] {
+			p.addConflict(current, mapping)
+			continue
+		}
+		added[mapping.SastID] = true
+		p.addConflict(mapping, current)
+		p.queryMappings[i] = mapping
+	}
+}
 
-	return &Provider{
-		queryMappings: mapSource.Mappings,
-	}, nil
+func (p *Provider) addConflict(kept, overridden QueryMap) {
+	if kept.AstID == overridden.AstID {
+		return
+	}
+	p.conflicts = append(p.conflicts, Conflict{
+		SastID:           kept.SastID,
+		AstID:            kept.AstID,
+		Origin:           kept.Origin,
+		OverriddenAstID:  overridden.AstID,
+		OverriddenOrigin: overridden.Origin,
+	})
 }
 
 func (p *Provider) GetMapping() []QueryMap {
 	return p.queryMappings
 }
 
-func (p *Provider) AddQueryMapping(language, name, group, sastQueryID string) error {
-	for _, mapping := range p.queryMappings {
-		if mapping.SastID == sastQueryID {
-			return nil
-		}
-	}
+// GetConflicts returns the SAST queries assigned different AST query ids, in the order they were found
+func (p *Provider) GetConflicts() []Conflict {
+	return p.conflicts
+}
 
-	astID, err := common.GetAstQueryID(language, name, group)
-	if err != nil {
-		return err
+// AddQueryMapping adds the AST query id of a SAST query, unless the SAST query is already mapped
+func (p *Provider) AddQueryMapping(sastQueryID, astQueryID string) {
+	if _, exists := p.index[sastQueryID]; exists {
+		return
 	}
-
+	p.index[sastQueryID] = len(p.queryMappings)
 	p.queryMappings = append(p.queryMappings, QueryMap{
-		AstID:  astID,
+		AstID:  astQueryID,
 		SastID: sastQueryID,
+		Origin: CalculatedOrigin,
 	})
-	return nil
 }
 
-func createTmpFile(fileURL string, client RetryableHTTPAdapter) (tmpFileName, tmpQueryMappingDir string, err error) {
-	tmpDir := os.TempDir()
-	tmpQueryMappingDir, err = os.MkdirTemp(tmpDir, tmpDirPrefix)
-	if err != nil {
-		return "", "", err
-	}
-	tmpFileName = path.Join(tmpQueryMappingDir, fileName)
-	out, err := os.Create(tmpFileName)
-	if err != nil {
-		return "", tmpQueryMappingDir, err
-	}
-	defer out.Close()
-	resp, err := client.Get(fileURL)
-	if err != nil {
-		return "", tmpQueryMappingDir, err
-	}
-	defer resp.Body.Close()
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return "", tmpQueryMappingDir, err
+// GenerateConflictsCSV returns the rows of the query mapping conflicts report
+func (p *Provider) GenerateConflictsCSV() [][]string {
+	items := [][]string{{"sast_query_id", "ast_query_id", "origin", "overridden_ast_query_id", "overridden_origin"}}
+	for _, c := range p.conflicts {
+		items = append(items, []string{c.SastID, c.AstID, c.Origin, c.OverriddenAstID, c.OverriddenOrigin})
 	}
-	return tmpFileName, tmpQueryMappingDir, nil
+	return items
 }
 
-func removeTmpDir(tmpDir string) {
-	if tmpDir == "" {
-		return
-	}
-	delErr := os.RemoveAll(tmpDir)
-	if delErr != nil {
-		log.Error().Err(delErr).Msgf("Could not remove temporary directory with query mapping file %s", tmpDir)
+func (e *ConflictError) Error() string {
+	sastIDs := make([]string, 0, len(e.Conflicts))
+	seen := make(map[string]bool, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		if !seen[c.SastID] {
+			seen[c.SastID] = true
+			sastIDs = append(sastIDs, c.SastID)
+		}
 	}
+	sort.Strings(sastIDs)
+	return fmt.Sprintf("query mapping conflicts affect triaged SAST queries %s", strings.Join(sastIDs, ", "))
 }
@@ -1,51 +1,82 @@
 package querymapping
 
 import (
-	"bytes"
-	"io"
-	"net/http"
+	"os"
 	"testing"
 
+	"github.com/checkmarxDev/ast-sast-export/data"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-type HTTPClientMock struct {
-	GetResponse *http.Response
-	GetError    error
-}
+func TestParse(t *testing.T) {
+	t.Run("Test parsing file", func(t *testing.T) {
+		content, readErr := os.ReadFile("../../../data/mapping.json")
+		require.NoError(t, readErr)
+
+		mappings, err := Parse(content)
+		assert.NoError(t, err)
+
+		assert.Equal(t, QueryMap{AstID: "5667386434418802377", SastID: "11"}, mappings[0])
+	})
 
-func (c *HTTPClientMock) Get(_ string) (*http.Response, error) {
-	return c.GetResponse, c.GetError
+	t.Run("Test error with invalid data", func(t *testing.T) {
+		_, err := Parse([]byte("<html>Proxy error</html>"))
+		assert.Error(t, err)
+	})
 }
 
 func TestQueryMappingProvider(t *testing.T) {
-	t.Run("Test creating from file", func(t *testing.T) {
-		response := http.Response{}
-		adapter := &HTTPClientMock{GetResponse: &response, GetError: nil}
+	t.Run("Test merging layers", func(t *testing.T) {
+		provider := NewProvider()
 
-		provider, err := NewProvider("../../../data/mapping.json", adapter)
-		assert.NoError(t, err)
+		provider.AddLayer("mapping.json", []QueryMap{{SastID: "1", AstID: "101"}, {SastID: "2", AstID: "102"}, {SastID: "3", AstID: "103"}})
+		provider.AddLayer("custom.json", []QueryMap{
+			{SastID: "2", AstID: "202"},
+			{SastID: "2", AstID: "302"},
+			{SastID: "3", AstID: "103"},
+			{SastID: "4", AstID: "204"},
+		})
 
-		assert.Equal(t, "11", provider.GetMapping()[0].SastID)
+		expectedMapping := []QueryMap{
+			{SastID: "1", AstID: "101", Origin: "mapping.json"},
+			{SastID: "2", AstID: "202", Origin: "custom.json"},
+			{SastID: "3", AstID: "103", Origin: "custom.json"},
+			{SastID: "4", AstID: "204", Origin: "custom.json"},
+		}
+		assert.Equal(t, expectedMapping, provider.GetMapping())
+		expectedConflicts := []Conflict{
+			{SastID: "2", AstID: "202", Origin: "custom.json", OverriddenAstID: "102", OverriddenOrigin: "mapping.json"},
+			{SastID: "2", AstID: "202", Origin: "custom.json", OverriddenAstID: "302", OverriddenOrigin: "custom.json"},
+		}
+		assert.Equal(t, expectedConflicts, provider.GetConflicts())
+		expectedCSV := [][]string{
+			{"sast_query_id", "ast_query_id", "origin", "overridden_ast_query_id", "overridden_origin"},
+			{"2", "202", "custom.json", "102", "mapping.json"},
+			{"2", "202", "custom.json", "302", "custom.json"},
+		}
+		assert.Equal(t, expectedCSV, provider.GenerateConflictsCSV())
 	})
 
-	t.Run("Test creating from URL", func(t *testing.T) {
-		response := http.Response{
-			Body: io.NopCloser(bytes.NewBufferString("{ \"mappings\": [{ \"astID\": \"5667386434418802377\",	\"sastID\": \"11\"}] }")),
-		}
-		adapter := &HTTPClientMock{GetResponse: &response, GetError: nil}
+	t.Run("Test adding query mapping", func(t *testing.T) {
+		mappings, err := Parse(data.QueryMapping)
+		require.NoError(t, err)
+		provider := NewProvider()
+		provider.AddLayer("embedded", mappings)
+		count := len(provider.GetMapping())
 
-		provider, err := NewProvider("https://raw.githubusercontent.com/mapping.json", adapter)
-		assert.NoError(t, err)
+		provider.AddQueryMapping("11", "1")
+		provider.AddQueryMapping("999999999", "2")
 
-		assert.Equal(t, "11", provider.GetMapping()[0].SastID)
+		assert.Len(t, provider.GetMapping(), count+1)
+		assert.NotEqual(t, "1", provider.GetMapping()[0].AstID)
+		assert.Equal(t, QueryMap{AstID: "2", SastID: "999999999", Origin: CalculatedOrigin}, provider.GetMapping()[count])
+		assert.Empty(t, provider.GetConflicts())
 	})
+}
 
-	t.Run("Test error with wrong path", func(t *testing.T) {
-		response := http.Response{}
-		adapter := &HTTPClientMock{GetResponse: &response, GetError: nil}
-		var err error
-		_, err = NewProvider("wrong_path", adapter)
-		assert.Error(t, err)
-	})
+func TestConflictError(t *testing.T) {
+	err := &ConflictError{Conflicts: []Conflict{{SastID: "2"}, {SastID: "1"}, {SastID: "2"}}}
+
+	assert.EqualError(t, err, "query mapping conflicts affect triaged SAST queries 1, 2")
 }
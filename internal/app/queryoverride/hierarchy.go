@@ -0,0 +1,240 @@
+package queryoverride
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+)
+
+const (
+	// CorporateLevel overrides apply to every team and project
+	CorporateLevel = "Corporate"
+	// TeamLevel overrides apply to the projects of the owning team
+	TeamLevel = "Team"
+	// ProjectLevel overrides apply to a single project
+	ProjectLevel = "Project"
+
+	productLevel = "Cx"
+)
+
+// levelOrder is the precedence of each level, a higher level overrides the lower ones
+var levelOrder = map[string]int{CorporateLevel: 1, TeamLevel: 2, ProjectLevel: 3}
+
+type (
+	// Override is a custom version of a query at one level of the SAST package hierarchy
+	Override struct {
+		Language    string `json:"language"`
+		Group       string `json:"group"`
+		Name        string `json:"name"`
+		SastQueryID string `json:"sastQueryId"`
+		AstQueryID  string `json:"astQueryId"`
+		Level       string `json:"level"`
+		Package     string `json:"package"`
+		TeamID      int    `json:"teamId,omitempty"`
+		ProjectID   int    `json:"projectId,omitempty"`
+		// Overrides is the SAST query id of the override replaced by this one, empty if it replaces the product query
+		Overrides string `json:"overrides,omitempty"`
+		// ProjectIDs are the exported projects this override applies to
+		ProjectIDs []int `json:"projectIds,omitempty"`
+	}
+
+	// Collision is a query whose overrides CxOne can't represent, because CxOne has no team level
+	// and every override of a query shares the same AST query id
+	Collision struct {
+		Language     string   `json:"language"`
+		Group        string   `json:"group"`
+		Name         string   `json:"name"`
+		AstQueryID   string   `json:"astQueryId"`
+		SastQueryIDs []string `json:"sastQueryIds"`
+		Reason       string   `json:"reason"`
+	}
+
+	// Hierarchy is the inheritance map of the custom query overrides
+	Hierarchy struct {
+		Overrides  []*Override `json:"overrides"`
+		Collisions []Collision `json:"collisions"`
+	}
+
+	// AstQueryIDResolver returns the AST query id of a SAST query
+	AstQueryIDResolver func(query queryid.Query) (string, error)
+
+	queryKey struct {
+		Language, Group, Name string
+	}
+)
+
+// NewHierarchy builds the inheritance map of the custom queries.
+// projectTeams maps the id of each exported project to the id of its owning team, it can be empty.
+// teamParents maps the id of each team to the id of its parent team, so sub-teams inherit the overrides of their ancestors.
+func NewHierarchy(groups []soap.CxWSQueryGroup, projectTeams, teamParents map[int]int, resolve AstQueryIDResolver,
+) (*Hierarchy, error) {
+	byQuery := map[queryKey][]*Override{}
+	var keys []queryKey
+	for i := range groups {
+		group := groups[i]
+		if group.PackageType == productLevel {
+			continue
+		}
+		for j := range group.Queries.CxWSQuery {
+			query := group.Queries.CxWSQuery[j]
+			sastQueryID := strconv.Itoa(query.QueryID)
+			astQueryID, err := resolve(queryid.NewCollectionQuery(&group, &query))
+			if err != nil {
+				return nil, err
+			}
+			override := &Override{
+				Language:    group.LanguageName,
+				Group:       group.Name,
+				Name:        query.Name,
+				SastQueryID: sastQueryID,
+				AstQueryID:  astQueryID,
+				Level:       group.PackageType,
+				Package:     group.PackageFullName,
+			}
+			switch group.PackageType {
+			case TeamLevel:
+				override.TeamID = group.OwningTeam
+			case ProjectLevel:
+				override.ProjectID = group.ProjectID
+			}
+			key := queryKey{Language: group.LanguageName, Group: group.Name, Name: query.Name}
+			if _, exists := byQuery[key]; !exists {
+				keys = append(keys, key)
+			}
+			byQuery[key] = append(byQuery[key], override)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Language != keys[j].Language {
+			return keys[i].Language < keys[j].Language
+		}
+		if keys[i].Group != keys[j].Group {
+			return keys[i].Group < keys[j].Group
+		}
+		return keys[i].Name < keys[j].Name
+	})
+
+	hierarchy := &Hierarchy{Overrides: []*Override{}, Collisions: []Collision{}}
+	for _, key := range keys {
+		overrides := byQuery[key]
+		sort.SliceStable(overrides, func(i, j int) bool {
+			return levelOrder[overrides[i].Level] < levelOrder[overrides[j].Level]
+		})
+		linkOverrides(overrides, projectTeams, teamParents)
+		hierarchy.Overrides = append(hierarchy.Overrides, overrides...)
+		if collision := getCollision(key, overrides); collision != nil {
+			hierarchy.Collisions = append(hierarchy.Collisions, *collision)
+		}
+	}
+	return hierarchy, nil
+}
+
+// linkOverrides sets the override each override replaces and the projects it applies to
+func linkOverrides(overrides []*Override, projectTeams, teamParents map[int]int) {
+	corporate, teams, projects := splitLevels(overrides)
+	for _, override := range overrides {
+		switch override.Level {
+		case TeamLevel:
+			if team, ok := findTeamOverride(teams, teamParents, teamParents[override.TeamID]); ok {
+				override.Overrides = team.SastQueryID
+			} else if corporate != nil {
+				override.Overrides = corporate.SastQueryID
+			}
+		case ProjectLevel:
+			if team, ok := findTeamOverride(teams, teamParents, projectTeams[override.ProjectID]); ok {
+				override.Overrides = team.SastQueryID
+			} else if corporate != nil {
+				override.Overrides = corporate.SastQueryID
+			}
+		}
+	}
+
+	projectIDs := make([]int, 0, len(projectTeams))
+	for projectID := range projectTeams {
+		projectIDs = append(projectIDs, projectID)
+	}
+	sort.Ints(projectIDs)
+	for _, projectID := range projectIDs {
+		if override, ok := projects[projectID]; ok {
+			override.ProjectIDs = append(override.ProjectIDs, projectID)
+		} else if override, ok := findTeamOverride(teams, teamParents, projectTeams[projectID]); ok {
+			override.ProjectIDs = append(override.ProjectIDs, projectID)
+		} else if corporate != nil {
+			corporate.ProjectIDs = append(corporate.ProjectIDs, projectID)
+		}
+	}
+}
+
+// findTeamOverride returns the override of the team or, if it has none, of its nearest ancestor team
+func findTeamOverride(teams map[int]*Override, teamParents map[int]int, teamID int) (*Override, bool) {
+	visited := map[int]bool{}
+	for teamID > 0 && !visited[teamID] {
+		if override, ok := teams[teamID]; ok {
+			return override, true
+		}
+		visited[teamID] = true
+		teamID = teamParents[teamID]
+	}
+	return nil, false
+}
+
+func splitLevels(overrides []*Override) (corporate *Override, teams, projects map[int]*Override) {
+	teams = map[int]*Override{}
+	projects = map[int]*Override{}
+	for _, override := range overrides {
+		switch override.Level {
+		case CorporateLevel:
+			if corporate == nil {
+				corporate = override
+			}
+		case TeamLevel:
+			if _, exists := teams[override.TeamID]; !exists {
+				teams[override.TeamID] = override
+			}
+		case ProjectLevel:
+			if _, exists := projects[override.ProjectID]; !exists {
+				projects[override.ProjectID] = override
+			}
+		}
+	}
+	return corporate, teams, projects
+}
+
+// getCollision returns the collision of the overrides of a query, if any.
+// CxOne only has tenant and project level overrides, so team overrides would have to become tenant overrides,
+// and overrides of the same query share one AST query id, so they can't be told apart.
+func getCollision(key queryKey, overrides []*Override) *Collision {
+	var tenantLevel []*Override
+	teamCount := 0
+	for _, override := range overrides {
+		if override.Level == ProjectLevel {
+			continue
+		}
+		tenantLevel = append(tenantLevel, override)
+		if override.Level == TeamLevel {
+			teamCount++
+		}
+	}
+	if teamCount == 0 {
+		return nil
+	}
+	collision := &Collision{
+		Language:   key.Language,
+		Group:      key.Group,
+		Name:       key.Name,
+		AstQueryID: overrides[0].AstQueryID,
+	}
+	for _, override := range tenantLevel {
+		collision.SastQueryIDs = append(collision.SastQueryIDs, override.SastQueryID)
+	}
+	if len(tenantLevel) == 1 {
+		collision.Reason = fmt.Sprintf("team override of team %d would apply to every project as a tenant override", tenantLevel[0].TeamID)
+	} else {
+		collision.Reason = fmt.Sprintf("%d corporate and team overrides share AST query id %s and would become one tenant override",
+			len(tenantLevel), collision.AstQueryID)
+	}
+	return collision
+}
@@ -0,0 +1,114 @@
+package queryoverride
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/stretchr/testify/assert"
+)
+
+func getGroup(packageType string, teamID, projectID int, queryIDs ...int) soap.CxWSQueryGroup {
+	group := soap.CxWSQueryGroup{
+		Name:            "Java_High_Risk",
+		LanguageName:    "Java",
+		PackageType:     packageType,
+		PackageFullName: packageType + ":Java_High_Risk",
+		OwningTeam:      teamID,
+		ProjectID:       projectID,
+	}
+	for _, queryID := range queryIDs {
+		group.Queries.CxWSQuery = append(group.Queries.CxWSQuery, soap.CxWSQuery{QueryID: queryID, Name: "SQL_Injection"})
+	}
+	return group
+}
+
+func resolveByName(query queryid.Query) (string, error) {
+	return "ast-" + query.Name, nil
+}
+
+func TestNewHierarchy(t *testing.T) {
+	t.Run("links overrides and projects", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{
+			getGroup(ProjectLevel, 0, 11, 100003),
+			getGroup(TeamLevel, 2, 0, 100002),
+			getGroup(productLevel, 0, 0, 5157),
+			getGroup(CorporateLevel, 0, 0, 100001),
+		}
+		projectTeams := map[int]int{10: 1, 11: 2, 12: 2}
+
+		result, err := NewHierarchy(groups, projectTeams, nil, resolveByName)
+
+		assert.NoError(t, err)
+		expectedOverrides := []*Override{
+			{
+				Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", SastQueryID: "100001", AstQueryID: "ast-SQL_Injection",
+				Level: CorporateLevel, Package: "Corporate:Java_High_Risk", ProjectIDs: []int{10},
+			},
+			{
+				Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", SastQueryID: "100002", AstQueryID: "ast-SQL_Injection",
+				Level: TeamLevel, Package: "Team:Java_High_Risk", TeamID: 2, Overrides: "100001", ProjectIDs: []int{12},
+			},
+			{
+				Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", SastQueryID: "100003", AstQueryID: "ast-SQL_Injection",
+				Level: ProjectLevel, Package: "Project:Java_High_Risk", ProjectID: 11, Overrides: "100002", ProjectIDs: []int{11},
+			},
+		}
+		assert.Equal(t, expectedOverrides, result.Overrides)
+		expectedCollisions := []Collision{
+			{
+				Language: "Java", Group: "Java_High_Risk", Name: "SQL_Injection", AstQueryID: "ast-SQL_Injection",
+				SastQueryIDs: []string{"100001", "100002"},
+				Reason:       "2 corporate and team overrides share AST query id ast-SQL_Injection and would become one tenant override",
+			},
+		}
+		assert.Equal(t, expectedCollisions, result.Collisions)
+	})
+	t.Run("links sub-team projects to the nearest ancestor team override", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{
+			getGroup(CorporateLevel, 0, 0, 100001),
+			getGroup(TeamLevel, 2, 0, 100002),
+			getGroup(TeamLevel, 4, 0, 100004),
+		}
+		projectTeams := map[int]int{10: 1, 11: 3, 12: 4, 13: 5}
+		teamParents := map[int]int{1: -1, 2: 1, 3: 2, 4: 3, 5: 4}
+
+		result, err := NewHierarchy(groups, projectTeams, teamParents, resolveByName)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Overrides, 3)
+		assert.Equal(t, []int{10}, result.Overrides[0].ProjectIDs)
+		assert.Equal(t, "100001", result.Overrides[1].Overrides)
+		assert.Equal(t, []int{11}, result.Overrides[1].ProjectIDs)
+		assert.Equal(t, "100002", result.Overrides[2].Overrides)
+		assert.Equal(t, []int{12, 13}, result.Overrides[2].ProjectIDs)
+	})
+	t.Run("reports single team override", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{getGroup(TeamLevel, 3, 0, 100002)}
+
+		result, err := NewHierarchy(groups, nil, nil, resolveByName)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Collisions, 1)
+		assert.Equal(t, "team override of team 3 would apply to every project as a tenant override", result.Collisions[0].Reason)
+	})
+	t.Run("doesn't report corporate and project overrides", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{getGroup(CorporateLevel, 0, 0, 100001), getGroup(ProjectLevel, 0, 4, 100003)}
+
+		result, err := NewHierarchy(groups, map[int]int{4: 1}, nil, resolveByName)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Collisions)
+		assert.Equal(t, "100001", result.Overrides[1].Overrides)
+	})
+	t.Run("fails if query id can't be resolved", func(t *testing.T) {
+		groups := []soap.CxWSQueryGroup{getGroup(CorporateLevel, 0, 0, 100001)}
+
+		_, err := NewHierarchy(groups, nil, nil, func(_ queryid.Query) (string, error) {
+			return "", fmt.Errorf("resolve error")
+		})
+
+		assert.EqualError(t, err, "resolve error")
+	})
+}
@@ -0,0 +1,156 @@
+package queryresolution
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/metadata"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+)
+
+const (
+	// ErrorSource is the source of queries whose AST query id could not be resolved
+	ErrorSource = "error"
+
+	// HashFallbackWarning is the warning for product queries whose AST query id is a hash of the query path.
+	// The hash only matches CxOne if the query kept its SAST path, so their triage is the most likely to be lost.
+	HashFallbackWarning = "AST query id calculated from the query path, triage may not match in AST"
+
+	queryPathSeparator   = `\`
+	productPackageFolder = "Cx"
+
+	productPackage = "product"
+	customPackage  = "custom"
+	unknownPackage = "unknown"
+)
+
+type (
+	// Entry describes how the AST query id of a triaged query was resolved
+	Entry struct {
+		Language     string
+		Group        string
+		Name         string
+		SastQueryID  string
+		AstQueryID   string
+		Source       string
+		TriagedPaths int
+		Warning      string
+	}
+
+	entryKey struct {
+		Language, Group, Name, SastQueryID string
+	}
+
+	// Report collects the resolution of triaged queries across scans
+	Report struct {
+		astQueryProvider interfaces.ASTQueryProvider
+		entries          map[entryKey]*Entry
+		mutex            sync.Mutex
+	}
+)
+
+// NewReport creates a report resolving AST query ids with the given provider
+func NewReport(astQueryProvider interfaces.ASTQueryProvider) *Report {
+	return &Report{
+		astQueryProvider: astQueryProvider,
+		entries:          map[entryKey]*Entry{},
+	}
+}
+
+// Add resolves the triaged queries of a scan and adds their triaged result paths to the report
+func (r *Report) Add(queries []*metadata.Query) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, query := range queries {
+		key := entryKey{Language: query.Language, Group: query.Group, Name: query.Name, SastQueryID: query.QueryID}
+		entry, exists := r.entries[key]
+		if !exists {
+			entry = r.resolve(query)
+			r.entries[key] = entry
+		}
+		entry.TriagedPaths += len(query.Results)
+	}
+}
+
+func (r *Report) resolve(query *metadata.Query) *Entry {
+	entry := &Entry{Language: query.Language, Group: query.Group, Name: query.Name, SastQueryID: query.QueryID}
+	resolution, err := r.astQueryProvider.ResolveQuery(queryid.Query{
+		Language:    query.Language,
+		Group:       query.Group,
+		Name:        query.Name,
+		SastQueryID: query.QueryID,
+		QueryPath:   query.QueryPath,
+	})
+	if err != nil {
+		entry.Source = ErrorSource
+		entry.Warning = err.Error()
+		return entry
+	}
+	entry.AstQueryID = resolution.AstQueryID
+	entry.Source = resolution.Source
+	if resolution.Source == queryid.HashSource && getQueryPackage(query.QueryPath) == productPackage {
+		entry.Warning = HashFallbackWarning
+	}
+	return entry
+}
+
+// GetEntries returns the report entries, sorted by language, group, name and SAST query id
+func (r *Report) GetEntries() []Entry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	out := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Language != b.Language {
+			return a.Language < b.Language
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.SastQueryID < b.SastQueryID
+	})
+	return out
+}
+
+// GenerateCSV returns the rows of the query resolution report
+func (r *Report) GenerateCSV() [][]string {
+	items := [][]string{{
+		"language", "group", "query", "sast_query_id", "ast_query_id", "source", "triaged_paths", "warning",
+	}}
+	for _, e := range r.GetEntries() {
+		items = append(items, []string{
+			e.Language,
+			e.Group,
+			e.Name,
+			e.SastQueryID,
+			e.AstQueryID,
+			e.Source,
+			strconv.Itoa(e.TriagedPaths),
+			e.Warning,
+		})
+	}
+	return items
+}
+
+// getQueryPackage returns whether a query path, like Java\Cx\Java High Risk\SQL Injection Version:1,
+// is in the product package or in a custom one, or unknown if the report has no path for the query
+func getQueryPackage(queryPath string) string {
+	parts := strings.Split(queryPath, queryPathSeparator)
+	switch {
+	case len(parts) < 2:
+		return unknownPackage
+	case parts[1] == productPackageFolder:
+		return productPackage
+	default:
+		return customPackage
+	}
+}
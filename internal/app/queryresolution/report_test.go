@@ -0,0 +1,64 @@
+package queryresolution
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/metadata"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	mock_interfaces_query_common "github.com/checkmarxDev/ast-sast-export/test/mocks/app/ast_query"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func getQuery(sastQueryID, language, group, name, queryPath string, results int) *metadata.Query {
+	query := &metadata.Query{QueryID: sastQueryID, Language: language, Group: group, Name: name, QueryPath: queryPath}
+	for i := 0; i < results; i++ {
+		query.Results = append(query.Results, &metadata.Result{ResultID: fmt.Sprintf("%d", i+1), PathID: "2"})
+	}
+	return query
+}
+
+func TestReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	astQueryProvider := mock_interfaces_query_common.NewMockASTQueryProvider(ctrl)
+	resolutions := map[string]*queryid.Resolution{
+		"1":      {AstQueryID: "101", Source: queryid.MappingSource},
+		"2":      {AstQueryID: "102", Source: queryid.HashSource},
+		"100001": {AstQueryID: "103", Source: queryid.HashSource},
+		"3":      {AstQueryID: "104", Source: queryid.RenameSource},
+		"5":      {AstQueryID: "105", Source: queryid.HashSource},
+	}
+	astQueryProvider.EXPECT().ResolveQuery(gomock.Any()).
+		DoAndReturn(func(query queryid.Query) (*queryid.Resolution, error) {
+			if resolution, exists := resolutions[query.SastQueryID]; exists {
+				return resolution, nil
+			}
+			return nil, fmt.Errorf("rename data not loaded")
+		}).
+		Times(6)
+	report := NewReport(astQueryProvider)
+
+	report.Add([]*metadata.Query{
+		getQuery("1", "Java", "Java_High_Risk", "SQL_Injection", `Java\Cx\Java High Risk\SQL Injection Version:1`, 2),
+		getQuery("2", "Java", "Java_High_Risk", "XSS", `Java\Cx\Java High Risk\XSS Version:1`, 1),
+		getQuery("100001", "Java", "Java_Corp", "Corp_XSS", `Java\Corp\Java Corp\Corp XSS Version:1`, 1),
+	})
+	report.Add([]*metadata.Query{
+		getQuery("1", "Java", "Java_High_Risk", "SQL_Injection", `Java\Cx\Java High Risk\SQL Injection Version:1`, 3),
+		getQuery("3", "CSharp", "CSharp_Low_Visibility", "Old_Name", `CSharp\Cx\CSharp Low Visibility\Old Name Version:2`, 1),
+		getQuery("4", "Go", "Go_High_Risk", "Broken", "", 1),
+		getQuery("5", "Go", "Go_Medium_Threat", "No_Path", "", 2),
+	})
+
+	expected := [][]string{
+		{"language", "group", "query", "sast_query_id", "ast_query_id", "source", "triaged_paths", "warning"},
+		{"CSharp", "CSharp_Low_Visibility", "Old_Name", "3", "104", "rename", "1", ""},
+		{"Go", "Go_High_Risk", "Broken", "4", "", "error", "1", "rename data not loaded"},
+		{"Go", "Go_Medium_Threat", "No_Path", "5", "105", "hash", "2", ""},
+		{"Java", "Java_Corp", "Corp_XSS", "100001", "103", "hash", "1", ""},
+		{"Java", "Java_High_Risk", "SQL_Injection", "1", "101", "mapping", "5", ""},
+		{"Java", "Java_High_Risk", "XSS", "2", "102", "hash", "1", HashFallbackWarning},
+	}
+	assert.Equal(t, expected, report.GenerateCSV())
+}
@@ -0,0 +1,161 @@
+package redaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Placeholder replaces the value of masked fields
+const Placeholder = "<redacted>"
+
+// secretKeys are the parts of field names that mark a field as a password, secret or token
+var secretKeys = []string{"password", "passwd", "secret", "token"}
+
+type (
+	// Redaction is a field of an exported file whose value was dropped, masked or hashed, and has to be re-entered
+	Redaction struct {
+		FileName string `json:"fileName"`
+		Path     string `json:"path"`
+		Action   string `json:"action"`
+	}
+
+	// Redactor replaces secrets in exported files and keeps track of what it replaced
+	Redactor struct {
+		rules      []Rule
+		redactions map[string][]*Redaction
+	}
+)
+
+// NewRedactor creates a redactor applying the given rules before the default ones
+func NewRedactor(rules []Rule) *Redactor {
+	return &Redactor{
+		rules:      append(append([]Rule{}, rules...), GetDefaultRules()...),
+		redactions: map[string][]*Redaction{},
+	}
+}
+
+// Redact applies the rule of each field of a JSON document, the rule for a file taking precedence over the rule for any file.
+// Fields without rule are masked if they're a password, secret or token, only non-empty string values are masked this way,
+// e.g. token expirations are kept. Redacting a file again replaces its previous redactions.
+func (r *Redactor) Redact(fileName string, data []byte) ([]byte, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s for redaction", fileName)
+	}
+	r.redactions[fileName] = []*Redaction{}
+	document = r.redactValue(fileName, "", document)
+	return json.Marshal(document)
+}
+
+// Redactions returns the fields redacted so far, ordered by file and path
+func (r *Redactor) Redactions() []*Redaction {
+	out := []*Redaction{}
+	for _, redactions := range r.redactions {
+		out = append(out, redactions...)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].FileName != out[j].FileName {
+			return out[i].FileName < out[j].FileName
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+func (r *Redactor) redactValue(fileName, valuePath string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range v {
+			fieldPath := joinPath(valuePath, key)
+			action := r.getAction(fileName, key, fieldValue)
+			if action == KeepAction || isEmpty(fieldValue) {
+				v[key] = r.redactValue(fileName, fieldPath, fieldValue)
+				continue
+			}
+			switch action {
+			case DropAction:
+				delete(v, key)
+			case MaskAction:
+				v[key] = Placeholder
+			case HashAction:
+				v[key] = hash(fieldValue)
+			}
+			r.redactions[fileName] = append(r.redactions[fileName], &Redaction{FileName: fileName, Path: fieldPath, Action: action})
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(fileName, fmt.Sprintf("%s[%d]", valuePath, i), item)
+		}
+	}
+	return value
+}
+
+func (r *Redactor) getAction(fileName, key string, value interface{}) string {
+	var anyFileAction string
+	for _, rule := range r.rules {
+		if !strings.EqualFold(rule.Field, key) {
+			continue
+		}
+		if matchesFile(rule.FileName, fileName) {
+			return rule.Action
+		}
+		if rule.FileName == "" && anyFileAction == "" {
+			anyFileAction = rule.Action
+		}
+	}
+	if anyFileAction != "" {
+		return anyFileAction
+	}
+	if _, isString := value.(string); isString && isSecret(key) {
+		return MaskAction
+	}
+	return KeepAction
+}
+
+// matchesFile tells if a rule for the file name, which can be a pattern, applies to the file
+func matchesFile(ruleFileName, fileName string) bool {
+	if ruleFileName == "" {
+		return false
+	}
+	matched, _ := path.Match(ruleFileName, fileName)
+	return matched
+}
+
+func isSecret(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, secretKey := range secretKeys {
+		if strings.Contains(lowerKey, secretKey) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmpty(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+// hash returns the sha256 of a value, objects and arrays are hashed by their JSON
+func hash(value interface{}) string {
+	data, ok := value.(string)
+	if !ok {
+		encoded, _ := json.Marshal(value)
+		data = string(encoded)
+	}
+	sum := sha256.Sum256([]byte(data))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
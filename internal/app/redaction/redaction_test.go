@@ -0,0 +1,102 @@
+package redaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	t.Run("masks secrets and lists them", func(t *testing.T) {
+		redactor := NewRedactor(nil)
+		data := `{"smtp": {"host": "smtp.example.com", "password": "p4ss", "useTls": true},
+			"providers": [{"name": "okta", "clientSecret": "s3cret", "tokenExpiration": 3600, "apiToken": ""}]}`
+
+		result, err := redactor.Redact("system_settings.json", []byte(data))
+
+		assert.NoError(t, err)
+		expected := `{"providers": [{"apiToken": "", "clientSecret": "<redacted>", "name": "okta", "tokenExpiration": 3600}],
+			"smtp": {"host": "smtp.example.com", "password": "<redacted>", "useTls": true}}`
+		assert.JSONEq(t, expected, string(result))
+		expectedRedactions := []*Redaction{
+			{FileName: "system_settings.json", Path: "providers[0].clientSecret", Action: MaskAction},
+			{FileName: "system_settings.json", Path: "smtp.password", Action: MaskAction},
+		}
+		assert.Equal(t, expectedRedactions, redactor.Redactions())
+	})
+
+	t.Run("applies rules", func(t *testing.T) {
+		redactor := NewRedactor([]Rule{
+			{Field: "host", Action: HashAction},
+			{FileName: "ldap_servers.json", Field: "baseDn", Action: DropAction},
+			{FileName: "ldap_servers.json", Field: "password", Action: KeepAction},
+			{FileName: "other.json", Field: "name", Action: MaskAction},
+		})
+		data := `[{"name": "corp", "host": "ldap.corp.local", "baseDn": "dc=corp,dc=local", "password": "p4ss"}]`
+
+		result, err := redactor.Redact("ldap_servers.json", []byte(data))
+
+		assert.NoError(t, err)
+		expected := `[{"name": "corp", "host": "sha256:346c6d193329fb826f924d29ad316f2ea1911f4273d1a3835172050fb97f3e41",
+			"password": "p4ss"}]`
+		assert.JSONEq(t, expected, string(result))
+		expectedRedactions := []*Redaction{
+			{FileName: "ldap_servers.json", Path: "[0].baseDn", Action: DropAction},
+			{FileName: "ldap_servers.json", Path: "[0].host", Action: HashAction},
+		}
+		assert.Equal(t, expectedRedactions, redactor.Redactions())
+	})
+
+	t.Run("applies default rules", func(t *testing.T) {
+		redactor := NewRedactor(nil)
+
+		result, err := redactor.Redact("ldap_servers.json", []byte(`[{"username": "cn=bind", "password": "p4ss"}]`))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"username": "<redacted>"}]`, string(result))
+	})
+
+	t.Run("applies rules to the files matching their file name", func(t *testing.T) {
+		redactor := NewRedactor([]Rule{{FileName: "project_settings/*.json", Field: "values", Action: HashAction}})
+		data := `{"postScanAction": {"id": 2, "arguments": "--token abc"}, "issueTracker": {"fields": [{"id": 5, "values": ["x"]}]}}`
+
+		result, err := redactor.Redact("project_settings/7.json", []byte(data))
+
+		assert.NoError(t, err)
+		expected := `{"postScanAction": {"id": 2, "arguments": "<redacted>"}, "issueTracker": {"fields": [{"id": 5,
+			"values": "sha256:cd65ea2c2ad99e94a85b1b6df72efef9cb2ed0ae933a60c32ce16317f7d7d6aa"}]}}`
+		assert.JSONEq(t, expected, string(result))
+		expectedRedactions := []*Redaction{
+			{FileName: "project_settings/7.json", Path: "issueTracker.fields[0].values", Action: HashAction},
+			{FileName: "project_settings/7.json", Path: "postScanAction.arguments", Action: MaskAction},
+		}
+		assert.Equal(t, expectedRedactions, redactor.Redactions())
+	})
+
+	t.Run("replaces the redactions of a file redacted again", func(t *testing.T) {
+		redactor := NewRedactor(nil)
+
+		_, err := redactor.Redact("b.json", []byte(`[{"Password": "b"}]`))
+		assert.NoError(t, err)
+		_, err = redactor.Redact("a.json", []byte(`{"secret": "a"}`))
+		assert.NoError(t, err)
+		_, err = redactor.Redact("b.json", []byte(`[{"Password": "b"}]`))
+		assert.NoError(t, err)
+
+		expected := []*Redaction{
+			{FileName: "a.json", Path: "secret", Action: MaskAction},
+			{FileName: "b.json", Path: "[0].Password", Action: MaskAction},
+		}
+		assert.Equal(t, expected, redactor.Redactions())
+	})
+
+	t.Run("fails if data isn't json", func(t *testing.T) {
+		redactor := NewRedactor(nil)
+
+		result, err := redactor.Redact("system_settings.json", []byte("<xml/>"))
+
+		assert.EqualError(t, err, "could not parse system_settings.json for redaction: invalid character '<' looking for beginning of value")
+		assert.Nil(t, result)
+		assert.Empty(t, redactor.Redactions())
+	})
+}
@@ -0,0 +1,90 @@
+package redaction
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/export"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DropAction removes the field
+	DropAction = "drop"
+	// MaskAction replaces the value of the field with the placeholder
+	MaskAction = "mask"
+	// HashAction replaces the value of the field with its sha256, so equal values can still be matched
+	HashAction = "hash"
+	// KeepAction exports the field as is
+	KeepAction = "keep"
+)
+
+type (
+	// Rule is how to redact a field, in the files matching the file name, e.g. project_settings/*.json,
+	// or in any file if no file name is given
+	Rule struct {
+		FileName string `json:"fileName,omitempty"`
+		Field    string `json:"field"`
+		Action   string `json:"action"`
+	}
+
+	// RuleSource is the format of the redaction rules file
+	RuleSource struct {
+		Rules []Rule `json:"rules"`
+	}
+)
+
+// GetDefaultRules returns the rules applied unless overridden, which remove the LDAP bind credentials, the
+// identity provider certificates and the post-scan action arguments of projects, which often pass credentials
+func GetDefaultRules() []Rule {
+	return []Rule{
+		{FileName: export.LdapServersFileName, Field: "username", Action: MaskAction},
+		{FileName: export.LdapServersFileName, Field: "password", Action: DropAction},
+		{FileName: export.SamlIdpFileName, Field: "certificate", Action: DropAction},
+		{FileName: path.Join(export.ProjectSettingsDirName, "*.json"), Field: "arguments", Action: MaskAction},
+	}
+}
+
+// Load creates a redactor with the rules in the redaction rules file, or with the default rules if no file is given
+func Load(fileName string) (*Redactor, error) {
+	if fileName == "" {
+		return NewRedactor(nil), nil
+	}
+	data, ioErr := os.ReadFile(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read redaction rules file")
+	}
+	var ruleSource RuleSource
+	if jsonErr := json.Unmarshal(data, &ruleSource); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse redaction rules file")
+	}
+	if validateErr := validate(ruleSource.Rules); validateErr != nil {
+		return nil, validateErr
+	}
+	return NewRedactor(ruleSource.Rules), nil
+}
+
+func validate(rules []Rule) error {
+	fields := map[string]bool{}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Field) == "" {
+			return errors.Errorf("redaction rule %d must have a field", i+1)
+		}
+		if _, matchErr := path.Match(rule.FileName, ""); matchErr != nil {
+			return errors.Errorf("redaction rule %d has invalid file name %s", i+1, rule.FileName)
+		}
+		switch rule.Action {
+		case DropAction, MaskAction, HashAction, KeepAction:
+		default:
+			return errors.Errorf("redaction rule %d has unknown action %s", i+1, rule.Action)
+		}
+		field := rule.FileName + "/" + strings.ToLower(rule.Field)
+		if fields[field] {
+			return errors.Errorf("redaction rule %d repeats field %s", i+1, rule.Field)
+		}
+		fields[field] = true
+	}
+	return nil
+}
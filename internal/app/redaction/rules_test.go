@@ -0,0 +1,53 @@
+package redaction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "loads rules",
+			content: `{"rules": [{"fileName": "ldap_servers.json", "field": "host", "action": "hash"},
+				{"field": "host", "action": "keep"}]}`,
+		},
+		{name: "rule without field", content: `{"rules": [{"action": "drop"}]}`, expectedError: "redaction rule 1 must have a field"},
+		{name: "unknown action", content: `{"rules": [{"field": "host", "action": "encrypt"}]}`,
+			expectedError: "redaction rule 1 has unknown action encrypt"},
+		{name: "repeated field", content: `{"rules": [{"field": "host", "action": "drop"}, {"field": "Host", "action": "mask"}]}`,
+			expectedError: "redaction rule 2 repeats field Host"},
+		{name: "invalid file name", content: `{"rules": [{"fileName": "project_settings/[.json", "field": "host", "action": "drop"}]}`,
+			expectedError: "redaction rule 1 has invalid file name project_settings/[.json"},
+		{name: "invalid json", content: `{`, expectedError: "could not parse redaction rules file: unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "redaction_rules.json")
+			require.NoError(t, os.WriteFile(fileName, []byte(test.content), 0600))
+
+			result, err := Load(fileName)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.rules, 2+len(GetDefaultRules()))
+			}
+		})
+	}
+	t.Run("without file", func(t *testing.T) {
+		result, err := Load("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, GetDefaultRules(), result.rules)
+	})
+}
@@ -0,0 +1,299 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	rootElementName     = "CxXMLResults"
+	queryElementName    = "Query"
+	resultElementName   = "Result"
+	pathElementName     = "Path"
+	pathNodeElementName = "PathNode"
+
+	// element depths in the report, starting with the root element
+	rootDepth     = 1
+	queryDepth    = 2
+	resultDepth   = 3
+	pathDepth     = 4
+	pathNodeDepth = 5
+)
+
+// ElementRewriter may change the attributes of a report element before it's written, returning true if it did
+type ElementRewriter func(element *xml.StartElement) bool
+
+// Stream copies a scan report from reader to writer one token at a time, so the report is never held in memory.
+// Tokens are written exactly as they were read. When rewrite changes attribute values of an element,
+// only those values are replaced, keeping the rest of the element byte for byte.
+// The returned report holds what's needed for metadata: the report attributes, and the triaged results
+// with only the first and last node of each path.
+func Stream(reader io.Reader, writer io.Writer, rewrite ElementRewriter) (*CxXMLResults, error) {
+	source := &recordingReader{reader: bufio.NewReader(reader)}
+	decoder := xml.NewDecoder(source)
+	var c collector
+	var offset int64
+	for {
+		token, tokenErr := decoder.RawToken()
+		if tokenErr == io.EOF {
+			break
+		}
+		if tokenErr != nil {
+			return nil, errors.Wrap(tokenErr, "could not read report")
+		}
+		raw := source.consume(decoder.InputOffset() - offset)
+		offset = decoder.InputOffset()
+
+		if element, ok := token.(xml.StartElement); ok && rewrite != nil {
+			original := element.Copy()
+			if rewrite(&element) {
+				token = element
+				raw = rewriteStartElement(raw, original, element)
+			}
+		}
+		if collectErr := c.collect(token, raw); collectErr != nil {
+			return nil, collectErr
+		}
+		if _, writeErr := writer.Write(raw); writeErr != nil {
+			return nil, errors.Wrap(writeErr, "could not write report")
+		}
+	}
+	if c.report == nil || c.depth != 0 {
+		return nil, errors.New("report is incomplete")
+	}
+	return c.report, nil
+}
+
+// collector keeps the report data needed for metadata while the report is streamed
+type collector struct {
+	report   *CxXMLResults
+	query    *Query
+	result   *Result
+	path     *Path
+	pathNode []byte
+	inNode   bool
+	depth    int
+}
+
+func (e *collector) collect(token xml.Token, raw []byte) error {
+	switch t := token.(type) {
+	case xml.StartElement:
+		e.depth++
+		return e.startElement(t, raw)
+	case xml.EndElement:
+		e.depth--
+		if e.depth < 0 {
+			return errors.Errorf("unexpected end element %s", t.Name.Local)
+		}
+		return e.endElement(raw)
+	default:
+		if e.inNode {
+			e.pathNode = append(e.pathNode, raw...)
+		}
+	}
+	return nil
+}
+
+func (e *collector) startElement(element xml.StartElement, raw []byte) error {
+	if e.inNode {
+		e.pathNode = append(e.pathNode, raw...)
+		return nil
+	}
+	name := element.Name.Local
+	switch {
+	case e.depth == rootDepth:
+		if name != rootElementName || e.report != nil {
+			return errors.Errorf("unexpected root element %s", name)
+		}
+		e.report = &CxXMLResults{}
+		return unmarshalStartElement(raw, name, e.report)
+	case e.depth == queryDepth && name == queryElementName:
+		e.query = &Query{}
+		return unmarshalStartElement(raw, name, e.query)
+	case e.depth == resultDepth && name == resultElementName && e.query != nil:
+		e.result = &Result{}
+		return unmarshalStartElement(raw, name, e.result)
+	case e.depth == pathDepth && name == pathElementName && e.result != nil:
+		e.path = &Path{}
+		return unmarshalStartElement(raw, name, e.path)
+	case e.depth == pathNodeDepth && name == pathNodeElementName && e.path != nil:
+		e.inNode = true
+		e.pathNode = append(e.pathNode[:0], raw...)
+	}
+	return nil
+}
+
+func (e *collector) endElement(raw []byte) error {
+	if e.inNode {
+		e.pathNode = append(e.pathNode, raw...)
+		if e.depth == pathDepth {
+			e.inNode = false
+			var node PathNode
+			if err := xml.Unmarshal(e.pathNode, &node); err != nil {
+				return errors.Wrap(err, "could not read path node")
+			}
+			// only the first and last nodes are kept
+			if len(e.path.PathNodes) < 2 {
+				e.path.PathNodes = append(e.path.PathNodes, node)
+			} else {
+				e.path.PathNodes[1] = node
+			}
+		}
+		return nil
+	}
+	switch e.depth {
+	case resultDepth:
+		if e.path != nil {
+			e.result.Paths = append(e.result.Paths, *e.path)
+			e.path = nil
+		}
+	case queryDepth:
+		// only triaged results are kept
+		if e.result != nil && e.result.State != "0" {
+			e.query.Results = append(e.query.Results, *e.result)
+		}
+		e.result = nil
+	case rootDepth:
+		if e.query != nil && len(e.query.Results) > 0 {
+			e.report.Queries = append(e.report.Queries, *e.query)
+		}
+		e.query = nil
+	}
+	return nil
+}
+
+// recordingReader keeps the bytes read by the decoder until they're consumed
+type recordingReader struct {
+	reader   *bufio.Reader
+	recorded []byte
+}
+
+func (e *recordingReader) Read(p []byte) (int, error) {
+	n, err := e.reader.Read(p)
+	e.recorded = append(e.recorded, p[:n]...)
+	return n, err
+}
+
+func (e *recordingReader) ReadByte() (byte, error) {
+	b, err := e.reader.ReadByte()
+	if err == nil {
+		e.recorded = append(e.recorded, b)
+	}
+	return b, err
+}
+
+// consume returns the next n recorded bytes, which remain valid until the following read
+func (e *recordingReader) consume(n int64) []byte {
+	output := e.recorded[:n]
+	e.recorded = e.recorded[n:]
+	return output
+}
+
+func isSelfClosing(raw []byte) bool {
+	return bytes.HasSuffix(raw, []byte("/>"))
+}
+
+func getQualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// rewriteStartElement replaces the changed attribute values in the raw start element.
+// The element is marshaled again only if attributes were added, removed or renamed.
+func rewriteStartElement(raw []byte, original, changed xml.StartElement) []byte {
+	valueSpans := getAttrValueSpans(raw)
+	if len(valueSpans) != len(original.Attr) || len(original.Attr) != len(changed.Attr) {
+		return marshalStartElement(changed, isSelfClosing(raw))
+	}
+	for i := range original.Attr {
+		if original.Attr[i].Name != changed.Attr[i].Name {
+			return marshalStartElement(changed, isSelfClosing(raw))
+		}
+	}
+	output := make([]byte, 0, len(raw))
+	last := 0
+	for i, span := range valueSpans {
+		if original.Attr[i].Value == changed.Attr[i].Value {
+			continue
+		}
+		output = append(output, raw[last:span[0]]...)
+		var value bytes.Buffer
+		_ = xml.EscapeText(&value, []byte(changed.Attr[i].Value))
+		output = append(output, value.Bytes()...)
+		last = span[1]
+	}
+	return append(output, raw[last:]...)
+}
+
+// getAttrValueSpans returns the start and end offsets of each attribute value in a raw start element,
+// between its quotes and in the order attributes are declared
+func getAttrValueSpans(raw []byte) [][2]int {
+	var spans [][2]int
+	i := bytes.IndexAny(raw, " \t\r\n")
+	if i < 0 {
+		return spans
+	}
+	for i < len(raw) {
+		equals := bytes.IndexByte(raw[i:], '=')
+		if equals < 0 {
+			break
+		}
+		i += equals + 1
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i >= len(raw) || (raw[i] != '"' && raw[i] != '\'') {
+			break
+		}
+		closing := bytes.IndexByte(raw[i+1:], raw[i])
+		if closing < 0 {
+			break
+		}
+		spans = append(spans, [2]int{i + 1, i + 1 + closing})
+		i += closing + 2
+	}
+	return spans
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func marshalStartElement(element xml.StartElement, selfClosing bool) []byte {
+	var b bytes.Buffer
+	b.WriteString("<")
+	b.WriteString(getQualifiedName(element.Name))
+	for _, attr := range element.Attr {
+		b.WriteString(" ")
+		b.WriteString(getQualifiedName(attr.Name))
+		b.WriteString(`="`)
+		_ = xml.EscapeText(&b, []byte(attr.Value))
+		b.WriteString(`"`)
+	}
+	if selfClosing {
+		b.WriteString("/>")
+	} else {
+		b.WriteString(">")
+	}
+	return b.Bytes()
+}
+
+// unmarshalStartElement reads the attributes of a start element into output
+func unmarshalStartElement(raw []byte, name string, output interface{}) error {
+	data := raw
+	if !isSelfClosing(raw) {
+		data = make([]byte, 0, len(raw)+len(name)+3)
+		data = append(data, raw...)
+		data = append(data, "</"+name+">"...)
+	}
+	if err := xml.Unmarshal(data, output); err != nil {
+		return errors.Wrapf(err, "could not read %s element", name)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	report1, ioErr := os.ReadFile("../../../test/data/process/report1.xml")
+	require.NoError(t, ioErr)
+
+	t.Run("copies report as is", func(t *testing.T) {
+		var output bytes.Buffer
+
+		result, err := Stream(bytes.NewReader(report1), &output, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(report1), output.String())
+		assert.Equal(t, "1000002", result.ScanID)
+		assert.Equal(t, "CxServer", result.Team)
+		assert.Empty(t, result.Queries)
+	})
+
+	t.Run("collects triaged results with first and last path nodes", func(t *testing.T) {
+		var output bytes.Buffer
+		triage := func(element *xml.StartElement) bool {
+			if element.Name.Local != "Result" {
+				return false
+			}
+			for i := range element.Attr {
+				if element.Attr[i].Name.Local == "NodeId" && element.Attr[i].Value == "10000020002" {
+					setAttr(element, "state", "2")
+					return true
+				}
+			}
+			return false
+		}
+
+		result, err := Stream(bytes.NewReader(report1), &output, triage)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Queries, 1)
+		assert.Equal(t, "6300", result.Queries[0].ID)
+		assert.Equal(t, "SQL_Injection", result.Queries[0].Name)
+		assert.Len(t, result.Queries[0].Results, 1)
+		triagedResult := result.Queries[0].Results[0]
+		assert.Equal(t, "2", triagedResult.State)
+		assert.Len(t, triagedResult.Paths, 1)
+		path := triagedResult.Paths[0]
+		assert.Equal(t, "1000002", path.ResultID)
+		assert.Equal(t, "2", path.PathID)
+		assert.Equal(t, "-748694192", path.SimilarityID)
+		assert.Len(t, path.PathNodes, 2)
+		assert.Equal(t, "text", path.PathNodes[0].Name)
+		assert.Equal(t, "83", path.PathNodes[0].Line)
+		assert.Equal(t, "78", path.PathNodes[0].Column)
+		assert.Equal(t, "update", path.PathNodes[1].Name)
+		assert.Equal(t, "Goatlin-develop/packages/clients/android/app/src/main/java/com/cx/goatlin/helpers/DatabaseHelper.kt",
+			path.PathNodes[1].FileName)
+		assert.Equal(t, "138", path.PathNodes[1].Line)
+		assert.Equal(t, 1, strings.Count(output.String(), `state="2"`))
+	})
+
+	t.Run("rewrites self closing elements", func(t *testing.T) {
+		report := `<CxXMLResults ScanId="1"><Query id="1"><Result state="0"/></Query></CxXMLResults>`
+		var output bytes.Buffer
+		triage := func(element *xml.StartElement) bool {
+			if element.Name.Local == "Result" {
+				setAttr(element, "state", "1")
+				return true
+			}
+			return false
+		}
+
+		result, err := Stream(strings.NewReader(report), &output, triage)
+
+		assert.NoError(t, err)
+		expected := `<CxXMLResults ScanId="1"><Query id="1"><Result state="1"/></Query></CxXMLResults>`
+		assert.Equal(t, expected, output.String())
+		assert.Len(t, result.Queries, 1)
+	})
+
+	t.Run("keeps the formatting of rewritten elements", func(t *testing.T) {
+		report := "<CxXMLResults ScanId=\"1\">\n<Query id='1'>\n" +
+			"<Result  Remark='a &quot;b&quot;&#xA;c'\n\tstate = '1' FileName=\"a&amp;b.js\"></Result>\n" +
+			"</Query>\n</CxXMLResults>"
+		var output bytes.Buffer
+		triage := func(element *xml.StartElement) bool {
+			if element.Name.Local == "Result" {
+				setAttr(element, "state", "1001")
+				return true
+			}
+			return false
+		}
+
+		_, err := Stream(strings.NewReader(report), &output, triage)
+
+		assert.NoError(t, err)
+		expected := strings.Replace(report, "state = '1'", "state = '1001'", 1)
+		assert.Equal(t, expected, output.String())
+	})
+
+	t.Run("fails if report is incomplete", func(t *testing.T) {
+		truncatedReport := report1[:bytes.Index(report1, []byte(`<Query id="3922"`))]
+		var output bytes.Buffer
+
+		_, err := Stream(bytes.NewReader(truncatedReport), &output, nil)
+
+		assert.EqualError(t, err, "report is incomplete")
+	})
+
+	t.Run("fails if report is not a scan report", func(t *testing.T) {
+		var output bytes.Buffer
+
+		_, err := Stream(strings.NewReader(`<Presets></Presets>`), &output, nil)
+
+		assert.EqualError(t, err, "unexpected root element Presets")
+	})
+}
+
+func setAttr(element *xml.StartElement, name, value string) {
+	for i := range element.Attr {
+		if element.Attr[i].Name.Local == name {
+			element.Attr[i].Value = value
+		}
+	}
+}
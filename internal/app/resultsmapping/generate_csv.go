@@ -7,29 +7,94 @@ import (
 	"github.com/checkmarxDev/ast-sast-export/internal/app/metadata"
 )
 
-func GenerateCSV(records []*metadata.Record) [][]string {
-	var items [][]string
-	items = append(items, []string{
-		"result_id",
-		"cxone_similarity_id",
-		"sast_similarity_id",
-	})
-	if records == nil {
-		return items
-	}
+// Entry maps a triaged SAST result path to its CxOne similarity id, along with its triage details
+type Entry struct {
+	ResultID          string `json:"resultId"`
+	CxOneSimilarityID string `json:"cxoneSimilarityId"`
+	SASTSimilarityID  string `json:"sastSimilarityId"`
+	ProjectID         string `json:"projectId"`
+	ScanID            string `json:"scanId"`
+	PathID            string `json:"pathId"`
+	QueryID           string `json:"queryId"`
+	AstQueryID        string `json:"astQueryId"`
+	OriginalSeverity  string `json:"originalSeverity"`
+	Severity          string `json:"severity"`
+	State             string `json:"state"`
+	Assignee          string `json:"assignee"`
+	DetectionDate     string `json:"detectionDate"`
+	FirstNodeFileName string `json:"firstNodeFileName"`
+	FirstNodeLine     string `json:"firstNodeLine"`
+}
+
+// GetEntries returns an entry for each path of the metadata records
+func GetEntries(records []*metadata.Record) []*Entry {
+	entries := []*Entry{}
 	for _, record := range records {
 		for _, query := range record.Queries {
 			for _, result := range query.Results {
 				for _, path := range result.Paths {
-					items = append(items, []string{
-						path.ResultID,
-						path.SimilarityID,
-						path.SASTSimilarityID,
+					entries = append(entries, &Entry{
+						ResultID:          path.ResultID,
+						CxOneSimilarityID: path.SimilarityID,
+						SASTSimilarityID:  path.SASTSimilarityID,
+						ProjectID:         record.ProjectID,
+						ScanID:            record.ScanID,
+						PathID:            path.PathID,
+						QueryID:           query.QueryID,
+						AstQueryID:        query.AstQueryID,
+						OriginalSeverity:  path.OriginalSeverity,
+						Severity:          path.Severity,
+						State:             path.State,
+						Assignee:          path.Assignee,
+						DetectionDate:     path.DetectionDate,
+						FirstNodeFileName: path.FileName,
+						FirstNodeLine:     path.Line,
 					})
 				}
 			}
 		}
 	}
+	return entries
+}
+
+func GenerateCSV(records []*metadata.Record) [][]string {
+	var items [][]string
+	items = append(items, []string{
+		"result_id",
+		"cxone_similarity_id",
+		"sast_similarity_id",
+		"project_id",
+		"scan_id",
+		"path_id",
+		"query_id",
+		"ast_query_id",
+		"original_severity",
+		"severity",
+		"state",
+		"assignee",
+		"detection_date",
+		"first_node_file_name",
+		"first_node_line",
+	})
+	for _, entry := range GetEntries(records) {
+		items = append(items, []string{
+			entry.ResultID,
+			entry.CxOneSimilarityID,
+			entry.SASTSimilarityID,
+			entry.ProjectID,
+			entry.ScanID,
+			entry.PathID,
+			entry.QueryID,
+			entry.AstQueryID,
+			entry.OriginalSeverity,
+			entry.Severity,
+			entry.State,
+			entry.Assignee,
+			entry.DetectionDate,
+			entry.FirstNodeFileName,
+			entry.FirstNodeLine,
+		})
+	}
 
 	return items
 }
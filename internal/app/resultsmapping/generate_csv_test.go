@@ -8,7 +8,10 @@ import (
 )
 
 func TestGenerateCsv(t *testing.T) {
-	var latestVersionHeaders = []string{"result_id", "cxone_similarity_id", "sast_similarity_id"}
+	var latestVersionHeaders = []string{
+		"result_id", "cxone_similarity_id", "sast_similarity_id", "project_id", "scan_id", "path_id", "query_id", "ast_query_id",
+		"original_severity", "severity", "state", "assignee", "detection_date", "first_node_file_name", "first_node_line",
+	}
 	similarityID1New := "-1234567890"
 	similarityID2New := "-1234567891"
 	similarityID1 := "-1234567890"
@@ -55,9 +58,12 @@ func TestGenerateCsv(t *testing.T) {
 		Results:  []*metadata.Result{&metaResult1, &metaResult2},
 	}
 	inputRecord1 := &metadata.Record{
+		ProjectID: "1",
+		ScanID:    "1000",
 		Queries: []*metadata.RecordQuery{
 			{
-				QueryID: metaQuery.QueryID,
+				QueryID:    metaQuery.QueryID,
+				AstQueryID: "12532796926860742976",
 				Results: []*metadata.RecordResult{
 					{
 						ResultID: metaResult1.ResultID,
@@ -66,6 +72,14 @@ func TestGenerateCsv(t *testing.T) {
 								PathID:           metaResult1.PathID,
 								SimilarityID:     similarityID1New,
 								SASTSimilarityID: similarityID1,
+								ResultID:         metaResult1.ResultID,
+								OriginalSeverity: "High",
+								Severity:         "Medium",
+								State:            "1",
+								Assignee:         "alice",
+								DetectionDate:    "4/21/2022 7:26:26 PM",
+								FileName:         "path/file1.kt",
+								Line:             "83",
 							},
 							{
 								PathID:           metaResult2.PathID,
@@ -103,23 +117,17 @@ func TestGenerateCsv(t *testing.T) {
 		inputRecord2,
 	}
 
-	items1 := [][]string{
-		{"result_id", "cxone_similarity_id", "sast_similarity_id"},
-		{"", "-1234567890", "-1234567890"},
-		{"", "-1234567891", "-1234567891"},
-	}
+	row1 := []string{"1000002", "-1234567890", "-1234567890", "1", "1000", "2", "6300", "12532796926860742976",
+		"High", "Medium", "1", "alice", "4/21/2022 7:26:26 PM", "path/file1.kt", "83"}
+	row2 := []string{"", "-1234567891", "-1234567891", "1", "1000", "3", "6300", "12532796926860742976",
+		"", "", "", "", "", "", ""}
+	row3 := []string{"", "-1234567890", "-1234567890", "", "", "2", "6300", "", "", "", "", "", "", "", ""}
 
-	items2 := [][]string{
-		{"result_id", "cxone_similarity_id", "sast_similarity_id"},
-		{"", "-1234567890", "-1234567890"},
-	}
+	items1 := [][]string{latestVersionHeaders, row1, row2}
 
-	itemsAll := [][]string{
-		{"result_id", "cxone_similarity_id", "sast_similarity_id"},
-		{"", "-1234567890", "-1234567890"},
-		{"", "-1234567891", "-1234567891"},
-		{"", "-1234567890", "-1234567890"},
-	}
+	items2 := [][]string{latestVersionHeaders, row3}
+
+	itemsAll := [][]string{latestVersionHeaders, row1, row2, row3}
 
 	t.Run("validate csv data returned from results", func(t *testing.T) {
 		result := GenerateCSV([]*metadata.Record{inputRecord1})
@@ -146,6 +154,19 @@ func TestGenerateCsv(t *testing.T) {
 		assert.Equal(t, expectedResult, result)
 	})
 
+	t.Run("entries have the data of the csv", func(t *testing.T) {
+		result := GetEntries([]*metadata.Record{inputRecord1})
+
+		expected := &Entry{
+			ResultID: "1000002", CxOneSimilarityID: "-1234567890", SASTSimilarityID: "-1234567890", ProjectID: "1", ScanID: "1000",
+			PathID: "2", QueryID: "6300", AstQueryID: "12532796926860742976", OriginalSeverity: "High", Severity: "Medium",
+			State: "1", Assignee: "alice", DetectionDate: "4/21/2022 7:26:26 PM", FirstNodeFileName: "path/file1.kt",
+			FirstNodeLine: "83",
+		}
+		assert.Len(t, result, 2)
+		assert.Equal(t, expected, result[0])
+	})
+
 	t.Run("success returns only headers if is passed nil from model", func(t *testing.T) {
 		expectedResult := [][]string{latestVersionHeaders}
 		result := GenerateCSV(nil)
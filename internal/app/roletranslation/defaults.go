@@ -0,0 +1,44 @@
+package roletranslation
+
+// GetDefaultTable returns the built-in translation of SAST system roles and permissions.
+// Permissions without a CxOne equivalent are left out, so they are reported as unmapped.
+func GetDefaultTable() Table {
+	return Table{
+		Roles: map[string][]string{
+			"Admin":         {"iam-admin"},
+			"SAST Admin":    {"ast-admin"},
+			"SAST Auditor":  {"ast-risk-manager"},
+			"SAST Reviewer": {"ast-viewer"},
+			"SAST Scanner":  {"ast-scanner"},
+			"SAST Viewer":   {"ast-viewer"},
+		},
+		Permissions: map[string][]string{
+			// Access Control
+			"manage-users":                    {"manage-users"},
+			"manage-teams":                    {"manage-groups"},
+			"manage-roles":                    {"manage-roles"},
+			"manage-authentication-providers": {"manage-identity-providers"},
+			// SAST projects and scans
+			"create-project":    {"create-project"},
+			"update-project":    {"update-project"},
+			"delete-project":    {"delete-project"},
+			"save-sast-scan":    {"create-scan"},
+			"delete-sast-scan":  {"delete-scan"},
+			"download-scan-log": {"view-scans"},
+			// SAST results
+			"view-results":                              {"view-results", "view-projects", "view-scans"},
+			"generate-scan-report":                      {"view-results"},
+			"update-result-severity":                    {"update-result-severity"},
+			"manage-result-assignee":                    {"update-result"},
+			"manage-result-comment":                     {"update-result"},
+			"set-result-state-to-verify":                {"update-result-states"},
+			"set-result-state-not-exploitable":          {"update-result-states"},
+			"set-result-state-confirmed":                {"update-result-states"},
+			"set-result-state-urgent":                   {"update-result-states"},
+			"set-result-state-proposed-not-exploitable": {"update-result-states"},
+			// SAST configuration
+			"manage-presets": {"manage-presets"},
+			"manage-queries": {"manage-queries"},
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package roletranslation
+
+type (
+	// Table maps SAST role names and SAST permission names to their CxOne equivalents
+	Table struct {
+		Roles       map[string][]string `json:"roles"`
+		Permissions map[string][]string `json:"permissions"`
+	}
+
+	// Translation is what's exported for admins to review before import
+	Translation struct {
+		Roles []RoleTranslation `json:"roles"`
+		// UnmappedPermissions lists the permissions granted by any role that have no CxOne equivalent
+		UnmappedPermissions []string `json:"unmappedPermissions"`
+	}
+
+	RoleTranslation struct {
+		ID                  int      `json:"id"`
+		Name                string   `json:"name"`
+		IsSystemRole        bool     `json:"isSystemRole"`
+		CxOneRoles          []string `json:"cxOneRoles"`
+		CxOnePermissions    []string `json:"cxOnePermissions"`
+		UnmappedPermissions []string `json:"unmappedPermissions"`
+	}
+)
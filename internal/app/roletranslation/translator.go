@@ -0,0 +1,103 @@
+package roletranslation
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/pkg/errors"
+)
+
+// Translator translates SAST roles and their permissions to CxOne roles and permissions
+type Translator struct {
+	table Table
+}
+
+// NewTranslator creates a translator with the given table
+func NewTranslator(table Table) *Translator {
+	return &Translator{table: table}
+}
+
+// Load creates a translator with the built-in table, overridden by the entries in the role translation file if given.
+// An entry mapping to an empty list marks a role or permission as having no CxOne equivalent.
+func Load(fileName string) (*Translator, error) {
+	table := GetDefaultTable()
+	if fileName == "" {
+		return NewTranslator(table), nil
+	}
+	data, ioErr := os.ReadFile(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read role translation file")
+	}
+	var override Table
+	if jsonErr := json.Unmarshal(data, &override); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse role translation file")
+	}
+	for name, cxOneRoles := range override.Roles {
+		table.Roles[name] = cxOneRoles
+	}
+	for name, cxOnePermissions := range override.Permissions {
+		table.Permissions[name] = cxOnePermissions
+	}
+	return NewTranslator(table), nil
+}
+
+// ParseRoles reads the roles returned by SAST
+func ParseRoles(data []byte) ([]*rest.Role, error) {
+	var roles []*rest.Role
+	if err := json.Unmarshal(data, &roles); err != nil {
+		return nil, errors.Wrap(err, "could not parse roles")
+	}
+	return roles, nil
+}
+
+// Translate maps each role to CxOne roles, by name, and to CxOne permissions, by the permissions it grants.
+// Permissions unknown to SAST are identified by their id.
+func (t *Translator) Translate(roles []*rest.Role, permissions []*rest.Permission) *Translation {
+	permissionNames := map[int]string{}
+	for _, e := range permissions {
+		permissionNames[e.ID] = e.Name
+	}
+	out := &Translation{Roles: make([]RoleTranslation, 0, len(roles))}
+	unmappedPermissions := map[string]bool{}
+	for _, role := range roles {
+		cxOnePermissions := map[string]bool{}
+		roleUnmappedPermissions := map[string]bool{}
+		for _, permissionID := range role.PermissionIDs {
+			name, exists := permissionNames[permissionID]
+			if !exists {
+				name = strconv.Itoa(permissionID)
+			}
+			mapped := t.table.Permissions[name]
+			if len(mapped) == 0 {
+				roleUnmappedPermissions[name] = true
+				unmappedPermissions[name] = true
+			}
+			for _, cxOnePermission := range mapped {
+				cxOnePermissions[cxOnePermission] = true
+			}
+		}
+		cxOneRoles := append([]string{}, t.table.Roles[role.Name]...)
+		out.Roles = append(out.Roles, RoleTranslation{
+			ID:                  role.ID,
+			Name:                role.Name,
+			IsSystemRole:        role.IsSystemRole,
+			CxOneRoles:          cxOneRoles,
+			CxOnePermissions:    getSortedKeys(cxOnePermissions),
+			UnmappedPermissions: getSortedKeys(roleUnmappedPermissions),
+		})
+	}
+	out.UnmappedPermissions = getSortedKeys(unmappedPermissions)
+	return out
+}
+
+func getSortedKeys(values map[string]bool) []string {
+	out := make([]string, 0, len(values))
+	for value := range values {
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
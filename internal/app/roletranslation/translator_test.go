@@ -0,0 +1,95 @@
+package roletranslation
+
+import (
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("uses built-in table without file", func(t *testing.T) {
+		translator, err := Load("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, GetDefaultTable(), translator.table)
+	})
+	t.Run("overrides built-in table with file", func(t *testing.T) {
+		translator, err := Load("../../../test/data/roletranslation/override.json")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ast-risk-manager"}, translator.table.Roles["Security Champion"])
+		assert.Equal(t, []string{"ast-admin"}, translator.table.Roles["SAST Admin"])
+		assert.Equal(t, []string{"view-results"}, translator.table.Permissions["use-odata"])
+		assert.Empty(t, translator.table.Permissions["manage-presets"])
+		assert.Equal(t, []string{"manage-users"}, translator.table.Permissions["manage-users"])
+	})
+	t.Run("fails if file doesn't exist", func(t *testing.T) {
+		_, err := Load("does_not_exist.json")
+
+		assert.ErrorContains(t, err, "could not read role translation file")
+	})
+}
+
+func TestParseRoles(t *testing.T) {
+	t.Run("parses roles", func(t *testing.T) {
+		data := `[{"id": 1, "isSystemRole": true, "name": "SAST Admin", "description": "admin", "permissionIds": [1, 2]}]`
+
+		result, err := ParseRoles([]byte(data))
+
+		assert.NoError(t, err)
+		expected := []*rest.Role{{ID: 1, IsSystemRole: true, Name: "SAST Admin", Description: "admin", PermissionIDs: []int{1, 2}}}
+		assert.Equal(t, expected, result)
+	})
+	t.Run("fails if roles are not valid", func(t *testing.T) {
+		_, err := ParseRoles([]byte(`{"id": 1}`))
+
+		assert.ErrorContains(t, err, "could not parse roles")
+	})
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	roles := []*rest.Role{
+		{ID: 1, IsSystemRole: true, Name: "SAST Scanner", PermissionIDs: []int{1, 2, 3}},
+		{ID: 2, Name: "Custom", PermissionIDs: []int{2, 4, 99}},
+		{ID: 3, Name: "Empty"},
+	}
+	permissions := []*rest.Permission{
+		{ID: 1, ServiceProviderID: 2, Name: "save-sast-scan"},
+		{ID: 2, ServiceProviderID: 2, Name: "view-results"},
+		{ID: 3, ServiceProviderID: 2, Name: "use-odata"},
+		{ID: 4, ServiceProviderID: 2, Name: "manage-custom-fields"},
+	}
+	translator := NewTranslator(GetDefaultTable())
+
+	result := translator.Translate(roles, permissions)
+
+	expected := &Translation{
+		Roles: []RoleTranslation{
+			{
+				ID:                  1,
+				Name:                "SAST Scanner",
+				IsSystemRole:        true,
+				CxOneRoles:          []string{"ast-scanner"},
+				CxOnePermissions:    []string{"create-scan", "view-projects", "view-results", "view-scans"},
+				UnmappedPermissions: []string{"use-odata"},
+			},
+			{
+				ID:                  2,
+				Name:                "Custom",
+				CxOneRoles:          []string{},
+				CxOnePermissions:    []string{"view-projects", "view-results", "view-scans"},
+				UnmappedPermissions: []string{"99", "manage-custom-fields"},
+			},
+			{
+				ID:                  3,
+				Name:                "Empty",
+				CxOneRoles:          []string{},
+				CxOnePermissions:    []string{},
+				UnmappedPermissions: []string{},
+			},
+		},
+		UnmappedPermissions: []string{"99", "manage-custom-fields", "use-odata"},
+	}
+	assert.Equal(t, expected, result)
+}
@@ -0,0 +1,108 @@
+package scanhistory
+
+import (
+	"sort"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+)
+
+const (
+	// FullScanType is the type of scans of all the source code
+	FullScanType = "full"
+	// IncrementalScanType is the type of scans of the source code changed since the previous scan
+	IncrementalScanType = "incremental"
+)
+
+type (
+	// ProjectScans is the scan history of a project
+	ProjectScans struct {
+		ProjectID int     `json:"projectId"`
+		Scans     []*Scan `json:"scans"`
+	}
+
+	Scan struct {
+		ID            int          `json:"id"`
+		Type          string       `json:"type"`
+		Origin        string       `json:"origin"`
+		RequestedOn   string       `json:"requestedOn"`
+		StartedOn     string       `json:"startedOn"`
+		FinishedOn    string       `json:"finishedOn"`
+		LOC           int          `json:"loc"`
+		FilesScanned  int          `json:"filesScanned"`
+		Languages     []string     `json:"languages"`
+		EngineVersion string       `json:"engineVersion"`
+		Preset        string       `json:"preset"`
+		Comment       string       `json:"comment"`
+		Results       ResultCounts `json:"results"`
+	}
+
+	// ResultCounts are the results of a scan by severity
+	ResultCounts struct {
+		High   int `json:"high"`
+		Medium int `json:"medium"`
+		Low    int `json:"low"`
+		Info   int `json:"info"`
+		Total  int `json:"total"`
+	}
+)
+
+// GroupByProject returns the scan history of each project, ordered by project id and scan id
+func GroupByProject(scans []*rest.Scan) []*ProjectScans {
+	byProject := map[int]*ProjectScans{}
+	for _, scan := range scans {
+		project, exists := byProject[scan.ProjectID]
+		if !exists {
+			project = &ProjectScans{ProjectID: scan.ProjectID, Scans: []*Scan{}}
+			byProject[scan.ProjectID] = project
+		}
+		project.Scans = append(project.Scans, newScan(scan))
+	}
+	out := make([]*ProjectScans, 0, len(byProject))
+	for _, project := range byProject {
+		sort.SliceStable(project.Scans, func(i, j int) bool {
+			return project.Scans[i].ID < project.Scans[j].ID
+		})
+		out = append(out, project)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ProjectID < out[j].ProjectID
+	})
+	return out
+}
+
+func newScan(scan *rest.Scan) *Scan {
+	scanType := FullScanType
+	if scan.IsIncremental {
+		scanType = IncrementalScanType
+	}
+	languages := make([]string, 0, len(scan.ScannedLanguages))
+	for _, language := range scan.ScannedLanguages {
+		languages = append(languages, language.LanguageName)
+	}
+	sort.Strings(languages)
+	preset := ""
+	if scan.Preset != nil {
+		preset = scan.Preset.Name
+	}
+	return &Scan{
+		ID:            scan.ID,
+		Type:          scanType,
+		Origin:        scan.Origin,
+		RequestedOn:   scan.ScanRequestedOn,
+		StartedOn:     scan.EngineStartedOn,
+		FinishedOn:    scan.ScanCompletedOn,
+		LOC:           scan.LOC,
+		FilesScanned:  scan.FileCount,
+		Languages:     languages,
+		EngineVersion: scan.ProductVersion,
+		Preset:        preset,
+		Comment:       scan.Comment,
+		Results: ResultCounts{
+			High:   scan.High,
+			Medium: scan.Medium,
+			Low:    scan.Low,
+			Info:   scan.Info,
+			Total:  scan.TotalVulnerabilities,
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package scanhistory
+
+import (
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByProject(t *testing.T) {
+	scans := []*rest.Scan{
+		{
+			ID: 1002, ProjectID: 2, IsIncremental: true, Origin: "Jenkins", ScanRequestedOn: "2022-04-21T19:20:00Z",
+			EngineStartedOn: "2022-04-21T19:21:00Z", ScanCompletedOn: "2022-04-21T19:26:00Z", LOC: 1200, FileCount: 40,
+			ProductVersion: "9.4.0.1001", Comment: "nightly", High: 1, Medium: 2, Low: 3, Info: 4, TotalVulnerabilities: 10,
+			Preset:           &rest.ScanPreset{Name: "Checkmarx Default"},
+			ScannedLanguages: []rest.ScannedLanguage{{LanguageName: "Java"}, {LanguageName: "Common"}},
+		},
+		{ID: 1001, ProjectID: 2},
+		{ID: 1000, ProjectID: 1},
+	}
+
+	result := GroupByProject(scans)
+
+	expected := []*ProjectScans{
+		{ProjectID: 1, Scans: []*Scan{{ID: 1000, Type: FullScanType, Languages: []string{}}}},
+		{
+			ProjectID: 2,
+			Scans: []*Scan{
+				{ID: 1001, Type: FullScanType, Languages: []string{}},
+				{
+					ID: 1002, Type: IncrementalScanType, Origin: "Jenkins", RequestedOn: "2022-04-21T19:20:00Z",
+					StartedOn: "2022-04-21T19:21:00Z", FinishedOn: "2022-04-21T19:26:00Z", LOC: 1200, FilesScanned: 40,
+					Languages: []string{"Common", "Java"}, EngineVersion: "9.4.0.1001", Preset: "Checkmarx Default",
+					Comment: "nightly", Results: ResultCounts{High: 1, Medium: 2, Low: 3, Info: 4, Total: 10},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
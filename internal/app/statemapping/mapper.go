@@ -0,0 +1,205 @@
+package statemapping
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSource is the source of predefined states kept as they are
+	DefaultSource = "default"
+	// MappingSource is the source of states translated by a state mapping rule
+	MappingSource = "mapping"
+	// CustomSource is the source of custom states no rule applies to
+	CustomSource = "custom"
+)
+
+type predefinedState struct {
+	Name string
+	ID   int
+}
+
+// predefinedStates are the CxOne predefined states and the ids of their SAST equivalents
+var predefinedStates = []predefinedState{
+	{Name: "TO_VERIFY", ID: 0},
+	{Name: "NOT_EXPLOITABLE", ID: 1},
+	{Name: "CONFIRMED", ID: 2},
+	{Name: "URGENT", ID: 3},
+	{Name: "PROPOSED_NOT_EXPLOITABLE", ID: 4},
+}
+
+// Mapper translates SAST result states to CxOne states
+type Mapper struct {
+	rules []Rule
+}
+
+// NewMapper creates a mapper applying the given rules
+func NewMapper(rules []Rule) *Mapper {
+	return &Mapper{rules: rules}
+}
+
+// Load creates a mapper with the rules in the state mapping file, or without rules if no file is given
+func Load(fileName string) (*Mapper, error) {
+	if fileName == "" {
+		return NewMapper(nil), nil
+	}
+	data, ioErr := os.ReadFile(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read state mapping file")
+	}
+	var mapSource MapSource
+	if jsonErr := json.Unmarshal(data, &mapSource); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse state mapping file")
+	}
+	if validateErr := validate(mapSource.Rules); validateErr != nil {
+		return nil, validateErr
+	}
+	return NewMapper(mapSource.Rules), nil
+}
+
+func validate(rules []Rule) error {
+	ids := map[int]bool{}
+	names := map[string]bool{}
+	for i, rule := range rules {
+		if rule.SastStateID == nil && rule.SastStateName == "" {
+			return errors.Errorf("state mapping rule %d must have a SAST state id or name", i+1)
+		}
+		if strings.TrimSpace(rule.CxOneState) == "" {
+			return errors.Errorf("state mapping rule %d must have a CxOne state", i+1)
+		}
+		if rule.SastStateID != nil {
+			if ids[*rule.SastStateID] {
+				return errors.Errorf("state mapping rule %d repeats SAST state id %d", i+1, *rule.SastStateID)
+			}
+			ids[*rule.SastStateID] = true
+		}
+		if rule.SastStateName != "" {
+			name := strings.ToLower(rule.SastStateName)
+			if names[name] {
+				return errors.Errorf("state mapping rule %d repeats SAST state %s", i+1, rule.SastStateName)
+			}
+			names[name] = true
+		}
+	}
+	return nil
+}
+
+// HasRules returns true if the mapper has any rules
+func (m *Mapper) HasRules() bool {
+	return len(m.rules) > 0
+}
+
+// Translate returns the CxOne state of each SAST state.
+// renumbered has the ids given to custom states that collide with predefined states, custom states keep their id otherwise.
+func (m *Mapper) Translate(states []soap.ResultState, renumbered map[string]string) (*Translation, error) {
+	out := &Translation{States: []StateTranslation{}, Unmapped: []StateTranslation{}}
+	for _, state := range states {
+		translation := StateTranslation{
+			SastStateID:   state.ResultID,
+			SastStateName: state.ResultName,
+			Permission:    state.ResultPermission,
+		}
+		if rule := m.getRule(&state); rule != nil {
+			id, ok := getCxOneStateID(rule.CxOneState, states, renumbered)
+			if !ok {
+				return nil, errors.Errorf("state %s is mapped to %s, which is neither a CxOne predefined state nor a SAST state",
+					state.ResultName, rule.CxOneState)
+			}
+			translation.CxOneState = rule.CxOneState
+			translation.CxOneStateID = id
+			translation.Source = MappingSource
+		} else if predefined, ok := getPredefinedState(state.ResultName); ok && predefined.ID == state.ResultID {
+			translation.CxOneState = predefined.Name
+			translation.CxOneStateID = predefined.ID
+			translation.Source = DefaultSource
+		} else {
+			translation.CxOneState = state.ResultName
+			translation.CxOneStateID = getCustomStateID(state.ResultID, renumbered)
+			translation.Source = CustomSource
+			out.Unmapped = append(out.Unmapped, translation)
+		}
+		out.States = append(out.States, translation)
+	}
+	return out, nil
+}
+
+func (m *Mapper) getRule(state *soap.ResultState) *Rule {
+	for i := range m.rules {
+		if m.rules[i].SastStateID != nil && *m.rules[i].SastStateID == state.ResultID {
+			return &m.rules[i]
+		}
+	}
+	for i := range m.rules {
+		if m.rules[i].SastStateID == nil && strings.EqualFold(m.rules[i].SastStateName, state.ResultName) {
+			return &m.rules[i]
+		}
+	}
+	return nil
+}
+
+// GetStateMapping returns the state ids to replace in the reports
+func (t *Translation) GetStateMapping() map[string]string {
+	stateMapping := map[string]string{}
+	for _, state := range t.States {
+		if state.CxOneStateID != state.SastStateID {
+			stateMapping[strconv.Itoa(state.SastStateID)] = strconv.Itoa(state.CxOneStateID)
+		}
+	}
+	return stateMapping
+}
+
+// FormatUnmapped returns a table of the states no rule applies to, or an empty string if there are none
+func (t *Translation) FormatUnmapped() string {
+	if len(t.Unmapped) == 0 {
+		return ""
+	}
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SAST STATE ID\tSAST STATE\tPERMISSION\tCXONE STATE ID")
+	for _, state := range t.Unmapped {
+		_, _ = fmt.Fprintf(writer, "%d\t%s\t%s\t%d\n", state.SastStateID, state.SastStateName, state.Permission, state.CxOneStateID)
+	}
+	_ = writer.Flush()
+	return buffer.String()
+}
+
+// getCxOneStateID returns the id of a predefined state, or of the custom state with the given name
+func getCxOneStateID(name string, states []soap.ResultState, renumbered map[string]string) (int, bool) {
+	if predefined, ok := getPredefinedState(name); ok {
+		return predefined.ID, true
+	}
+	for _, state := range states {
+		if strings.EqualFold(state.ResultName, name) {
+			return getCustomStateID(state.ResultID, renumbered), true
+		}
+	}
+	return 0, false
+}
+
+func getCustomStateID(id int, renumbered map[string]string) int {
+	if newID, exists := renumbered[strconv.Itoa(id)]; exists {
+		if value, err := strconv.Atoi(newID); err == nil {
+			return value
+		}
+	}
+	return id
+}
+
+// getPredefinedState finds a predefined state by its CxOne name, e.g. NOT_EXPLOITABLE, or its SAST name, e.g. Not Exploitable
+func getPredefinedState(name string) (predefinedState, bool) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	for _, state := range predefinedStates {
+		if state.Name == normalized {
+			return state, true
+		}
+	}
+	return predefinedState{}, false
+}
@@ -0,0 +1,117 @@
+package statemapping
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getStates() []soap.ResultState {
+	return []soap.ResultState{
+		{ResultID: 0, ResultName: "To Verify", ResultPermission: "set-result-state-toverify"},
+		{ResultID: 1, ResultName: "False Positive", ResultPermission: "set-result-state-falsepositive"},
+		{ResultID: 2, ResultName: "Confirmed", ResultPermission: "set-result-state-confirmed"},
+		{ResultID: 5, ResultName: "Accepted Risk", ResultPermission: "set-result-state-acceptedrisk"},
+		{ResultID: 6, ResultName: "Won't Fix", ResultPermission: "set-result-state-wontfix"},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "loads rules",
+			content: `{"rules": [{"sastStateId": 1, "cxOneState": "NOT_EXPLOITABLE"},
+				{"sastStateName": "Accepted Risk", "cxOneState": "Confirmed"}]}`,
+		},
+		{name: "rule without SAST state", content: `{"rules": [{"cxOneState": "URGENT"}]}`,
+			expectedError: "state mapping rule 1 must have a SAST state id or name"},
+		{name: "rule without CxOne state", content: `{"rules": [{"sastStateId": 1}]}`,
+			expectedError: "state mapping rule 1 must have a CxOne state"},
+		{name: "repeated id", content: `{"rules": [{"sastStateId": 1, "cxOneState": "URGENT"}, {"sastStateId": 1, "cxOneState": "URGENT"}]}`,
+			expectedError: "state mapping rule 2 repeats SAST state id 1"},
+		{name: "repeated name", content: `{"rules": [{"sastStateName": "A", "cxOneState": "URGENT"},
+			{"sastStateName": "a", "cxOneState": "URGENT"}]}`,
+			expectedError: "state mapping rule 2 repeats SAST state a"},
+		{name: "invalid json", content: `{`, expectedError: "could not parse state mapping file: unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "state_mapping.json")
+			require.NoError(t, os.WriteFile(fileName, []byte(test.content), 0600))
+
+			result, err := Load(fileName)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, result.HasRules())
+			}
+		})
+	}
+	t.Run("without file", func(t *testing.T) {
+		result, err := Load("")
+
+		assert.NoError(t, err)
+		assert.False(t, result.HasRules())
+	})
+}
+
+func TestMapper_Translate(t *testing.T) {
+	t.Run("applies rules and keeps unmapped custom states", func(t *testing.T) {
+		id := 1
+		mapper := NewMapper([]Rule{
+			{SastStateID: &id, CxOneState: "NOT_EXPLOITABLE"},
+			{SastStateName: "accepted risk", CxOneState: "Won't Fix"},
+		})
+
+		result, err := mapper.Translate(getStates(), map[string]string{"1": "7"})
+
+		assert.NoError(t, err)
+		expected := []StateTranslation{
+			{SastStateID: 0, SastStateName: "To Verify", Permission: "set-result-state-toverify", CxOneState: "TO_VERIFY",
+				CxOneStateID: 0, Source: DefaultSource},
+			{SastStateID: 1, SastStateName: "False Positive", Permission: "set-result-state-falsepositive", CxOneState: "NOT_EXPLOITABLE",
+				CxOneStateID: 1, Source: MappingSource},
+			{SastStateID: 2, SastStateName: "Confirmed", Permission: "set-result-state-confirmed", CxOneState: "CONFIRMED",
+				CxOneStateID: 2, Source: DefaultSource},
+			{SastStateID: 5, SastStateName: "Accepted Risk", Permission: "set-result-state-acceptedrisk", CxOneState: "Won't Fix",
+				CxOneStateID: 6, Source: MappingSource},
+			{SastStateID: 6, SastStateName: "Won't Fix", Permission: "set-result-state-wontfix", CxOneState: "Won't Fix",
+				CxOneStateID: 6, Source: CustomSource},
+		}
+		assert.Equal(t, expected, result.States)
+		assert.Equal(t, []StateTranslation{expected[4]}, result.Unmapped)
+		assert.Equal(t, map[string]string{"5": "6"}, result.GetStateMapping())
+		assert.Equal(t, "SAST STATE ID  SAST STATE  PERMISSION                CXONE STATE ID\n"+
+			"6              Won't Fix   set-result-state-wontfix  6\n", result.FormatUnmapped())
+	})
+	t.Run("renumbers unmapped custom states colliding with predefined states", func(t *testing.T) {
+		result, err := NewMapper(nil).Translate(getStates(), map[string]string{"1": "7"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"1": "7"}, result.GetStateMapping())
+		assert.Len(t, result.Unmapped, 3)
+	})
+	t.Run("fails if the CxOne state is unknown", func(t *testing.T) {
+		mapper := NewMapper([]Rule{{SastStateName: "Accepted Risk", CxOneState: "Ignored"}})
+
+		_, err := mapper.Translate(getStates(), nil)
+
+		assert.EqualError(t, err, "state Accepted Risk is mapped to Ignored, which is neither a CxOne predefined state nor a SAST state")
+	})
+	t.Run("formats nothing without unmapped states", func(t *testing.T) {
+		result, err := NewMapper(nil).Translate(getStates()[:1], nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.FormatUnmapped())
+	})
+}
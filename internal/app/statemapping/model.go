@@ -0,0 +1,32 @@
+package statemapping
+
+type (
+	// Rule maps a SAST state, identified by id or name, to a CxOne predefined or custom state
+	Rule struct {
+		SastStateID   *int   `json:"sastStateId,omitempty"`
+		SastStateName string `json:"sastStateName,omitempty"`
+		CxOneState    string `json:"cxOneState"`
+	}
+
+	MapSource struct {
+		Rules []Rule `json:"rules"`
+	}
+
+	// StateTranslation is the CxOne state a SAST state is exported as
+	StateTranslation struct {
+		SastStateID   int    `json:"sastStateId"`
+		SastStateName string `json:"sastStateName"`
+		Permission    string `json:"permission"`
+		CxOneState    string `json:"cxOneState"`
+		// CxOneStateID is the state id written in the exported reports
+		CxOneStateID int    `json:"cxOneStateId"`
+		Source       string `json:"source"`
+	}
+
+	// Translation is what's exported for admins to review before import
+	Translation struct {
+		States []StateTranslation `json:"states"`
+		// Unmapped lists the custom states no rule applies to, they are kept as CxOne custom states
+		Unmapped []StateTranslation `json:"unmapped"`
+	}
+)
@@ -0,0 +1,70 @@
+package systemsettings
+
+import (
+	"encoding/json"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+)
+
+type (
+	// Settings are the system-wide settings needed to rebuild the SAST configuration in CxOne,
+	// sections SAST didn't return are omitted
+	Settings struct {
+		SMTP                    json.RawMessage `json:"smtp,omitempty"`
+		EngineServers           []*EngineServer `json:"engineServers"`
+		DataRetention           json.RawMessage `json:"dataRetention,omitempty"`
+		AuthenticationProviders json.RawMessage `json:"authenticationProviders,omitempty"`
+		ResultSeverityLevels    json.RawMessage `json:"resultSeverityLevels,omitempty"`
+		ScanQueue               json.RawMessage `json:"scanQueue,omitempty"`
+	}
+
+	// EngineServer is an engine server registration and the lines of code range of the scans it takes
+	EngineServer struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		URI      string `json:"uri"`
+		MinLOC   int    `json:"minLoc"`
+		MaxLOC   int    `json:"maxLoc"`
+		MaxScans int    `json:"maxScans"`
+	}
+
+	// Sources are the system settings returned by SAST, nil for the ones it couldn't return
+	Sources struct {
+		SMTP                    []byte
+		EngineServers           []*rest.EngineServer
+		DataRetention           []byte
+		AuthenticationProviders []byte
+		ResultSeverityLevels    []byte
+		ScanQueue               []byte
+	}
+)
+
+// New builds the system settings from what SAST returned, responses that aren't JSON are omitted
+func New(sources Sources) *Settings {
+	out := &Settings{
+		SMTP:                    toRaw(sources.SMTP),
+		EngineServers:           make([]*EngineServer, 0, len(sources.EngineServers)),
+		DataRetention:           toRaw(sources.DataRetention),
+		AuthenticationProviders: toRaw(sources.AuthenticationProviders),
+		ResultSeverityLevels:    toRaw(sources.ResultSeverityLevels),
+		ScanQueue:               toRaw(sources.ScanQueue),
+	}
+	for _, engineServer := range sources.EngineServers {
+		out.EngineServers = append(out.EngineServers, &EngineServer{
+			ID:       engineServer.ID,
+			Name:     engineServer.Name,
+			URI:      engineServer.URI,
+			MinLOC:   engineServer.MinLoc,
+			MaxLOC:   engineServer.MaxLoc,
+			MaxScans: engineServer.MaxScans,
+		})
+	}
+	return out
+}
+
+func toRaw(data []byte) json.RawMessage {
+	if len(data) == 0 || !json.Valid(data) {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,49 @@
+package systemsettings
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("builds every section", func(t *testing.T) {
+		sources := Sources{
+			SMTP: []byte(`{"host": "smtp.example.com"}`),
+			EngineServers: []*rest.EngineServer{
+				{ID: 1, Name: "Localhost", URI: "http://localhost/CxSourceAnalyzerEngineWCF/CxEngineWebServices.svc",
+					MinLoc: 0, MaxLoc: 999999999, MaxScans: 3, CxVersion: "9.4.0.1001"},
+			},
+			DataRetention:           []byte(`{"numOfScansToPreserve": 10}`),
+			AuthenticationProviders: []byte(`[{"id": 1, "name": "Application"}]`),
+			ResultSeverityLevels:    []byte(`[{"id": 1, "name": "High"}]`),
+			ScanQueue:               []byte(`{"maxQueuedScans": 100}`),
+		}
+
+		result := New(sources)
+
+		expected := &Settings{
+			SMTP: json.RawMessage(`{"host": "smtp.example.com"}`),
+			EngineServers: []*EngineServer{
+				{ID: 1, Name: "Localhost", URI: "http://localhost/CxSourceAnalyzerEngineWCF/CxEngineWebServices.svc",
+					MinLOC: 0, MaxLOC: 999999999, MaxScans: 3},
+			},
+			DataRetention:           json.RawMessage(`{"numOfScansToPreserve": 10}`),
+			AuthenticationProviders: json.RawMessage(`[{"id": 1, "name": "Application"}]`),
+			ResultSeverityLevels:    json.RawMessage(`[{"id": 1, "name": "High"}]`),
+			ScanQueue:               json.RawMessage(`{"maxQueuedScans": 100}`),
+		}
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("omits missing and invalid sections", func(t *testing.T) {
+		result := New(Sources{SMTP: []byte("<html/>")})
+
+		assert.Equal(t, &Settings{EngineServers: []*EngineServer{}}, result)
+		data, err := json.Marshal(result)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"engineServers":[]}`, string(data))
+	})
+}
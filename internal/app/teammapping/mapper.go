@@ -0,0 +1,158 @@
+package teammapping
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	pathSeparator = "/"
+	flatSeparator = "_"
+)
+
+// Mapper maps SAST team paths to CxOne group paths.
+// Teams no rule applies to keep their path, flattened unless nested teams are kept.
+type Mapper struct {
+	rules       []Rule
+	nestedTeams bool
+	unmapped    map[string]string
+	mutex       sync.Mutex
+}
+
+// NewMapper creates a mapper applying the given rules
+func NewMapper(rules []Rule, nestedTeams bool) *Mapper {
+	normalizedRules := make([]Rule, len(rules))
+	for i, rule := range rules {
+		normalizedRules[i] = Rule{
+			TeamPath:  normalizePath(rule.TeamPath),
+			GroupPath: normalizePath(rule.GroupPath),
+			Merge:     rule.Merge,
+		}
+	}
+	return &Mapper{
+		rules:       normalizedRules,
+		nestedTeams: nestedTeams,
+		unmapped:    map[string]string{},
+	}
+}
+
+// Load creates a mapper with the rules in the team mapping file, or without rules if no file is given
+func Load(fileName string, nestedTeams bool) (*Mapper, error) {
+	if fileName == "" {
+		return NewMapper(nil, nestedTeams), nil
+	}
+	data, ioErr := os.ReadFile(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read team mapping file")
+	}
+	var mapSource MapSource
+	if jsonErr := json.Unmarshal(data, &mapSource); jsonErr != nil {
+		return nil, errors.Wrap(jsonErr, "could not parse team mapping file")
+	}
+	if validateErr := validate(mapSource.Rules); validateErr != nil {
+		return nil, validateErr
+	}
+	return NewMapper(mapSource.Rules, nestedTeams), nil
+}
+
+func validate(rules []Rule) error {
+	teamPaths := map[string]bool{}
+	for i, rule := range rules {
+		teamPath := normalizePath(rule.TeamPath)
+		if teamPath == pathSeparator || normalizePath(rule.GroupPath) == pathSeparator {
+			return errors.Errorf("team mapping rule %d must have a team path and a group path", i+1)
+		}
+		if teamPaths[teamPath] {
+			return errors.Errorf("team mapping rule %d repeats team path %s", i+1, teamPath)
+		}
+		teamPaths[teamPath] = true
+	}
+	return nil
+}
+
+// HasRules returns true if the mapper has any rules
+func (m *Mapper) HasRules() bool {
+	return len(m.rules) > 0
+}
+
+// GetGroupPath returns the group path for a team path, like /CxServer/SP/Company.
+// The rule with the longest matching team path applies. Child teams of a rule's team are
+// mapped to the rule's group, if merged, or otherwise kept below it.
+func (m *Mapper) GetGroupPath(teamPath string) string {
+	teamPath = normalizePath(teamPath)
+	rule, childPath, found := m.findRule(teamPath)
+	if !found {
+		groupPath := m.getDefaultPath(teamPath)
+		if m.HasRules() {
+			m.addUnmapped(teamPath, groupPath)
+		}
+		return groupPath
+	}
+	if childPath == "" || rule.Merge {
+		return rule.GroupPath
+	}
+	if m.nestedTeams {
+		return rule.GroupPath + pathSeparator + childPath
+	}
+	return rule.GroupPath + flatSeparator + strings.ReplaceAll(childPath, pathSeparator, flatSeparator)
+}
+
+// GetUnmappedTeams returns the team paths no rule applied to, and the group path each was given instead
+func (m *Mapper) GetUnmappedTeams() [][2]string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	out := make([][2]string, 0, len(m.unmapped))
+	for teamPath, groupPath := range m.unmapped {
+		out = append(out, [2]string{teamPath, groupPath})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i][0] < out[j][0]
+	})
+	return out
+}
+
+// GenerateUnmappedTeamsCSV returns the rows of the unmapped teams report
+func (m *Mapper) GenerateUnmappedTeamsCSV() [][]string {
+	items := [][]string{{"sast_team_path", "cxone_group_path"}}
+	for _, team := range m.GetUnmappedTeams() {
+		items = append(items, []string{team[0], team[1]})
+	}
+	return items
+}
+
+func (m *Mapper) findRule(teamPath string) (rule Rule, childPath string, found bool) {
+	for _, e := range m.rules {
+		if len(e.TeamPath) <= len(rule.TeamPath) {
+			continue
+		}
+		if teamPath == e.TeamPath {
+			rule, childPath, found = e, "", true
+		} else if strings.HasPrefix(teamPath, e.TeamPath+pathSeparator) {
+			rule, childPath, found = e, strings.TrimPrefix(teamPath, e.TeamPath+pathSeparator), true
+		}
+	}
+	return rule, childPath, found
+}
+
+func (m *Mapper) getDefaultPath(teamPath string) string {
+	if m.nestedTeams {
+		return teamPath
+	}
+	return pathSeparator + strings.ReplaceAll(strings.TrimPrefix(teamPath, pathSeparator), pathSeparator, flatSeparator)
+}
+
+func (m *Mapper) addUnmapped(teamPath, groupPath string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.unmapped[teamPath] = groupPath
+}
+
+// normalizePath returns the path with a single leading separator and no trailing separator
+func normalizePath(path string) string {
+	return pathSeparator + strings.Trim(strings.TrimSpace(path), pathSeparator)
+}
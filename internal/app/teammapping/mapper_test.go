@@ -0,0 +1,112 @@
+package teammapping
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("loads rules from file", func(t *testing.T) {
+		mapper, err := Load("../../../test/data/teammapping/mapping.json", false)
+
+		assert.NoError(t, err)
+		assert.True(t, mapper.HasRules())
+		assert.Equal(t, "/Company/Developers", mapper.GetGroupPath("/CxServer/SP/Company/Users"))
+		assert.Equal(t, "/Company/Archive", mapper.GetGroupPath("/CxServer/SP/Legacy/TeamA"))
+	})
+	t.Run("has no rules without file", func(t *testing.T) {
+		mapper, err := Load("", true)
+
+		assert.NoError(t, err)
+		assert.False(t, mapper.HasRules())
+		assert.Equal(t, "/CxServer/SP", mapper.GetGroupPath("/CxServer/SP"))
+	})
+	t.Run("fails if file doesn't exist", func(t *testing.T) {
+		_, err := Load("does_not_exist.json", false)
+
+		assert.ErrorContains(t, err, "could not read team mapping file")
+	})
+	tests := []struct {
+		Name        string
+		Content     string
+		ExpectedErr string
+	}{
+		{"fails if file is not valid", `{"rules": [`, "could not parse team mapping file: unexpected end of JSON input"},
+		{"fails if team path is missing", `{"rules": [{"groupPath": "/A"}]}`, "team mapping rule 1 must have a team path and a group path"},
+		{"fails if group path is missing", `{"rules": [{"teamPath": "/A"}]}`, "team mapping rule 1 must have a team path and a group path"},
+		{
+			"fails if team path is repeated",
+			`{"rules": [{"teamPath": "/A", "groupPath": "/B"}, {"teamPath": "A/", "groupPath": "/C"}]}`,
+			"team mapping rule 2 repeats team path /A",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "mapping.json")
+			assert.NoError(t, os.WriteFile(fileName, []byte(test.Content), 0600))
+
+			_, err := Load(fileName, false)
+
+			assert.EqualError(t, err, test.ExpectedErr)
+		})
+	}
+}
+
+func TestMapper_GetGroupPath(t *testing.T) {
+	rules := []Rule{
+		{TeamPath: "/CxServer/SP/Company", GroupPath: "/Company"},
+		{TeamPath: "/CxServer/SP/Company/Users", GroupPath: "/Company/Developers/"},
+		{TeamPath: "/CxServer/SP/Legacy", GroupPath: "/Archive", Merge: true},
+	}
+	tests := []struct {
+		TeamPath,
+		ExpectedFlat,
+		ExpectedNested string
+	}{
+		{"/CxServer/SP/Company", "/Company", "/Company"},
+		{"/CxServer/SP/Company/TeamA/TeamB", "/Company_TeamA_TeamB", "/Company/TeamA/TeamB"},
+		{"/CxServer/SP/Company/Users", "/Company/Developers", "/Company/Developers"},
+		{"/CxServer/SP/Company/Users/TeamA", "/Company/Developers_TeamA", "/Company/Developers/TeamA"},
+		{"/CxServer/SP/Legacy", "/Archive", "/Archive"},
+		{"/CxServer/SP/Legacy/TeamA", "/Archive", "/Archive"},
+		{"/CxServer/SP/CompanyB", "/CxServer_SP_CompanyB", "/CxServer/SP/CompanyB"},
+		{"CxServer/SP", "/CxServer_SP", "/CxServer/SP"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("#%d", i+1), func(t *testing.T) {
+			assert.Equal(t, test.ExpectedFlat, NewMapper(rules, false).GetGroupPath(test.TeamPath))
+			assert.Equal(t, test.ExpectedNested, NewMapper(rules, true).GetGroupPath(test.TeamPath))
+		})
+	}
+}
+
+func TestMapper_GetUnmappedTeams(t *testing.T) {
+	t.Run("reports teams without rules", func(t *testing.T) {
+		mapper := NewMapper([]Rule{{TeamPath: "/CxServer/SP/Company", GroupPath: "/Company"}}, false)
+
+		mapper.GetGroupPath("/CxServer/SP/TeamB")
+		mapper.GetGroupPath("/CxServer/SP/Company/TeamA")
+		mapper.GetGroupPath("/CxServer")
+		mapper.GetGroupPath("/CxServer/SP/TeamB")
+
+		expected := [][2]string{{"/CxServer", "/CxServer"}, {"/CxServer/SP/TeamB", "/CxServer_SP_TeamB"}}
+		assert.Equal(t, expected, mapper.GetUnmappedTeams())
+		expectedCSV := [][]string{
+			{"sast_team_path", "cxone_group_path"},
+			{"/CxServer", "/CxServer"},
+			{"/CxServer/SP/TeamB", "/CxServer_SP_TeamB"},
+		}
+		assert.Equal(t, expectedCSV, mapper.GenerateUnmappedTeamsCSV())
+	})
+	t.Run("reports nothing without rules", func(t *testing.T) {
+		mapper := NewMapper(nil, false)
+
+		mapper.GetGroupPath("/CxServer/SP/TeamB")
+
+		assert.Empty(t, mapper.GetUnmappedTeams())
+	})
+}
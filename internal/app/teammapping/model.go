@@ -0,0 +1,15 @@
+package teammapping
+
+type (
+	// Rule maps a SAST team path, and its child teams, to a CxOne group path
+	Rule struct {
+		TeamPath  string `json:"teamPath"`
+		GroupPath string `json:"groupPath"`
+		// Merge maps child teams to the group itself instead of keeping them as child groups
+		Merge bool `json:"merge"`
+	}
+
+	MapSource struct {
+		Rules []Rule `json:"rules"`
+	}
+)
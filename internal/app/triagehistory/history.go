@@ -0,0 +1,124 @@
+package triagehistory
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+)
+
+type (
+	// ProjectHistory is the triage history of the triaged results of the last scan of a project
+	ProjectHistory struct {
+		ProjectID int              `json:"projectId"`
+		ScanID    int              `json:"scanId"`
+		Results   []*ResultHistory `json:"results"`
+	}
+
+	// ResultHistory is the triage history of a result, identified by its result and path ids
+	ResultHistory struct {
+		ResultID     int      `json:"resultId"`
+		PathID       int      `json:"pathId"`
+		SimilarityID string   `json:"similarityId"`
+		QueryID      string   `json:"queryId"`
+		Entries      []*Entry `json:"entries"`
+	}
+
+	// Entry is a triage change, in the order it was made; old and new values are only set for what the entry changed
+	Entry struct {
+		Author      string  `json:"author"`
+		Timestamp   string  `json:"timestamp"`
+		OldState    *string `json:"oldState,omitempty"`
+		NewState    *string `json:"newState,omitempty"`
+		OldSeverity *string `json:"oldSeverity,omitempty"`
+		NewSeverity *string `json:"newSeverity,omitempty"`
+		OldAssignee *string `json:"oldAssignee,omitempty"`
+		NewAssignee *string `json:"newAssignee,omitempty"`
+		Comment     *string `json:"comment,omitempty"`
+	}
+
+	// Options are the translations applied to the history, so it refers to the same states and users as the reports
+	Options struct {
+		// StateMapping has the state ids to replace
+		StateMapping map[string]string
+		// GetUserName returns the username to refer to a user by, it's not called if nil
+		GetUserName func(userName string) string
+	}
+)
+
+// New builds the triage history of a project from the history of its triaged results
+func New(projectID, scanID int, results []*rest.ResultWithHistory, options Options) *ProjectHistory {
+	out := &ProjectHistory{ProjectID: projectID, ScanID: scanID, Results: make([]*ResultHistory, 0, len(results))}
+	for _, result := range results {
+		resultHistory := &ResultHistory{
+			ResultID:     result.ID,
+			PathID:       result.PathID,
+			SimilarityID: strconv.FormatInt(result.SimilarityID, 10),
+			QueryID:      strconv.FormatInt(result.QueryID, 10),
+			Entries:      make([]*Entry, 0, len(result.History)),
+		}
+		history := append([]*rest.ResultHistoryEntry{}, result.History...)
+		sort.SliceStable(history, func(i, j int) bool {
+			if history[i].Date != history[j].Date {
+				return history[i].Date < history[j].Date
+			}
+			return history[i].ID < history[j].ID
+		})
+		for _, entry := range history {
+			resultHistory.Entries = append(resultHistory.Entries, newEntry(entry, options))
+		}
+		out.Results = append(out.Results, resultHistory)
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool {
+		if out.Results[i].ResultID != out.Results[j].ResultID {
+			return out.Results[i].ResultID < out.Results[j].ResultID
+		}
+		return out.Results[i].PathID < out.Results[j].PathID
+	})
+	return out
+}
+
+func newEntry(entry *rest.ResultHistoryEntry, options Options) *Entry {
+	author := entry.UserName
+	if options.GetUserName != nil && author != "" {
+		author = options.GetUserName(author)
+	}
+	return &Entry{
+		Author:      author,
+		Timestamp:   entry.Date,
+		OldState:    mapState(entry.OldStateID, options.StateMapping),
+		NewState:    mapState(entry.NewStateID, options.StateMapping),
+		OldSeverity: formatID(entry.OldSeverityID),
+		NewSeverity: formatID(entry.NewSeverityID),
+		OldAssignee: mapUser(entry.OldAssignedTo, options),
+		NewAssignee: mapUser(entry.NewAssignedTo, options),
+		Comment:     entry.Comment,
+	}
+}
+
+func mapState(id *int, stateMapping map[string]string) *string {
+	state := formatID(id)
+	if state == nil {
+		return nil
+	}
+	if newState, exists := stateMapping[*state]; exists {
+		return &newState
+	}
+	return state
+}
+
+func mapUser(userName *string, options Options) *string {
+	if userName == nil || *userName == "" || options.GetUserName == nil {
+		return userName
+	}
+	mapped := options.GetUserName(*userName)
+	return &mapped
+}
+
+func formatID(id *int) *string {
+	if id == nil {
+		return nil
+	}
+	value := strconv.Itoa(*id)
+	return &value
+}
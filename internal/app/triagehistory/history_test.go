@@ -0,0 +1,56 @@
+package triagehistory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func TestNew(t *testing.T) {
+	results := []*rest.ResultWithHistory{
+		{
+			ID: 3, PathID: 7, SimilarityID: -1234, QueryID: 5157,
+			History: []*rest.ResultHistoryEntry{
+				{ID: 12, Date: "2022-04-22T10:00:00Z", UserName: "Bob", OldSeverityID: intPtr(3), NewSeverityID: intPtr(2)},
+				{ID: 11, Date: "2022-04-21T10:00:00Z", UserName: "alice", OldStateID: intPtr(0), NewStateID: intPtr(1),
+					Comment: strPtr("false positive")},
+				{ID: 13, Date: "2022-04-22T10:00:00Z", UserName: "bob", OldAssignedTo: strPtr(""), NewAssignedTo: strPtr("Bob")},
+			},
+		},
+		{ID: 2, PathID: 1, QueryID: 5158},
+	}
+	options := Options{
+		StateMapping: map[string]string{"1": "6"},
+		GetUserName:  strings.ToLower,
+	}
+
+	result := New(10, 1000, results, options)
+
+	expected := &ProjectHistory{
+		ProjectID: 10,
+		ScanID:    1000,
+		Results: []*ResultHistory{
+			{ResultID: 2, PathID: 1, SimilarityID: "0", QueryID: "5158", Entries: []*Entry{}},
+			{
+				ResultID: 3, PathID: 7, SimilarityID: "-1234", QueryID: "5157",
+				Entries: []*Entry{
+					{Author: "alice", Timestamp: "2022-04-21T10:00:00Z", OldState: strPtr("0"), NewState: strPtr("6"),
+						Comment: strPtr("false positive")},
+					{Author: "bob", Timestamp: "2022-04-22T10:00:00Z", OldSeverity: strPtr("3"), NewSeverity: strPtr("2")},
+					{Author: "bob", Timestamp: "2022-04-22T10:00:00Z", OldAssignee: strPtr(""), NewAssignee: strPtr("bob")},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
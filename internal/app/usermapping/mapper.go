@@ -0,0 +1,311 @@
+package usermapping
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	MergedStatus    = "merged"
+	UnmappedStatus  = "unmapped"
+	InactiveStatus  = "inactive"
+	DuplicateStatus = "duplicate"
+
+	// remarkAuthorEnd follows the author name and project name of each remark entry,
+	// e.g. "John Smith WebGoat, [Friday, February 9, 2024 2:12:40 PM]: comment"
+	remarkAuthorEnd = ", ["
+)
+
+type (
+	// ReportEntry describes a user that was merged, or that can't be identified on import
+	ReportEntry struct {
+		UserName                 string
+		AuthenticationProviderID int
+		Email                    string
+		Status                   string
+		TargetUserName           string
+	}
+
+	// Mapper consolidates users that are the same person across authentication providers.
+	// Users are the same person if mapped to the same identity, or if they have the same email
+	// and come from different authentication providers.
+	Mapper struct {
+		identities   map[string]string
+		userNames    map[string]string
+		displayNames map[string]string
+		report       []ReportEntry
+		consolidated bool
+		merged       bool
+	}
+)
+
+// NewMapper creates a mapper with the identities of users, by SAST username
+func NewMapper(identities map[string]string) *Mapper {
+	normalizedIdentities := make(map[string]string, len(identities))
+	for userName, identity := range identities {
+		normalizedIdentities[strings.ToLower(userName)] = identity
+	}
+	return &Mapper{
+		identities:   normalizedIdentities,
+		userNames:    map[string]string{},
+		displayNames: map[string]string{},
+	}
+}
+
+// Load creates a mapper with the identities in the user mapping file, or without identities if no file is given.
+// Each line of the file has a SAST username and the email identifying the user, optionally after a header line.
+func Load(fileName string) (*Mapper, error) {
+	if fileName == "" {
+		return NewMapper(nil), nil
+	}
+	file, ioErr := os.Open(fileName)
+	if ioErr != nil {
+		return nil, errors.Wrap(ioErr, "could not read user mapping file")
+	}
+	defer file.Close()
+	identities, parseErr := parse(file)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return NewMapper(identities), nil
+}
+
+func parse(reader io.Reader) (map[string]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 2
+	csvReader.TrimLeadingSpace = true
+	records, csvErr := csvReader.ReadAll()
+	if csvErr != nil {
+		return nil, errors.Wrap(csvErr, "could not parse user mapping file")
+	}
+	identities := map[string]string{}
+	for i, record := range records {
+		userName := strings.TrimSpace(record[0])
+		identity := strings.TrimSpace(record[1])
+		if i == 0 && strings.EqualFold(userName, "sast_username") {
+			continue
+		}
+		if userName == "" || identity == "" {
+			return nil, errors.Errorf("user mapping line %d must have a username and an identity", i+1)
+		}
+		if _, exists := identities[strings.ToLower(userName)]; exists {
+			return nil, errors.Errorf("user mapping line %d repeats username %s", i+1, userName)
+		}
+		identities[strings.ToLower(userName)] = identity
+	}
+	return identities, nil
+}
+
+// Consolidate merges users that are the same person into one, keeping the first active one.
+// Merged users add their teams and roles to the kept user, and are reported along with users that
+// have no identity and inactive users. Users sharing their email with another user of the same authentication
+// provider aren't merged, as they're different accounts, and are reported as duplicates instead.
+func (m *Mapper) Consolidate(users []*rest.User) []*rest.User {
+	keys, duplicates := m.getIdentityKeys(users)
+	keptUsers := map[string]*rest.User{}
+	for _, e := range users {
+		key := keys[e]
+		if key == "" {
+			continue
+		}
+		if keptUser, exists := keptUsers[key]; !exists || (!keptUser.Active && e.Active) {
+			keptUsers[key] = e
+		}
+	}
+	m.logUnknownIdentities(users)
+
+	displayNameTargets := map[string]string{}
+	out := make([]*rest.User, 0, len(users))
+	for _, e := range users {
+		if identity, exists := m.identities[strings.ToLower(e.UserName)]; exists {
+			e.Email = identity
+		}
+		keptUser, exists := keptUsers[keys[e]]
+		switch {
+		case !exists:
+			m.addReportEntry(e, UnmappedStatus, e.UserName)
+		case keptUser != e:
+			keptUser.TeamIDs = appendUnique(keptUser.TeamIDs, e.TeamIDs...)
+			keptUser.RoleIDs = appendUnique(keptUser.RoleIDs, e.RoleIDs...)
+			m.addReportEntry(e, MergedStatus, keptUser.UserName)
+			m.userNames[strings.ToLower(e.UserName)] = keptUser.UserName
+			m.merged = true
+		case duplicates[e]:
+			log.Warn().Str("username", e.UserName).Int("authenticationProviderId", e.AuthenticationProviderID).
+				Msg("user shares its email with another user of the same authentication provider, it isn't merged")
+			m.addReportEntry(e, DuplicateStatus, e.UserName)
+		case !e.Active:
+			m.addReportEntry(e, InactiveStatus, e.UserName)
+		}
+		if exists && keptUser != e {
+			addDisplayNameTarget(displayNameTargets, getDisplayName(e), getDisplayName(keptUser))
+		} else {
+			addDisplayNameTarget(displayNameTargets, getDisplayName(e), getDisplayName(e))
+		}
+		if !exists || keptUser == e {
+			out = append(out, e)
+		}
+	}
+	for displayName, target := range displayNameTargets {
+		if target != "" && target != displayName {
+			m.displayNames[displayName] = target
+		}
+	}
+	m.consolidated = true
+	return out
+}
+
+// IsConsolidated returns true if users were consolidated, so report references can be mapped
+func (m *Mapper) IsConsolidated() bool {
+	return m.consolidated
+}
+
+// HasMerges returns true if consolidating users merged any of them
+func (m *Mapper) HasMerges() bool {
+	return m.merged
+}
+
+// GetUserName returns the username of the user a username was merged into, or the username itself
+func (m *Mapper) GetUserName(userName string) string {
+	if target, exists := m.userNames[strings.ToLower(userName)]; exists {
+		return target
+	}
+	return userName
+}
+
+// MapRemark replaces the author names of remark entries written by merged users
+func (m *Mapper) MapRemark(remark string) string {
+	if len(m.displayNames) == 0 || remark == "" {
+		return remark
+	}
+	lines := strings.Split(remark, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, remarkAuthorEnd) {
+			continue
+		}
+		author := ""
+		for displayName := range m.displayNames {
+			if strings.HasPrefix(line, displayName+" ") && len(displayName) > len(author) {
+				author = displayName
+			}
+		}
+		if author != "" {
+			lines[i] = m.displayNames[author] + strings.TrimPrefix(line, author)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GenerateReportCSV returns the rows of the user mapping report
+func (m *Mapper) GenerateReportCSV() [][]string {
+	items := [][]string{{"sast_username", "authentication_provider_id", "email", "status", "target_username"}}
+	for _, e := range m.report {
+		items = append(items, []string{
+			e.UserName,
+			strconv.Itoa(e.AuthenticationProviderID),
+			e.Email,
+			e.Status,
+			e.TargetUserName,
+		})
+	}
+	return items
+}
+
+// getIdentityKey returns the key identifying the person a user is, or empty if the user has no identity
+func (m *Mapper) getIdentityKey(user *rest.User) string {
+	if identity, exists := m.identities[strings.ToLower(user.UserName)]; exists {
+		return strings.ToLower(identity)
+	}
+	return strings.ToLower(strings.TrimSpace(user.Email))
+}
+
+// getIdentityKeys returns the identity key of each user, and the users whose email is shared with another user
+// of the same authentication provider. These get a key of their own, so they aren't merged by email.
+func (m *Mapper) getIdentityKeys(users []*rest.User) (keys map[*rest.User]string, duplicates map[*rest.User]bool) {
+	keys = make(map[*rest.User]string, len(users))
+	providerCounts := map[string]map[int]int{}
+	for _, e := range users {
+		key := m.getIdentityKey(e)
+		keys[e] = key
+		if key == "" {
+			continue
+		}
+		if providerCounts[key] == nil {
+			providerCounts[key] = map[int]int{}
+		}
+		providerCounts[key][e.AuthenticationProviderID]++
+	}
+	duplicates = map[*rest.User]bool{}
+	for _, e := range users {
+		if _, mapped := m.identities[strings.ToLower(e.UserName)]; mapped || keys[e] == "" {
+			continue
+		}
+		if providerCounts[keys[e]][e.AuthenticationProviderID] > 1 {
+			duplicates[e] = true
+			keys[e] = keys[e] + "/" + strings.ToLower(e.UserName)
+		}
+	}
+	return keys, duplicates
+}
+
+func (m *Mapper) addReportEntry(user *rest.User, status, targetUserName string) {
+	m.report = append(m.report, ReportEntry{
+		UserName:                 user.UserName,
+		AuthenticationProviderID: user.AuthenticationProviderID,
+		Email:                    user.Email,
+		Status:                   status,
+		TargetUserName:           targetUserName,
+	})
+}
+
+func (m *Mapper) logUnknownIdentities(users []*rest.User) {
+	userNames := map[string]bool{}
+	for _, e := range users {
+		userNames[strings.ToLower(e.UserName)] = true
+	}
+	for userName := range m.identities {
+		if !userNames[userName] {
+			log.Warn().Str("username", userName).Msg("user mapping refers to unknown user")
+		}
+	}
+}
+
+// addDisplayNameTarget records the name a display name is replaced with, or empty if it's ambiguous
+func addDisplayNameTarget(targets map[string]string, displayName, target string) {
+	if displayName == "" {
+		return
+	}
+	if existing, exists := targets[displayName]; exists && existing != target {
+		targets[displayName] = ""
+		return
+	}
+	targets[displayName] = target
+}
+
+// getDisplayName returns the name SAST uses for the user in remarks
+func getDisplayName(user *rest.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+func appendUnique(values []int, newValues ...int) []int {
+	for _, newValue := range newValues {
+		exists := false
+		for _, value := range values {
+			if value == newValue {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			values = append(values, newValue)
+		}
+	}
+	return values
+}
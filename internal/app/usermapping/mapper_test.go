@@ -0,0 +1,141 @@
+package usermapping
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("loads identities from file", func(t *testing.T) {
+		mapper, err := Load("../../../test/data/usermapping/mapping.csv")
+
+		assert.NoError(t, err)
+		expected := map[string]string{"alice": "alice@example.com", `ldap\bob`: "bob@example.com"}
+		assert.Equal(t, expected, mapper.identities)
+	})
+	t.Run("has no identities without file", func(t *testing.T) {
+		mapper, err := Load("")
+
+		assert.NoError(t, err)
+		assert.Empty(t, mapper.identities)
+	})
+	t.Run("fails if file doesn't exist", func(t *testing.T) {
+		_, err := Load("does_not_exist.csv")
+
+		assert.ErrorContains(t, err, "could not read user mapping file")
+	})
+	tests := []struct {
+		Name        string
+		Content     string
+		ExpectedErr string
+	}{
+		{"fails if line has wrong number of fields", "alice\n", "could not parse user mapping file: record on line 1: wrong number of fields"},
+		{"fails if identity is missing", "alice, \n", "user mapping line 1 must have a username and an identity"},
+		{"fails if username is repeated", "alice,a@example.com\nAlice,b@example.com\n", "user mapping line 2 repeats username Alice"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "mapping.csv")
+			assert.NoError(t, os.WriteFile(fileName, []byte(test.Content), 0600))
+
+			_, err := Load(fileName)
+
+			assert.EqualError(t, err, test.ExpectedErr)
+		})
+	}
+}
+
+func TestMapper_Consolidate(t *testing.T) {
+	users := []*rest.User{
+		{ID: 1, UserName: "alice", FirstName: "Alice", LastName: "Smith", Email: "", TeamIDs: []int{1},
+			RoleIDs: []int{1}, AuthenticationProviderID: 1, Active: true},
+		{ID: 2, UserName: `CORP\asmith`, FirstName: "A.", LastName: "Smith", Email: "Alice@example.com", TeamIDs: []int{1, 2},
+			RoleIDs: []int{2}, AuthenticationProviderID: 2, Active: true},
+		{ID: 3, UserName: "bob.old", FirstName: "Bob", LastName: "Jones", Email: "bob@example.com", TeamIDs: []int{3},
+			AuthenticationProviderID: 1, Active: false},
+		{ID: 4, UserName: "bob", FirstName: "Bob", LastName: "Jones", Email: "bob@example.com", TeamIDs: []int{4},
+			AuthenticationProviderID: 3, Active: true},
+		{ID: 5, UserName: "carol", FirstName: "Carol", LastName: "White", AuthenticationProviderID: 1, Active: true},
+		{ID: 6, UserName: "dave", FirstName: "Dave", LastName: "Brown", Email: "dave@example.com",
+			AuthenticationProviderID: 1, Active: false},
+		{ID: 7, UserName: "erin", FirstName: "Erin", LastName: "Green", Email: "ops@example.com",
+			AuthenticationProviderID: 1, Active: true},
+		{ID: 8, UserName: "frank", FirstName: "Frank", LastName: "Black", Email: "OPS@example.com",
+			AuthenticationProviderID: 1, Active: true},
+	}
+	mapper := NewMapper(map[string]string{"Alice": "alice@example.com"})
+
+	result := mapper.Consolidate(users)
+
+	expectedUsers := []*rest.User{
+		{ID: 1, UserName: "alice", FirstName: "Alice", LastName: "Smith", Email: "alice@example.com", TeamIDs: []int{1, 2},
+			RoleIDs: []int{1, 2}, AuthenticationProviderID: 1, Active: true},
+		{ID: 4, UserName: "bob", FirstName: "Bob", LastName: "Jones", Email: "bob@example.com", TeamIDs: []int{4, 3},
+			AuthenticationProviderID: 3, Active: true},
+		{ID: 5, UserName: "carol", FirstName: "Carol", LastName: "White", AuthenticationProviderID: 1, Active: true},
+		{ID: 6, UserName: "dave", FirstName: "Dave", LastName: "Brown", Email: "dave@example.com",
+			AuthenticationProviderID: 1, Active: false},
+		{ID: 7, UserName: "erin", FirstName: "Erin", LastName: "Green", Email: "ops@example.com",
+			AuthenticationProviderID: 1, Active: true},
+		{ID: 8, UserName: "frank", FirstName: "Frank", LastName: "Black", Email: "OPS@example.com",
+			AuthenticationProviderID: 1, Active: true},
+	}
+	assert.Equal(t, expectedUsers, result)
+	expectedReport := [][]string{
+		{"sast_username", "authentication_provider_id", "email", "status", "target_username"},
+		{`CORP\asmith`, "2", "Alice@example.com", "merged", "alice"},
+		{"bob.old", "1", "bob@example.com", "merged", "bob"},
+		{"carol", "1", "", "unmapped", "carol"},
+		{"dave", "1", "dave@example.com", "inactive", "dave"},
+		{"erin", "1", "ops@example.com", "duplicate", "erin"},
+		{"frank", "1", "OPS@example.com", "duplicate", "frank"},
+	}
+	assert.Equal(t, expectedReport, mapper.GenerateReportCSV())
+	assert.True(t, mapper.IsConsolidated())
+	assert.True(t, mapper.HasMerges())
+	assert.Equal(t, "frank", mapper.GetUserName("frank"))
+	assert.Equal(t, "alice", mapper.GetUserName(`corp\ASMITH`))
+	assert.Equal(t, "bob", mapper.GetUserName("bob.old"))
+	assert.Equal(t, "carol", mapper.GetUserName("carol"))
+}
+
+func TestMapper_ConsolidateWithoutMerges(t *testing.T) {
+	users := []*rest.User{
+		{ID: 1, UserName: "alice", Email: "alice@example.com", AuthenticationProviderID: 1, Active: true},
+		{ID: 2, UserName: "bob", Email: "bob@example.com", AuthenticationProviderID: 1, Active: true},
+	}
+	mapper := NewMapper(nil)
+
+	result := mapper.Consolidate(users)
+
+	assert.Len(t, result, 2)
+	assert.True(t, mapper.IsConsolidated())
+	assert.False(t, mapper.HasMerges())
+}
+
+func TestMapper_MapRemark(t *testing.T) {
+	users := []*rest.User{
+		{ID: 1, UserName: "john", FirstName: "John", LastName: "Smith", Email: "john@example.com",
+			AuthenticationProviderID: 1, Active: true},
+		{ID: 2, UserName: "jsmith", FirstName: "J.", LastName: "Smith", Email: "john@example.com",
+			AuthenticationProviderID: 2, Active: true},
+		{ID: 3, UserName: "j", FirstName: "J.", LastName: "", Email: "other@example.com", Active: true},
+	}
+	mapper := NewMapper(nil)
+	mapper.Consolidate(users)
+
+	remark := "J. Smith WebGoat, [Friday, February 9, 2024 2:13:02 PM]: Changed status to Risk Accepted\r\n" +
+		"J. Smith WebGoat, [Friday, February 9, 2024 2:12:40 PM]: J. Smith said\nJ. Smith continued\r\n" +
+		"J. WebGoat, [Friday, February 9, 2024 2:10:00 PM]: Changed status to Confirmed"
+
+	result := mapper.MapRemark(remark)
+
+	expected := "John Smith WebGoat, [Friday, February 9, 2024 2:13:02 PM]: Changed status to Risk Accepted\r\n" +
+		"John Smith WebGoat, [Friday, February 9, 2024 2:12:40 PM]: J. Smith said\nJ. Smith continued\r\n" +
+		"J. WebGoat, [Friday, February 9, 2024 2:10:00 PM]: Changed status to Confirmed"
+	assert.Equal(t, expected, result)
+}
@@ -12,5 +12,8 @@ func GetNumCPU() int {
 	if numCPU > maxCPUs {
 		numCPU = maxCPUs
 	}
+	if numCPU < 1 {
+		numCPU = 1
+	}
 	return numCPU
 }
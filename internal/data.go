@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/fetch"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// dataCacheFolder is the folder of the user cache where downloaded data files are kept
+const dataCacheFolder = "cxsast_exporter"
+
+// DataSource is a data file used by the export, written to the export so it can be traced
+type DataSource struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	SHA256 string `json:"sha256"`
+}
+
+// loadDataBundle returns the data bundle in a file, or the data embedded in the exporter if there's no file
+func loadDataBundle(dataBundleFile, dataBundleKeyFile string) (*databundle.Bundle, error) {
+	if dataBundleFile == "" {
+		return databundle.GetEmbedded(), nil
+	}
+	return databundle.Load(dataBundleFile, dataBundleKeyFile)
+}
+
+// checkOffline returns an error if any of the sources has to be downloaded
+func checkOffline(sources ...string) error {
+	for _, source := range sources {
+		if fetch.IsRemote(source) {
+			return errors.Errorf("offline mode doesn't allow downloading %s", source)
+		}
+	}
+	return nil
+}
+
+// newFetcher creates a fetcher with the retry and proxy settings of the SAST client, caching downloads in the user cache
+func newFetcher(client fetch.Adapter) *fetch.Fetcher {
+	cacheDir, cacheDirErr := os.UserCacheDir()
+	if cacheDirErr != nil {
+		log.Debug().Err(cacheDirErr).Msg("downloads won't be cached")
+		return fetch.NewFetcher(client, "")
+	}
+	return fetch.NewFetcher(client, filepath.Join(cacheDir, dataCacheFolder))
+}
+
+// getFetchOptions returns the checks of a data source, with the public key verifying signatures read from publicKeyFile
+func getFetchOptions(sha256, publicKeyFile string) (fetch.Options, error) {
+	options := fetch.Options{SHA256: sha256}
+	if publicKeyFile != "" {
+		publicKey, keyErr := databundle.ReadPublicKey(publicKeyFile)
+		if keyErr != nil {
+			return options, keyErr
+		}
+		options.PublicKey = publicKey
+	}
+	return options, nil
+}
+
+// fetchDataFile reads a data file from a file path or URL, or from the data bundle if the source is embedded.
+// Embedded files are only checked against the pinned hash, the data bundle itself being signed.
+func fetchDataFile(fetcher *fetch.Fetcher, source, name string, bundle *databundle.Bundle, options fetch.Options,
+) (*fetch.Resource, error) {
+	if source == databundle.EmbeddedSource {
+		resource := fetch.NewResource(bundle.Origin, bundle.GetFile(name))
+		return resource, fetch.CheckSHA256(resource, options.SHA256)
+	}
+	return fetcher.Fetch(source, options)
+}
+
+// getLayerFetchOptions returns the checks of each layered data source, pinned in the same order as the sources
+func getLayerFetchOptions(sha256s []string, sourceCount int, publicKeyFile string) ([]fetch.Options, error) {
+	if len(sha256s) > 0 && len(sha256s) != sourceCount {
+		return nil, errors.Errorf("%d hashes given for %d sources, give one per source or none", len(sha256s), sourceCount)
+	}
+	options := make([]fetch.Options, sourceCount)
+	for i := range options {
+		sha256 := ""
+		if len(sha256s) > 0 {
+			sha256 = sha256s[i]
+		}
+		var err error
+		if options[i], err = getFetchOptions(sha256, publicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+// loadQueryMappings merges the query mappings of the sources in order, each source read from a file path or URL,
+// or from the data bundle if it's embedded. Every source is added to the resolver as a layer too,
+// so both give precedence to the same entries.
+func loadQueryMappings(fetcher *fetch.Fetcher, sources []string, bundle *databundle.Bundle, options []fetch.Options,
+	resolver *queryid.Resolver,
+) (*querymapping.Provider, []*fetch.Resource, error) {
+	provider := querymapping.NewProvider()
+	resources := make([]*fetch.Resource, 0, len(sources))
+	for i, source := range sources {
+		sourceOptions := fetch.Options{}
+		if i < len(options) {
+			sourceOptions = options[i]
+		}
+		resource, fetchErr := fetchDataFile(fetcher, source, databundle.QueryMappingFileName, bundle, sourceOptions)
+		if fetchErr != nil {
+			return nil, nil, fetchErr
+		}
+		mappings, parseErr := querymapping.Parse(resource.Content)
+		if parseErr != nil {
+			return nil, nil, errors.Wrapf(parseErr, "could not parse query mapping %s", resource.Source)
+		}
+		provider.AddLayer(resource.Source, mappings)
+		resolver.AddMappings(resource.Source, mappings)
+		resources = append(resources, resource)
+	}
+	return provider, resources, nil
+}
+
+// addQueryRenaming adds the query renames from a file path or URL, or from the data bundle if the source is embedded
+func addQueryRenaming(resolver *queryid.Resolver, fetcher *fetch.Fetcher, source string, bundle *databundle.Bundle,
+	options fetch.Options,
+) (*fetch.Resource, error) {
+	resource, fetchErr := fetchDataFile(fetcher, source, databundle.QueryRenamingFileName, bundle, options)
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	renames, err := common.ParseRenames(resource.Content, resource.Source)
+	if err != nil {
+		return nil, err
+	}
+	resolver.AddRenames(resource.Source, renames)
+	return resource, nil
+}
+
+// newDataSource describes a data file used by the export
+func newDataSource(name string, resource *fetch.Resource) DataSource {
+	return DataSource{Name: name, Source: resource.Source, SHA256: resource.SHA256}
+}
+
+// BundleData writes a signed data bundle, so exports can run without internet access.
+// A signing key pair is generated if the signing key file doesn't exist.
+func BundleData(args *BundleDataArgs, output io.Writer) error {
+	privateKey, keyErr := databundle.ReadPrivateKey(args.SigningKeyFile)
+	if errors.Is(keyErr, os.ErrNotExist) {
+		publicKeyFile := args.SigningKeyFile + ".pub"
+		privateKey, keyErr = databundle.GenerateKey(args.SigningKeyFile, publicKeyFile)
+		if keyErr == nil {
+			_, _ = fmt.Fprintf(output, "generated signing key %s, verify bundles with public key %s\n", args.SigningKeyFile, publicKeyFile)
+		}
+	}
+	if keyErr != nil {
+		return keyErr
+	}
+
+	fetcher := newFetcher(getRetryHTTPClient())
+	embedded := databundle.GetEmbedded()
+	sources := map[string]string{
+		databundle.QueryMappingFileName:        args.QueryMappingFile,
+		databundle.QueryRenamingFileName:       args.QueryRenamingFile,
+		databundle.EngineConfigMappingFileName: args.EngineConfigMappingFile,
+	}
+	files := make(map[string][]byte, len(sources))
+	for name, source := range sources {
+		resource, fetchErr := fetchDataFile(fetcher, source, name, embedded, fetch.Options{})
+		if fetchErr != nil {
+			return errors.Wrapf(fetchErr, "could not read %s from %s", name, source)
+		}
+		if validateErr := validateDataFile(name, resource.Content); validateErr != nil {
+			return errors.Wrapf(validateErr, "could not parse %s from %s", name, source)
+		}
+		files[name] = resource.Content
+	}
+
+	bundle := databundle.New(files, sources, args.RunTime)
+	if signErr := bundle.Sign(privateKey); signErr != nil {
+		return errors.Wrap(signErr, "could not sign data bundle")
+	}
+	if writeErr := bundle.Write(args.OutputFile); writeErr != nil {
+		return errors.Wrap(writeErr, "could not write data bundle")
+	}
+	_, writeErr := fmt.Fprintf(output, "data bundle %s written to %s\n", bundle.Version, args.OutputFile)
+	return writeErr
+}
+
+func validateDataFile(name string, content []byte) error {
+	switch name {
+	case databundle.QueryMappingFileName:
+		_, err := querymapping.Parse(content)
+		return err
+	case databundle.QueryRenamingFileName:
+		_, err := common.ParseRenames(content, name)
+		return err
+	default:
+		var value interface{}
+		return json.Unmarshal(content, &value)
+	}
+}
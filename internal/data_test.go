@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/checkmarxDev/ast-sast-export/data"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/fetch"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOffline(t *testing.T) {
+	assert.NoError(t, checkOffline(databundle.EmbeddedSource, "mapping.json"))
+	assert.EqualError(t, checkOffline("mapping.json", "https://example.com/renames.json"),
+		"offline mode doesn't allow downloading https://example.com/renames.json")
+}
+
+func TestLoadQueryMappings(t *testing.T) {
+	t.Run("loads embedded query mapping", func(t *testing.T) {
+		bundle := databundle.GetEmbedded()
+		resolver := queryid.NewResolver()
+
+		provider, resources, err := loadQueryMappings(fetch.NewFetcher(nil, ""), []string{databundle.EmbeddedSource}, bundle,
+			nil, resolver)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "11", provider.GetMapping()[0].SastID)
+		assert.Equal(t, bundle.Origin, provider.GetMapping()[0].Origin)
+		assert.Len(t, resources, 1)
+		assert.Equal(t, bundle.Origin, resources[0].Source)
+		assert.True(t, resolver.IsMapped("11"))
+	})
+	t.Run("merges query mapping layers", func(t *testing.T) {
+		customFile := filepath.Join(t.TempDir(), "custom.json")
+		require.NoError(t, os.WriteFile(customFile, []byte(`{"mappings": [{"astId": "1", "sastId": "11"}]}`), 0600))
+		resolver := queryid.NewResolver()
+
+		provider, resources, err := loadQueryMappings(fetch.NewFetcher(nil, ""), []string{"../data/mapping.json", customFile},
+			databundle.GetEmbedded(), nil, resolver)
+
+		assert.NoError(t, err)
+		assert.Equal(t, querymapping.QueryMap{AstID: "1", SastID: "11", Origin: customFile}, provider.GetMapping()[0])
+		assert.Equal(t, "../data/mapping.json", provider.GetMapping()[1].Origin)
+		assert.Equal(t, []querymapping.Conflict{{
+			SastID:           "11",
+			AstID:            "1",
+			Origin:           customFile,
+			OverriddenAstID:  "5667386434418802377",
+			OverriddenOrigin: "../data/mapping.json",
+		}}, provider.GetConflicts())
+		assert.Len(t, resources, 2)
+		assert.Equal(t, fetch.NewResource("", data.QueryMapping).SHA256, resources[0].SHA256)
+		resolution, resolveErr := resolver.Resolve(queryid.Query{SastQueryID: "11"})
+		assert.NoError(t, resolveErr)
+		assert.Equal(t, "1", resolution.AstQueryID)
+	})
+	t.Run("fails if embedded query mapping doesn't match pinned hash", func(t *testing.T) {
+		_, _, err := loadQueryMappings(fetch.NewFetcher(nil, ""), []string{databundle.EmbeddedSource}, databundle.GetEmbedded(),
+			[]fetch.Options{{SHA256: "0000"}}, queryid.NewResolver())
+
+		assert.ErrorContains(t, err, "expected 0000")
+	})
+	t.Run("fails if query mapping is not valid", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "mapping.json")
+		require.NoError(t, os.WriteFile(fileName, []byte("<html>Proxy error</html>"), 0600))
+
+		_, _, err := loadQueryMappings(fetch.NewFetcher(nil, ""), []string{fileName}, databundle.GetEmbedded(), nil,
+			queryid.NewResolver())
+
+		assert.ErrorContains(t, err, "could not parse query mapping "+fileName)
+	})
+}
+
+func TestGetLayerFetchOptions(t *testing.T) {
+	t.Run("returns options without hashes", func(t *testing.T) {
+		result, err := getLayerFetchOptions(nil, 2, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []fetch.Options{{}, {}}, result)
+	})
+	t.Run("returns options with hashes", func(t *testing.T) {
+		result, err := getLayerFetchOptions([]string{"a", "b"}, 2, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []fetch.Options{{SHA256: "a"}, {SHA256: "b"}}, result)
+	})
+	t.Run("fails if hashes don't match sources", func(t *testing.T) {
+		_, err := getLayerFetchOptions([]string{"a"}, 2, "")
+
+		assert.EqualError(t, err, "1 hashes given for 2 sources, give one per source or none")
+	})
+}
+
+func TestAddQueryRenaming(t *testing.T) {
+	query := queryid.Query{
+		Language:    "Java",
+		Group:       "Java_Low_Visibility",
+		Name:        "Trust_Boundary_Violation_in_Session_Variables",
+		SastQueryID: "646",
+	}
+	t.Run("adds embedded query renaming", func(t *testing.T) {
+		bundle := databundle.GetEmbedded()
+		resolver := queryid.NewResolver()
+
+		resource, err := addQueryRenaming(resolver, fetch.NewFetcher(nil, ""), databundle.EmbeddedSource, bundle, fetch.Options{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, bundle.Origin, resource.Source)
+		result, resolveErr := resolver.Resolve(query)
+		assert.NoError(t, resolveErr)
+		assert.Equal(t, queryid.RenameSource, result.Source)
+		assert.Equal(t, bundle.Origin, result.Origin)
+	})
+	t.Run("adds query renaming file", func(t *testing.T) {
+		resolver := queryid.NewResolver()
+
+		_, err := addQueryRenaming(resolver, fetch.NewFetcher(nil, ""), "../data/renames.json", databundle.GetEmbedded(),
+			fetch.Options{})
+
+		assert.NoError(t, err)
+		result, resolveErr := resolver.Resolve(query)
+		assert.NoError(t, resolveErr)
+		assert.Equal(t, "../data/renames.json", result.Origin)
+	})
+	t.Run("fails if embedded query renaming is not valid", func(t *testing.T) {
+		bundle := databundle.New(map[string][]byte{databundle.QueryRenamingFileName: []byte(`{`)}, nil, time.Now())
+
+		_, err := addQueryRenaming(queryid.NewResolver(), fetch.NewFetcher(nil, ""), databundle.EmbeddedSource, bundle,
+			fetch.Options{})
+
+		assert.ErrorContains(t, err, "failed to parse rename JSON data")
+	})
+}
+
+func TestGetFetchOptions(t *testing.T) {
+	t.Run("returns options without signature key", func(t *testing.T) {
+		result, err := getFetchOptions("abc", "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, fetch.Options{SHA256: "abc"}, result)
+	})
+	t.Run("returns options with signature key", func(t *testing.T) {
+		dir := t.TempDir()
+		privateKey, keyErr := databundle.GenerateKey(filepath.Join(dir, "key.pem"), filepath.Join(dir, "key.pub"))
+		require.NoError(t, keyErr)
+
+		result, err := getFetchOptions("", filepath.Join(dir, "key.pub"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, privateKey.Public(), result.PublicKey)
+	})
+	t.Run("fails if signature key can't be read", func(t *testing.T) {
+		_, err := getFetchOptions("", "does_not_exist.pub")
+
+		assert.ErrorContains(t, err, "could not read public key file")
+	})
+}
+
+func TestBundleData(t *testing.T) {
+	t.Run("writes signed data bundle and generates key", func(t *testing.T) {
+		dir := t.TempDir()
+		args := &BundleDataArgs{
+			QueryMappingFile:        databundle.EmbeddedSource,
+			QueryRenamingFile:       "../data/renames.json",
+			EngineConfigMappingFile: databundle.EmbeddedSource,
+			SigningKeyFile:          filepath.Join(dir, "key.pem"),
+			OutputFile:              filepath.Join(dir, "bundle.json"),
+			RunTime:                 time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+		var output bytes.Buffer
+
+		err := BundleData(args, &output)
+
+		assert.NoError(t, err)
+		assert.Contains(t, output.String(), "generated signing key "+args.SigningKeyFile)
+		bundle, loadErr := loadDataBundle(args.OutputFile, args.SigningKeyFile+".pub")
+		require.NoError(t, loadErr)
+		assert.Equal(t, data.QueryMapping, bundle.GetFile(databundle.QueryMappingFileName))
+		assert.Equal(t, "../data/renames.json", bundle.Sources[databundle.QueryRenamingFileName])
+		assert.Equal(t, args.RunTime, bundle.CreatedAt)
+		assert.Equal(t, databundle.GetEmbedded().Version, bundle.Version)
+	})
+	t.Run("uses existing signing key", func(t *testing.T) {
+		dir := t.TempDir()
+		keyFile := filepath.Join(dir, "key.pem")
+		_, keyErr := databundle.GenerateKey(keyFile, filepath.Join(dir, "other.pub"))
+		require.NoError(t, keyErr)
+		args := &BundleDataArgs{
+			QueryMappingFile:        databundle.EmbeddedSource,
+			QueryRenamingFile:       databundle.EmbeddedSource,
+			EngineConfigMappingFile: databundle.EmbeddedSource,
+			SigningKeyFile:          keyFile,
+			OutputFile:              filepath.Join(dir, "bundle.json"),
+		}
+		var output bytes.Buffer
+
+		err := BundleData(args, &output)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, output.String(), "generated signing key")
+		_, loadErr := loadDataBundle(args.OutputFile, filepath.Join(dir, "other.pub"))
+		assert.NoError(t, loadErr)
+	})
+	t.Run("fails if data file is not valid", func(t *testing.T) {
+		dir := t.TempDir()
+		mappingFile := filepath.Join(dir, "mapping.json")
+		require.NoError(t, os.WriteFile(mappingFile, []byte(`<html>Proxy error</html>`), 0600))
+		args := &BundleDataArgs{
+			QueryMappingFile:        mappingFile,
+			QueryRenamingFile:       databundle.EmbeddedSource,
+			EngineConfigMappingFile: databundle.EmbeddedSource,
+			SigningKeyFile:          filepath.Join(dir, "key.pem"),
+			OutputFile:              filepath.Join(dir, "bundle.json"),
+		}
+
+		err := BundleData(args, &bytes.Buffer{})
+
+		assert.ErrorContains(t, err, "could not parse mapping.json from "+mappingFile)
+		assert.NoFileExists(t, args.OutputFile)
+	})
+}
+
+func TestLoadDataBundle(t *testing.T) {
+	bundle, err := loadDataBundle("", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, databundle.GetEmbedded(), bundle)
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/fetch"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	"github.com/checkmarxDev/ast-sast-export/internal/persistence/queries"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const generatedMappingFileMode = 0600
+
+// GenerateMapping writes a query mapping with the AST query id of every SAST query, overrides included,
+// and compares it with an existing mapping if there's one
+func GenerateMapping(args *GenerateMappingArgs, output io.Writer) error {
+	dataBundle, dataBundleErr := loadDataBundle(args.DataBundleFile, args.DataBundleKeyFile)
+	if dataBundleErr != nil {
+		return errors.Wrap(dataBundleErr, "could not load data bundle")
+	}
+	retryHTTPClient := getRetryHTTPClient()
+	fetcher := newFetcher(retryHTTPClient)
+	resolver := queryid.NewResolver()
+	if _, renameErr := addQueryRenaming(resolver, fetcher, args.QueryRenamingFile, dataBundle, fetch.Options{}); renameErr != nil {
+		return errors.Wrap(renameErr, "could not load query renaming")
+	}
+	var previous []querymapping.QueryMap
+	if args.CompareFile != "" {
+		resource, fetchErr := fetchDataFile(fetcher, args.CompareFile, databundle.QueryMappingFileName, dataBundle, fetch.Options{})
+		if fetchErr != nil {
+			return errors.Wrap(fetchErr, "could not load query mapping to compare with")
+		}
+		var parseErr error
+		if previous, parseErr = querymapping.Parse(resource.Content); parseErr != nil {
+			return errors.Wrapf(parseErr, "could not parse query mapping %s", resource.Source)
+		}
+	}
+
+	client, clientErr := rest.NewSASTClient(args.URL, retryHTTPClient)
+	if clientErr != nil {
+		return errors.Wrap(clientErr, "could not create REST client")
+	}
+	log.Info().Msg("connecting to SAST")
+	if authErr := client.Authenticate(args.Username, args.Password); authErr != nil {
+		return errors.Wrap(authErr, "could not authenticate with SAST API")
+	}
+	queriesRepo := queries.NewRepo(soap.NewClient(args.URL, client.Token, retryHTTPClient))
+
+	mappings, generateErr := generateMapping(queriesRepo, resolver)
+	if generateErr != nil {
+		return generateErr
+	}
+	return writeGeneratedMapping(mappings, previous, args, output)
+}
+
+// generateMapping resolves the AST query id of every query in the SAST query collection,
+// from renames or the hash of its source path
+func generateMapping(queriesRepo interfaces.QueriesRepo, resolver *queryid.Resolver) ([]querymapping.QueryMap, error) {
+	log.Info().Msg("collecting queries")
+	queryCollection, queriesErr := queriesRepo.GetQueriesList()
+	if queriesErr != nil {
+		return nil, errors.Wrap(queriesErr, "could not get queries")
+	}
+	var mappings []querymapping.QueryMap
+	for _, group := range queryCollection.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup {
+		for i := range group.Queries.CxWSQuery {
+			query := group.Queries.CxWSQuery[i]
+			sastQueryID := strconv.Itoa(query.QueryID)
+			resolution, resolveErr := resolver.Resolve(queryid.Query{
+				Language:    group.LanguageName,
+				Group:       group.Name,
+				Name:        query.Name,
+				SastQueryID: sastQueryID,
+			})
+			if resolveErr != nil {
+				log.Warn().Err(resolveErr).Str("package", group.PackageFullName).Msg("query left out of mapping")
+				continue
+			}
+			mappings = append(mappings, querymapping.QueryMap{AstID: resolution.AstQueryID, SastID: sastQueryID})
+		}
+	}
+	querymapping.SortBySastID(mappings)
+	return mappings, nil
+}
+
+func writeGeneratedMapping(mappings, previous []querymapping.QueryMap, args *GenerateMappingArgs, output io.Writer) error {
+	content, marshalErr := json.MarshalIndent(querymapping.MapSource{Mappings: mappings}, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+	if writeErr := os.WriteFile(args.OutputFile, content, generatedMappingFileMode); writeErr != nil {
+		return errors.Wrap(writeErr, "could not write query mapping")
+	}
+	if _, printErr := fmt.Fprintf(output, "%d query mappings written to %s\n", len(mappings), args.OutputFile); printErr != nil {
+		return printErr
+	}
+	if args.CompareFile == "" {
+		return nil
+	}
+	diff := querymapping.GetDiff(previous, mappings)
+	diffContent, diffErr := json.MarshalIndent(diff, "", "  ")
+	if diffErr != nil {
+		return diffErr
+	}
+	_, printErr := fmt.Fprintf(output, "compared with %s: %d added, %d removed, %d changed\n%s\n",
+		args.CompareFile, len(diff.Added), len(diff.Removed), len(diff.Changed), diffContent)
+	return printErr
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/fetch"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
+	mock_interfaces_queries "github.com/checkmarxDev/ast-sast-export/test/mocks/app/queries"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func getQueryCollection() *soap.GetQueryCollectionResponse {
+	var response soap.GetQueryCollectionResponse
+	response.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup = []soap.CxWSQueryGroup{
+		{
+			Name:         "Java_High_Risk",
+			LanguageName: "Java",
+			PackageType:  "Cx",
+			Queries:      soap.Queries{CxWSQuery: []soap.CxWSQuery{{QueryID: 20, Name: "SQL_Injection"}, {QueryID: 3, Name: "XSS"}}},
+		},
+		{
+			Name:         "Java_High_Risk",
+			LanguageName: "Java",
+			PackageType:  "Corporate",
+			Queries:      soap.Queries{CxWSQuery: []soap.CxWSQuery{{QueryID: 100001, Name: "SQL_Injection"}}},
+		},
+		{
+			Name:         "Java_Low_Visibility",
+			LanguageName: "Java",
+			PackageType:  "Cx",
+			Queries:      soap.Queries{CxWSQuery: []soap.CxWSQuery{{QueryID: 646, Name: "Trust_Boundary_Violation_in_Session_Variables"}}},
+		},
+	}
+	return &response
+}
+
+func TestGenerateMapping(t *testing.T) {
+	t.Run("resolves every query", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		queriesRepo := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+		queriesRepo.EXPECT().GetQueriesList().Return(getQueryCollection(), nil)
+		resolver := queryid.NewResolver()
+		_, renameErr := addQueryRenaming(resolver, fetch.NewFetcher(nil, ""), "../data/renames.json", nil, fetch.Options{})
+		require.NoError(t, renameErr)
+
+		result, err := generateMapping(queriesRepo, resolver)
+
+		assert.NoError(t, err)
+		expected := []querymapping.QueryMap{
+			{SastID: "3", AstID: "9227982078537865114"},
+			{SastID: "20", AstID: "14517067005933136034"},
+			{SastID: "646", AstID: "5726913611564465136"},
+			{SastID: "100001", AstID: "14517067005933136034"},
+		}
+		assert.Equal(t, expected, result)
+	})
+	t.Run("fails if queries can't be collected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		queriesRepo := mock_interfaces_queries.NewMockQueriesRepo(ctrl)
+		queriesRepo.EXPECT().GetQueriesList().Return(nil, fmt.Errorf("soap error"))
+
+		_, err := generateMapping(queriesRepo, queryid.NewResolver())
+
+		assert.EqualError(t, err, "could not get queries: soap error")
+	})
+}
+
+func TestWriteGeneratedMapping(t *testing.T) {
+	mappings := []querymapping.QueryMap{{SastID: "1", AstID: "101"}, {SastID: "2", AstID: "202"}}
+	t.Run("writes mapping", func(t *testing.T) {
+		args := &GenerateMappingArgs{OutputFile: filepath.Join(t.TempDir(), "mapping.json")}
+		var output bytes.Buffer
+
+		err := writeGeneratedMapping(mappings, nil, args, &output)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2 query mappings written to "+args.OutputFile+"\n", output.String())
+		content, readErr := os.ReadFile(args.OutputFile)
+		require.NoError(t, readErr)
+		result, parseErr := querymapping.Parse(content)
+		require.NoError(t, parseErr)
+		assert.Equal(t, mappings, result)
+	})
+	t.Run("writes mapping and compares it", func(t *testing.T) {
+		args := &GenerateMappingArgs{OutputFile: filepath.Join(t.TempDir(), "mapping.json"), CompareFile: "embedded"}
+		previous := []querymapping.QueryMap{{SastID: "2", AstID: "102"}, {SastID: "3", AstID: "103"}}
+		var output bytes.Buffer
+
+		err := writeGeneratedMapping(mappings, previous, args, &output)
+
+		assert.NoError(t, err)
+		assert.Contains(t, output.String(), "compared with embedded: 1 added, 1 removed, 1 changed\n")
+		assert.Contains(t, output.String(), `"previousAstId": "102"`)
+	})
+}
@@ -3,11 +3,13 @@ package rest
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
+	"github.com/checkmarxDev/ast-sast-export/data"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rs/zerolog/log"
 )
@@ -16,7 +18,9 @@ const (
 	usersEndpoint         = "/CxRestAPI/auth/Users"
 	teamsEndpoint         = "/CxRestAPI/auth/Teams"
 	rolesEndpoint         = "/CxRestAPI/auth/Roles"
+	permissionsEndpoint   = "/CxRestAPI/auth/Permissions"
 	presetsEndpoint       = "/CxRestAPI/sast/presets"
+	customFieldsEndpoint  = "/CxRestAPI/customFields"
 	projectsODataEndpoint = "/Cxwebinterface/odata/v1/Projects"
 
 	ldapServersEndpoint            = "/CxRestAPI/auth/LDAPServers"
@@ -32,8 +36,18 @@ const (
 	scanSettingsEndpoint           = "/CxRestAPI/sast/scanSettings"
 	engineConfigurationsEndpoint   = "/CxRestAPI/sast/engineConfigurations"
 	projectExcludeSettingsEndpoint = "/CxRestAPI/projects/%d/sourceCode/excludeSettings"
+	projectGitSettingsEndpoint     = "/CxRestAPI/projects/%d/sourceCode/remoteSettings/git"
+	projectScanSettingsEndpoint    = "/CxRestAPI/sast/scanSettings/%d"
+	projectSchedulingEndpoint      = "/CxRestAPI/sast/project/%d/scheduling"
+	projectIssueTrackingEndpoint   = "/CxRestAPI/projects/%d/issueTrackingSettings"
+	projectDataRetentionEndpoint   = "/CxRestAPI/sast/project/%d/dataRetention"
+	projectBranchEndpoint          = "/CxRestAPI/projects/branch/%d"
+	smtpSettingsEndpoint           = "/CxRestAPI/sast/smtpSettings"
+	dataRetentionSettingsEndpoint  = "/CxRestAPI/sast/dataRetention/settings"
+	authProvidersEndpoint          = "/CxRestAPI/auth/AuthenticationProviders"
+	resultSeverityLevelsEndpoint   = "/CxRestAPI/sast/resultSeverityLevels"
+	scanQueueSettingsEndpoint      = "/CxRestAPI/sast/scansQueue/settings"
 
-	engineConfigURL = "https://raw.githubusercontent.com/Checkmarx/sast-to-ast-export/master/data/EngineConfigMapping.json"
 	// ScanReportTypeXML defines SAST report type XML
 	ScanReportTypeXML = "XML"
 )
@@ -43,23 +57,38 @@ type Client interface {
 	PostResponseBody(endpoint string, body io.Reader) ([]byte, error)
 	GetUsers() ([]*User, error)
 	GetRoles() ([]byte, error)
+	GetPermissions() ([]*Permission, error)
 	GetTeams() ([]*Team, error)
 	GetProjects(fromDate, teamName, projectIDs string, offset, limit int) ([]*Project, error)
 	GetPresets() ([]*PresetShort, error)
-	GetLdapServers() ([]byte, error)
+	GetCustomFields() ([]*CustomFieldDefinition, error)
+	GetLdapServers() ([]*LdapServer, error)
 	GetLdapRoleMappings() ([]byte, error)
 	GetLdapTeamMappings() ([]byte, error)
-	GetSamlIdentityProviders() ([]byte, error)
+	GetSamlIdentityProviders() ([]*SamlIdentityProvider, error)
 	GetSamlRoleMappings() ([]byte, error)
 	GetSamlTeamMappings() ([]*SamlTeamMapping, error)
 	GetProjectsWithLastScanID(fromDate, teamName, projectsIDs string, offset, limit int) (*[]ProjectWithLastScanID, error)
 	GetTriagedResultsByScanID(scanID int) (*[]TriagedScanResult, error)
-	CreateScanReport(scanID int, reportType string, retry Retry) ([]byte, error)
+	GetResultsHistoryByScanID(scanID, offset, limit int) ([]*ResultWithHistory, error)
+	GetScans(fromDate, teamName, projectIDs string, offset, limit int) ([]*Scan, error)
+	CreateScanReport(scanID int, reportType string, retry Retry) (io.ReadCloser, error)
 	GetEngineServers() ([]*EngineServer, error)
 	GetEngineConfigurations(projectID int) ([]byte, error)
 	GetEngineConfigurationMappings() ([]byte, error)
 	GetConfigurationsKeys() (*EngineKeysConfigMapping, error)
 	GetProjectExcludeSettings(projectID int) (*ProjectExcludeSettings, error)
+	GetProjectGitSettings(projectID int) (*ProjectGitSettings, error)
+	GetProjectScanSettings(projectID int) (*ProjectScanSettings, error)
+	GetProjectScheduling(projectID int) (*ProjectScheduling, error)
+	GetProjectIssueTrackingSettings(projectID int) (*ProjectIssueTrackingSettings, error)
+	GetProjectDataRetentionSettings(projectID int) (*ProjectDataRetentionSettings, error)
+	GetProjectBranch(projectID int) (*ProjectBranch, error)
+	GetSMTPSettings() ([]byte, error)
+	GetDataRetentionSettings() ([]byte, error)
+	GetAuthenticationProviders() ([]byte, error)
+	GetResultSeverityLevels() ([]byte, error)
+	GetScanQueueSettings() ([]byte, error)
 }
 
 type RetryableHTTPAdapter interface {
@@ -70,6 +99,19 @@ type APIClient struct {
 	BaseURL string
 	Adapter RetryableHTTPAdapter
 	Token   *AccessToken
+	// EngineConfigMapping has the engine configuration keys, the embedded ones are used if it's empty
+	EngineConfigMapping []byte
+}
+
+// StatusError is the error of a request answered with an unexpected status code
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request %s %s failed with status code %d", e.Method, e.URL, e.StatusCode)
 }
 
 type APIError struct {
@@ -214,7 +256,7 @@ func (c *APIClient) doRequest(req *retryablehttp.Request, expectStatusCode int)
 				log.Debug().Err(closeErr).Msg("doRequest")
 			}
 		}()
-		return nil, fmt.Errorf("request %s %s failed with status code %d", req.Method, req.URL.String(), resp.StatusCode)
+		return nil, &StatusError{Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode}
 	}
 	return resp, nil
 }
@@ -244,6 +286,12 @@ func (c *APIClient) GetRoles() ([]byte, error) {
 	return c.getResponseBody(rolesEndpoint)
 }
 
+func (c *APIClient) GetPermissions() ([]*Permission, error) {
+	var permissions []*Permission
+	err := c.unmarshalResponseBody(permissionsEndpoint, &permissions)
+	return permissions, err
+}
+
 func (c *APIClient) GetTeams() ([]*Team, error) {
 	var teams []*Team
 	err := c.unmarshalResponseBody(teamsEndpoint, &teams)
@@ -296,8 +344,17 @@ func (c *APIClient) GetPresets() ([]*PresetShort, error) {
 	return presets, err
 }
 
-func (c *APIClient) GetLdapServers() ([]byte, error) {
-	return c.getResponseBody(ldapServersEndpoint)
+// GetCustomFields returns the custom fields projects can have
+func (c *APIClient) GetCustomFields() ([]*CustomFieldDefinition, error) {
+	var customFields []*CustomFieldDefinition
+	err := c.unmarshalResponseBody(customFieldsEndpoint, &customFields)
+	return customFields, err
+}
+
+func (c *APIClient) GetLdapServers() ([]*LdapServer, error) {
+	var ldapServers []*LdapServer
+	err := c.unmarshalResponseBody(ldapServersEndpoint, &ldapServers)
+	return ldapServers, err
 }
 
 func (c *APIClient) GetLdapRoleMappings() ([]byte, error) {
@@ -308,8 +365,10 @@ func (c *APIClient) GetLdapTeamMappings() ([]byte, error) {
 	return c.getResponseBody(ldapTeamMappingsEndpoint)
 }
 
-func (c *APIClient) GetSamlIdentityProviders() ([]byte, error) {
-	return c.getResponseBody(samlIdentityProvidersEndpoint)
+func (c *APIClient) GetSamlIdentityProviders() ([]*SamlIdentityProvider, error) {
+	var identityProviders []*SamlIdentityProvider
+	err := c.unmarshalResponseBody(samlIdentityProvidersEndpoint, &identityProviders)
+	return identityProviders, err
 }
 
 func (c *APIClient) GetSamlRoleMappings() ([]byte, error) {
@@ -326,8 +385,17 @@ func (c *APIClient) getReportIDStatus(reportID int) ([]byte, error) {
 	return c.getResponseBody(fmt.Sprintf(reportsCheckStatusEndpoint, reportID))
 }
 
-func (c *APIClient) getReportResult(reportID int) ([]byte, error) {
-	return c.getResponseBody(fmt.Sprintf(reportsResultEndpoint, reportID))
+// getReportResult returns the report content as a stream, which the caller must close
+func (c *APIClient) getReportResult(reportID int) (io.ReadCloser, error) {
+	req, err := CreateRequest(http.MethodGet, c.BaseURL+fmt.Sprintf(reportsResultEndpoint, reportID), nil, c.Token)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
 }
 
 func (c *APIClient) postReportID(body io.Reader) ([]byte, error) {
@@ -382,14 +450,71 @@ func (c *APIClient) GetTriagedResultsByScanID(scanID int) (*[]TriagedScanResult,
 	return &response.Value, nil
 }
 
-func (c *APIClient) CreateScanReport(scanID int, reportType string, retry Retry) ([]byte, error) {
+// GetResultsHistoryByScanID returns a page of the triaged results of a scan with their triage history
+func (c *APIClient) GetResultsHistoryByScanID(scanID, offset, limit int) ([]*ResultWithHistory, error) {
+	url := fmt.Sprintf("%s/Cxwebinterface/odata/v1/Scans(%d)/Results", c.BaseURL, scanID)
+	req, requestErr := CreateRequest(http.MethodGet, url, nil, c.Token)
+	if requestErr != nil {
+		return nil, requestErr
+	}
+	q := req.URL.Query()
+	q.Add("$filter", "Comment ne null")
+	q.Add("$select", "Id,PathId,SimilarityId,QueryId")
+	q.Add("$expand", "ResultHistory($select=Id,Date,UserName,Comment,OldStateId,NewStateId,"+
+		"OldSeverityId,NewSeverityId,OldAssignedTo,NewAssignedTo)")
+	q.Add("$orderby", "PathId")
+	q.Add("$skip", fmt.Sprintf("%d", offset))
+	q.Add("$top", fmt.Sprintf("%d", limit))
+	req.URL.RawQuery = q.Encode()
+	body, getErr := c.getResponseBodyFromRequest(req)
+	if getErr != nil {
+		return nil, getErr
+	}
+	var response ODataResultsHistory
+	if unmarshalErr := json.Unmarshal(body, &response); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return response.Value, nil
+}
+
+// GetScans returns a page of the scans of the projects in the project filters, ordered by project and scan
+func (c *APIClient) GetScans(fromDate, teamName, projectIDs string, offset, limit int) ([]*Scan, error) {
+	url := fmt.Sprintf("%s/Cxwebinterface/odata/v1/Scans", c.BaseURL)
+	req, requestErr := CreateRequest(http.MethodGet, url, nil, c.Token)
+	if requestErr != nil {
+		return nil, requestErr
+	}
+	q := req.URL.Query()
+	q.Add("$select", "Id,ProjectId,IsIncremental,Origin,ScanRequestedOn,EngineStartedOn,ScanCompletedOn,LOC,FileCount,"+
+		"ProductVersion,Comment,High,Medium,Low,Info,TotalVulnerabilities")
+	q.Add("$expand", "Preset($select=Name),ScannedLanguages($select=LanguageName)")
+	if filter := GetFilterForScans(fromDate, teamName, projectIDs); filter != "" {
+		q.Add("$filter", filter)
+	}
+	q.Add("$orderby", "ProjectId,Id")
+	q.Add("$skip", fmt.Sprintf("%d", offset))
+	q.Add("$top", fmt.Sprintf("%d", limit))
+	req.URL.RawQuery = q.Encode()
+	body, getErr := c.getResponseBodyFromRequest(req)
+	if getErr != nil {
+		return nil, getErr
+	}
+	var response ODataScans
+	if unmarshalErr := json.Unmarshal(body, &response); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return response.Value, nil
+}
+
+// CreateScanReport creates a scan report and returns its content as a stream, which the caller must close
+func (c *APIClient) CreateScanReport(scanID int, reportType string, retry Retry) (io.ReadCloser, error) {
 	reportBody := &ReportRequest{
 		ReportType: reportType,
 		ScanID:     scanID,
 	}
 	reportJSON, marshalErr := json.Marshal(reportBody)
 	if marshalErr != nil {
-		return []byte{}, marshalErr
+		return nil, marshalErr
 	}
 	body := bytes.NewBuffer(reportJSON)
 	log.Debug().
@@ -398,12 +523,12 @@ func (c *APIClient) CreateScanReport(scanID int, reportType string, retry Retry)
 		Msg("creating report")
 	postResponse, createErr := c.postReportID(body)
 	if createErr != nil {
-		return []byte{}, createErr
+		return nil, createErr
 	}
 	var reportCreateResponse ReportResponse
 	unmarshalErr := json.Unmarshal(postResponse, &reportCreateResponse)
 	if unmarshalErr != nil {
-		return []byte{}, unmarshalErr
+		return nil, unmarshalErr
 	}
 	for i := 1; i <= retry.Attempts; i++ {
 		time.Sleep(retryablehttp.DefaultBackoff(retry.MinSleep, retry.MaxSleep, i, nil))
@@ -414,17 +539,17 @@ func (c *APIClient) CreateScanReport(scanID int, reportType string, retry Retry)
 			Msg("getting report")
 		status, statusFetchErr := c.getReportStatusResponse(reportCreateResponse)
 		if statusFetchErr != nil {
-			return []byte{}, statusFetchErr
+			return nil, statusFetchErr
 		}
 		if status.Status.Value == "Created" {
 			reportData, getReportErr := c.getReportResult(reportCreateResponse.ReportID)
 			if getReportErr != nil {
-				return []byte{}, getReportErr
+				return nil, getReportErr
 			}
 			return reportData, nil
 		}
 	}
-	return []byte{}, fmt.Errorf("failed getting report after %d attempts", retry.Attempts)
+	return nil, fmt.Errorf("failed getting report after %d attempts", retry.Attempts)
 }
 
 func (c *APIClient) GetEngineServers() ([]*EngineServer, error) {
@@ -471,19 +596,9 @@ func (c *APIClient) GetEngineConfigurationMappings() ([]byte, error) {
 }
 
 func (c *APIClient) GetConfigurationsKeys() (*EngineKeysConfigMapping, error) {
-	resp, err := http.Get(engineConfigURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch engine config: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch engine config: received status code %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	body := c.EngineConfigMapping
+	if len(body) == 0 {
+		body = data.EngineConfigMapping
 	}
 
 	var mapping EngineKeysConfigMapping
@@ -518,3 +633,81 @@ func (c *APIClient) GetProjectExcludeSettings(projectID int) (*ProjectExcludeSet
 	}
 	return &excludeSettings, nil
 }
+
+// GetProjectGitSettings returns the git settings of a project, it fails for projects not scanned from git
+func (c *APIClient) GetProjectGitSettings(projectID int) (*ProjectGitSettings, error) {
+	var settings ProjectGitSettings
+	if err := c.unmarshalResponseBody(fmt.Sprintf(projectGitSettingsEndpoint, projectID), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetProjectScanSettings returns the scan settings of a project
+func (c *APIClient) GetProjectScanSettings(projectID int) (*ProjectScanSettings, error) {
+	var settings ProjectScanSettings
+	if err := c.unmarshalResponseBody(fmt.Sprintf(projectScanSettingsEndpoint, projectID), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetProjectScheduling returns the scan schedule of a project
+func (c *APIClient) GetProjectScheduling(projectID int) (*ProjectScheduling, error) {
+	var scheduling ProjectScheduling
+	if err := c.unmarshalResponseBody(fmt.Sprintf(projectSchedulingEndpoint, projectID), &scheduling); err != nil {
+		return nil, err
+	}
+	return &scheduling, nil
+}
+
+// GetProjectIssueTrackingSettings returns the issue tracker settings of a project
+func (c *APIClient) GetProjectIssueTrackingSettings(projectID int) (*ProjectIssueTrackingSettings, error) {
+	var settings ProjectIssueTrackingSettings
+	if err := c.unmarshalResponseBody(fmt.Sprintf(projectIssueTrackingEndpoint, projectID), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetProjectDataRetentionSettings returns the data retention settings of a project
+func (c *APIClient) GetProjectDataRetentionSettings(projectID int) (*ProjectDataRetentionSettings, error) {
+	var settings ProjectDataRetentionSettings
+	if err := c.unmarshalResponseBody(fmt.Sprintf(projectDataRetentionEndpoint, projectID), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetProjectBranch returns how a branched project was created, or nil for projects that aren't branches
+func (c *APIClient) GetProjectBranch(projectID int) (*ProjectBranch, error) {
+	var branch ProjectBranch
+	if err := c.unmarshalResponseBody(fmt.Sprintf(projectBranchEndpoint, projectID), &branch); err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &branch, nil
+}
+
+func (c *APIClient) GetSMTPSettings() ([]byte, error) {
+	return c.getResponseBody(smtpSettingsEndpoint)
+}
+
+func (c *APIClient) GetDataRetentionSettings() ([]byte, error) {
+	return c.getResponseBody(dataRetentionSettingsEndpoint)
+}
+
+func (c *APIClient) GetAuthenticationProviders() ([]byte, error) {
+	return c.getResponseBody(authProvidersEndpoint)
+}
+
+func (c *APIClient) GetResultSeverityLevels() ([]byte, error) {
+	return c.getResponseBody(resultSeverityLevelsEndpoint)
+}
+
+func (c *APIClient) GetScanQueueSettings() ([]byte, error) {
+	return c.getResponseBody(scanQueueSettingsEndpoint)
+}
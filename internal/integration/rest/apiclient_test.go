@@ -287,6 +287,33 @@ func TestAPIClient_GetRoles(t *testing.T) {
 	})
 }
 
+func TestAPIClient_GetPermissions(t *testing.T) {
+	t.Run("returns permissions response", func(t *testing.T) {
+		responseJSON := `[{"id": 1, "serviceProviderId": 1, "name": "manage-users"},
+						  {"id": 2, "serviceProviderId": 2, "name": "view-results"}]`
+		client, clientErr := newMockClient(makeOkResponse(responseJSON)) //nolint:bodyclose
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetPermissions()
+
+		assert.NoError(t, err)
+		expected := []*Permission{
+			{ID: 1, ServiceProviderID: 1, Name: "manage-users"},
+			{ID: 2, ServiceProviderID: 2, Name: "view-results"},
+		}
+		assert.Equal(t, expected, result)
+	})
+	t.Run("returns error if response is not HTTP OK", func(t *testing.T) {
+		client, clientErr := newMockClient(makeBadRequestResponse(ErrorResponseJSON)) //nolint:bodyclose
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetPermissions()
+
+		assert.Error(t, err)
+		assert.Len(t, result, 0)
+	})
+}
+
 func TestAPIClient_GetProjectsWithLastScanID(t *testing.T) {
 	odataResponse := `
 {
@@ -337,6 +364,72 @@ func TestAPIClient_GetTriagedResultsByScanID(t *testing.T) {
 	assert.Equal(t, expected, *result)
 }
 
+func TestAPIClient_GetResultsHistoryByScanID(t *testing.T) {
+	odataResponse := `
+{
+    "@odata.context": "http://localhost/odata/$metadata#somecontext",
+    "value": [
+        {"Id": 2, "PathId": 5, "SimilarityId": -1234, "QueryId": 5157, "ResultHistory": [
+            {"Id": 10, "Date": "2022-04-21T19:26:26.93Z", "UserName": "admin", "Comment": "false positive",
+             "OldStateId": 0, "NewStateId": 1, "OldSeverityId": null, "NewSeverityId": null,
+             "OldAssignedTo": null, "NewAssignedTo": null}
+        ]}
+	]
+}`
+	response := makeOkResponse(odataResponse)
+	defer response.Body.Close()
+	adapter := &HTTPClientMock{DoResponse: response, DoError: nil}
+	client, _ := NewSASTClient(BaseURL, adapter)
+	client.Token = mockToken
+
+	result, err := client.GetResultsHistoryByScanID(1000000, 0, 10)
+
+	comment := "false positive"
+	oldState, newState := 0, 1
+	expected := []*ResultWithHistory{
+		{
+			ID: 2, PathID: 5, SimilarityID: -1234, QueryID: 5157,
+			History: []*ResultHistoryEntry{
+				{
+					ID: 10, Date: "2022-04-21T19:26:26.93Z", UserName: "admin", Comment: &comment,
+					OldStateID: &oldState, NewStateID: &newState,
+				},
+			},
+		},
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestAPIClient_GetScans(t *testing.T) {
+	odataResponse := `
+{
+    "@odata.context": "http://localhost/odata/$metadata#somecontext",
+    "value": [
+        {"Id": 1000, "ProjectId": 1, "IsIncremental": false, "Origin": "Web Portal", "LOC": 1200, "FileCount": 40,
+         "ProductVersion": "9.4.0.1001", "High": 1, "TotalVulnerabilities": 1,
+         "Preset": {"Name": "Checkmarx Default"}, "ScannedLanguages": [{"LanguageName": "Java"}]}
+	]
+}`
+	response := makeOkResponse(odataResponse)
+	defer response.Body.Close()
+	adapter := &HTTPClientMock{DoResponse: response, DoError: nil}
+	client, _ := NewSASTClient(BaseURL, adapter)
+	client.Token = mockToken
+
+	result, err := client.GetScans("2021-10-7", "", "1", 0, 10)
+
+	expected := []*Scan{
+		{
+			ID: 1000, ProjectID: 1, Origin: "Web Portal", LOC: 1200, FileCount: 40, ProductVersion: "9.4.0.1001",
+			High: 1, TotalVulnerabilities: 1, Preset: &ScanPreset{Name: "Checkmarx Default"},
+			ScannedLanguages: []ScannedLanguage{{LanguageName: "Java"}},
+		},
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
 // nolint:funlen
 func TestAPIClient_CreateScanReport(t *testing.T) {
 	retry := Retry{Attempts: 3, MinSleep: time.Millisecond, MaxSleep: time.Millisecond}
@@ -396,9 +489,11 @@ func TestAPIClient_CreateScanReport(t *testing.T) {
 		client := makeCreateReportClient(responses)
 		result, err := client.CreateScanReport(scanID, ScanReportTypeXML, retry)
 
-		expected := reportXML
 		assert.NoError(t, err)
-		assert.Equal(t, expected, string(result))
+		defer result.Close()
+		data, readErr := io.ReadAll(result)
+		assert.NoError(t, readErr)
+		assert.Equal(t, reportXML, string(data))
 	})
 	t.Run("fails if create report fails", func(t *testing.T) {
 		scanID := 1000001
@@ -417,7 +512,7 @@ func TestAPIClient_CreateScanReport(t *testing.T) {
 
 		expectedErr := fmt.Sprintf("request POST %s/CxRestAPI/help/reports/sastScan failed with status code 500", BaseURL)
 		assert.EqualError(t, err, expectedErr)
-		assert.Equal(t, "", string(result))
+		assert.Nil(t, result)
 	})
 	t.Run("fails if get report status fails", func(t *testing.T) {
 		scanID := 1000002
@@ -445,7 +540,7 @@ func TestAPIClient_CreateScanReport(t *testing.T) {
 
 		expectedErr := fmt.Sprintf("request GET %s/CxRestAPI/help/reports/sastScan/1252/status failed with status code 500", BaseURL)
 		assert.EqualError(t, err, expectedErr)
-		assert.Equal(t, "", string(result))
+		assert.Nil(t, result)
 	})
 	t.Run("fails if get report status exhausts attempts ", func(t *testing.T) {
 		scanID := 1000004
@@ -479,7 +574,7 @@ func TestAPIClient_CreateScanReport(t *testing.T) {
 		result, err := client.CreateScanReport(scanID, ScanReportTypeXML, retry)
 
 		assert.EqualError(t, err, "failed getting report after 3 attempts")
-		assert.Equal(t, "", string(result))
+		assert.Nil(t, result)
 	})
 	t.Run("fails if fetch report fails", func(t *testing.T) {
 		scanID := 1000003
@@ -514,7 +609,7 @@ func TestAPIClient_CreateScanReport(t *testing.T) {
 
 		expectedErr := fmt.Sprintf("request GET %s/CxRestAPI/help/reports/sastScan/1253 failed with status code 500", BaseURL)
 		assert.EqualError(t, err, expectedErr)
-		assert.Equal(t, "", string(result))
+		assert.Nil(t, result)
 	})
 }
 
@@ -691,6 +786,38 @@ func TestAPIClient_GetEngineConfigurations(t *testing.T) {
 	})
 }
 
+func TestAPIClient_GetConfigurationsKeys(t *testing.T) {
+	t.Run("returns embedded engine configuration keys", func(t *testing.T) {
+		client, clientErr := newMockClient(makeOkResponse(""))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetConfigurationsKeys()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Multi-language Scan", result.EngineConfig.Configurations.Configuration[0].Name)
+	})
+	t.Run("returns engine configuration keys from client data", func(t *testing.T) {
+		client, clientErr := newMockClient(makeOkResponse(""))
+		assert.NoError(t, clientErr)
+		client.EngineConfigMapping = []byte(`{"EngineConfig": {"Configurations": {"Configuration": [{"Name": "Custom"}]}}}`)
+
+		result, err := client.GetConfigurationsKeys()
+
+		assert.NoError(t, err)
+		assert.Len(t, result.EngineConfig.Configurations.Configuration, 1)
+		assert.Equal(t, "Custom", result.EngineConfig.Configurations.Configuration[0].Name)
+	})
+	t.Run("fails if engine configuration keys are invalid", func(t *testing.T) {
+		client, clientErr := newMockClient(makeOkResponse(""))
+		assert.NoError(t, clientErr)
+		client.EngineConfigMapping = []byte(`{`)
+
+		_, err := client.GetConfigurationsKeys()
+
+		assert.ErrorContains(t, err, "failed to unmarshal JSON")
+	})
+}
+
 func makeOkResponse(body string) *http.Response {
 	return &http.Response{
 		StatusCode: 200,
@@ -735,3 +862,168 @@ func makeCreateReportClient(responses map[string][]DoResponse) *APIClient {
 	client.Token = &AccessToken{AccessToken: "jwt", TokenType: "Bearer", ExpiresIn: 1234}
 	return client
 }
+
+func TestAPIClient_GetProjectSettings(t *testing.T) {
+	//nolint:bodyclose
+	t.Run("returns git settings", func(t *testing.T) {
+		client, clientErr := newMockClient(makeOkResponse(`{"url": "https://git.example.com/repo.git", "branch": "main", "useSsh": false}`))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetProjectGitSettings(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ProjectGitSettings{URL: "https://git.example.com/repo.git", Branch: "main"}, result)
+	})
+	//nolint:bodyclose
+	t.Run("returns scan settings", func(t *testing.T) {
+		responseJSON := `{"project": {"id": 1}, "preset": {"id": 36}, "engineConfiguration": {"id": 1},
+			"postScanAction": {"id": 2}, "emailNotifications": {"failedScan": ["admin@example.com"], "beforeScan": [], "afterScan": []}}`
+		client, clientErr := newMockClient(makeOkResponse(responseJSON))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetProjectScanSettings(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &IDRef{ID: 2}, result.PostScanAction)
+		assert.Equal(t, []string{"admin@example.com"}, result.EmailNotifications.FailedScan)
+	})
+	//nolint:bodyclose
+	t.Run("returns issue tracking settings", func(t *testing.T) {
+		responseJSON := `{"issueTrackingSystem": {"id": 1}, "jiraProjectId": "10000",
+			"issueType": {"id": 10004, "fields": [{"id": 5, "values": ["High"]}]}}`
+		client, clientErr := newMockClient(makeOkResponse(responseJSON))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetProjectIssueTrackingSettings(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "10000", result.JiraProjectID)
+		assert.Equal(t, []IssueTrackingField{{ID: 5, Values: []string{"High"}}}, result.IssueType.Fields)
+	})
+	//nolint:bodyclose
+	t.Run("fails if the request fails", func(t *testing.T) {
+		adapter := &HTTPClientMock{DoResponse: nil, DoError: fmt.Errorf("not found")}
+		client, _ := NewSASTClient(BaseURL, adapter)
+		client.Token = mockToken
+
+		result, err := client.GetProjectScheduling(1)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestAPIClient_GetProjectBranch(t *testing.T) {
+	//nolint:bodyclose
+	t.Run("returns how the project was branched", func(t *testing.T) {
+		client, clientErr := newMockClient(makeOkResponse(`{"id": 2, "originalProjectId": 1, "branchedOnScanId": 1000}`))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetProjectBranch(2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ProjectBranch{ID: 2, OriginalProjectID: 1, BranchedOnScanID: 1000}, result)
+	})
+	//nolint:bodyclose
+	t.Run("returns nil if the project isn't a branch", func(t *testing.T) {
+		client, clientErr := newMockClient(makeResponse(http.StatusNotFound, "Not Found", `{}`))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetProjectBranch(1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+	//nolint:bodyclose
+	t.Run("fails for other status codes", func(t *testing.T) {
+		client, clientErr := newMockClient(makeResponse(http.StatusInternalServerError, "Internal Server Error", `{}`))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetProjectBranch(1)
+
+		assert.EqualError(t, err, "request GET "+BaseURL+"/CxRestAPI/projects/branch/1 failed with status code 500")
+		assert.Nil(t, result)
+	})
+}
+
+func TestAPIClient_GetSystemSettings(t *testing.T) {
+	responseJSON := `{"host": "smtp.example.com", "port": 25}`
+	getters := map[string]func(client *APIClient) ([]byte, error){
+		"smtp settings":            (*APIClient).GetSMTPSettings,
+		"data retention settings":  (*APIClient).GetDataRetentionSettings,
+		"authentication providers": (*APIClient).GetAuthenticationProviders,
+		"result severity levels":   (*APIClient).GetResultSeverityLevels,
+		"scan queue settings":      (*APIClient).GetScanQueueSettings,
+	}
+	for name, get := range getters {
+		get := get
+		//nolint:bodyclose
+		t.Run(fmt.Sprintf("returns %s", name), func(t *testing.T) {
+			client, clientErr := newMockClient(makeOkResponse(responseJSON))
+			assert.NoError(t, clientErr)
+
+			result, err := get(client)
+
+			assert.NoError(t, err)
+			assert.Equal(t, responseJSON, string(result))
+		})
+		//nolint:bodyclose
+		t.Run(fmt.Sprintf("fails getting %s if the request fails", name), func(t *testing.T) {
+			adapter := &HTTPClientMock{DoResponse: nil, DoError: fmt.Errorf("not found")}
+			client, _ := NewSASTClient(BaseURL, adapter)
+			client.Token = mockToken
+
+			result, err := get(client)
+
+			assert.Error(t, err)
+			assert.Len(t, result, 0)
+		})
+	}
+}
+
+func TestAPIClient_GetIdentityProviders(t *testing.T) {
+	//nolint:bodyclose
+	t.Run("returns ldap servers", func(t *testing.T) {
+		responseJSON := `[{"id": 1, "active": true, "name": "corp", "host": "ldap.corp.local", "port": 389,
+			"ldapDirectoryType": "MicrosoftActiveDirectory", "username": "cn=bind", "baseDn": "dc=corp,dc=local", "defaultRoleId": 3}]`
+		client, clientErr := newMockClient(makeOkResponse(responseJSON))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetLdapServers()
+
+		assert.NoError(t, err)
+		roleID := 3
+		expected := []*LdapServer{{
+			ID: 1, Active: true, Name: "corp", Host: "ldap.corp.local", Port: 389, LdapDirectoryType: "MicrosoftActiveDirectory",
+			Username: "cn=bind", BaseDn: "dc=corp,dc=local", DefaultRoleID: &roleID,
+		}}
+		assert.Equal(t, expected, result)
+	})
+	//nolint:bodyclose
+	t.Run("returns saml identity providers", func(t *testing.T) {
+		responseJSON := `[{"id": 2, "active": true, "name": "okta", "issuer": "http://www.okta.com/1",
+			"loginUrl": "https://okta.example.com/sso", "certificateSubject": "CN=okta"}]`
+		client, clientErr := newMockClient(makeOkResponse(responseJSON))
+		assert.NoError(t, clientErr)
+
+		result, err := client.GetSamlIdentityProviders()
+
+		assert.NoError(t, err)
+		expected := []*SamlIdentityProvider{{
+			ID: 2, Active: true, Name: "okta", Issuer: "http://www.okta.com/1", LoginURL: "https://okta.example.com/sso",
+			CertificateSubject: "CN=okta",
+		}}
+		assert.Equal(t, expected, result)
+	})
+	//nolint:bodyclose
+	t.Run("fails if the request fails", func(t *testing.T) {
+		adapter := &HTTPClientMock{DoResponse: nil, DoError: fmt.Errorf("not found")}
+		client, _ := NewSASTClient(BaseURL, adapter)
+		client.Token = mockToken
+
+		result, err := client.GetLdapServers()
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -48,6 +48,40 @@ type (
 		LastScanID int `json:"LastScanId"`
 	}
 
+	ODataScans struct {
+		Value []*Scan `json:"value"`
+	}
+
+	// Scan is a scan of a project, with its result counts by severity
+	Scan struct {
+		ID                   int               `json:"Id"`
+		ProjectID            int               `json:"ProjectId"`
+		IsIncremental        bool              `json:"IsIncremental"`
+		Origin               string            `json:"Origin"`
+		ScanRequestedOn      string            `json:"ScanRequestedOn"`
+		EngineStartedOn      string            `json:"EngineStartedOn"`
+		ScanCompletedOn      string            `json:"ScanCompletedOn"`
+		LOC                  int               `json:"LOC"`
+		FileCount            int               `json:"FileCount"`
+		ProductVersion       string            `json:"ProductVersion"`
+		Comment              string            `json:"Comment"`
+		High                 int               `json:"High"`
+		Medium               int               `json:"Medium"`
+		Low                  int               `json:"Low"`
+		Info                 int               `json:"Info"`
+		TotalVulnerabilities int               `json:"TotalVulnerabilities"`
+		Preset               *ScanPreset       `json:"Preset"`
+		ScannedLanguages     []ScannedLanguage `json:"ScannedLanguages"`
+	}
+
+	ScanPreset struct {
+		Name string `json:"Name"`
+	}
+
+	ScannedLanguage struct {
+		LanguageName string `json:"LanguageName"`
+	}
+
 	ODataTriagedResultsByScan struct {
 		Value []TriagedScanResult
 	}
@@ -56,6 +90,33 @@ type (
 		ID int `json:"Id"`
 	}
 
+	ODataResultsHistory struct {
+		Value []*ResultWithHistory `json:"value"`
+	}
+
+	// ResultWithHistory is a triaged result of a scan and its triage history
+	ResultWithHistory struct {
+		ID           int                   `json:"Id"`
+		PathID       int                   `json:"PathId"`
+		SimilarityID int64                 `json:"SimilarityId"`
+		QueryID      int64                 `json:"QueryId"`
+		History      []*ResultHistoryEntry `json:"ResultHistory"`
+	}
+
+	// ResultHistoryEntry is a triage change of a result, fields not changed by the entry are null
+	ResultHistoryEntry struct {
+		ID            int     `json:"Id"`
+		Date          string  `json:"Date"`
+		UserName      string  `json:"UserName"`
+		Comment       *string `json:"Comment"`
+		OldStateID    *int    `json:"OldStateId"`
+		NewStateID    *int    `json:"NewStateId"`
+		OldSeverityID *int    `json:"OldSeverityId"`
+		NewSeverityID *int    `json:"NewSeverityId"`
+		OldAssignedTo *string `json:"OldAssignedTo"`
+		NewAssignedTo *string `json:"NewAssignedTo"`
+	}
+
 	Team struct {
 		ID       int    `json:"id"`
 		Name     string `json:"name"`
@@ -85,10 +146,15 @@ type (
 		PresetID      int            `json:"presetId"`
 		CreatedDate   string         `json:"createdDate"`
 		Configuration *Configuration `json:"configuration"`
+		// OriginalProjectID is the project this project was branched from, if it's a branched project
+		OriginalProjectID int `json:"originalProjectId,omitempty"`
+		BranchedOnScanID  int `json:"branchedOnScanId,omitempty"`
 	}
 
 	Configuration struct {
 		CustomFields []*CustomField `json:"customFields"`
+		// Tags are the CxOne project tags the custom fields are exported as
+		Tags map[string]string `json:"tags,omitempty"`
 	}
 
 	CustomField struct {
@@ -96,6 +162,13 @@ type (
 		FieldValue string `json:"fieldValue"`
 	}
 
+	// CustomFieldDefinition is a custom field projects can have
+	CustomFieldDefinition struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		IsMandatory bool   `json:"isMandatory"`
+	}
+
 	ProjectOData struct {
 		ID           int            `json:"Id"`
 		CreatedDate  string         `json:"CreatedDate"`
@@ -108,6 +181,20 @@ type (
 		OwnerName string `json:"ownerName"`
 	}
 
+	Role struct {
+		ID            int    `json:"id"`
+		IsSystemRole  bool   `json:"isSystemRole"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		PermissionIDs []int  `json:"permissionIds"`
+	}
+
+	Permission struct {
+		ID                int    `json:"id"`
+		ServiceProviderID int    `json:"serviceProviderId"`
+		Name              string `json:"name"`
+	}
+
 	User struct {
 		ID                       int      `json:"id"`
 		UserName                 string   `json:"userName"`
@@ -130,6 +217,56 @@ type (
 		LocaleID                 int      `json:"localeId"`
 	}
 
+	// LdapServer is an LDAP server users are authenticated and synchronized with,
+	// fields a SAST version doesn't return are omitted
+	LdapServer struct {
+		ID                                int    `json:"id"`
+		Active                            bool   `json:"active"`
+		Name                              string `json:"name"`
+		Host                              string `json:"host"`
+		Port                              int    `json:"port"`
+		LdapDirectoryType                 string `json:"ldapDirectoryType"`
+		UseSsl                            bool   `json:"useSsl"`
+		VerifySslCertificate              bool   `json:"verifySslCertificate"`
+		Username                          string `json:"username,omitempty"`
+		Password                          string `json:"password,omitempty"`
+		BaseDn                            string `json:"baseDn"`
+		AdditionalUserDn                  string `json:"additionalUserDn,omitempty"`
+		UserObjectFilter                  string `json:"userObjectFilter,omitempty"`
+		UserObjectClass                   string `json:"userObjectClass,omitempty"`
+		UsernameAttribute                 string `json:"usernameAttribute,omitempty"`
+		FirstNameAttribute                string `json:"firstNameAttribute,omitempty"`
+		LastNameAttribute                 string `json:"lastNameAttribute,omitempty"`
+		EmailAttribute                    string `json:"emailAttribute,omitempty"`
+		SynchronizationEnabled            bool   `json:"synchronizationEnabled"`
+		DefaultTeamID                     *int   `json:"defaultTeamId,omitempty"`
+		DefaultRoleID                     *int   `json:"defaultRoleId,omitempty"`
+		UpdateTeamAndRoleUponLogin        bool   `json:"updateTeamAndRoleUponLogin"`
+		PeriodicalSynchronizationEnabled  bool   `json:"periodicalSynchronizationEnabled"`
+		AdvancedTeamAndRoleMappingEnabled bool   `json:"advancedTeamAndRoleMappingEnabled"`
+		SsoEnabled                        bool   `json:"ssoEnabled"`
+	}
+
+	// SamlIdentityProvider is a SAML identity provider users are authenticated with,
+	// fields a SAST version doesn't return are omitted
+	SamlIdentityProvider struct {
+		ID                  int    `json:"id"`
+		Active              bool   `json:"active"`
+		Name                string `json:"name"`
+		Issuer              string `json:"issuer"`
+		LoginURL            string `json:"loginUrl"`
+		LogoutURL           string `json:"logoutUrl,omitempty"`
+		ErrorURL            string `json:"errorUrl,omitempty"`
+		SignAuthnRequest    bool   `json:"signAuthnRequest"`
+		AuthnRequestBinding string `json:"authnRequestBinding,omitempty"`
+		IsManualManagement  bool   `json:"isManualManagement"`
+		DefaultTeamID       *int   `json:"defaultTeamId,omitempty"`
+		DefaultRoleID       *int   `json:"defaultRoleId,omitempty"`
+		CertificateFileName string `json:"certificateFileName,omitempty"`
+		CertificateSubject  string `json:"certificateSubject,omitempty"`
+		Certificate         string `json:"certificate,omitempty"`
+	}
+
 	SamlTeamMapping struct {
 		ID                     int    `json:"id"`
 		SamlIdentityProviderID int    `json:"samlIdentityProviderId"`
@@ -205,4 +342,67 @@ type (
 		ExcludeFilesPattern   string `json:"excludeFilesPattern"`
 		Link                  Link   `json:"link"`
 	}
+
+	// ProjectBranch is how a branched project was created
+	ProjectBranch struct {
+		ID                int `json:"id"`
+		OriginalProjectID int `json:"originalProjectId"`
+		BranchedOnScanID  int `json:"branchedOnScanId"`
+	}
+
+	// ProjectGitSettings is the git repository a project is scanned from, the url may have credentials
+	ProjectGitSettings struct {
+		URL    string `json:"url"`
+		Branch string `json:"branch"`
+		UseSSH bool   `json:"useSsh"`
+	}
+
+	IDRef struct {
+		ID int `json:"id"`
+	}
+
+	// ProjectScanSettings are the scan settings of a project, including its post scan action and email notifications
+	ProjectScanSettings struct {
+		Project                 IDRef              `json:"project"`
+		Preset                  IDRef              `json:"preset"`
+		EngineConfiguration     IDRef              `json:"engineConfiguration"`
+		PostScanAction          *IDRef             `json:"postScanAction"`
+		PostScanActionArguments string             `json:"postScanActionArguments"`
+		EmailNotifications      EmailNotifications `json:"emailNotifications"`
+	}
+
+	EmailNotifications struct {
+		FailedScan []string `json:"failedScan"`
+		BeforeScan []string `json:"beforeScan"`
+		AfterScan  []string `json:"afterScan"`
+	}
+
+	// ProjectScheduling is the scan schedule of a project
+	ProjectScheduling struct {
+		ScheduleType  string   `json:"scheduleType"`
+		ScheduledDays []string `json:"scheduledDays"`
+		ScheduleTime  string   `json:"scheduleTime"`
+	}
+
+	// ProjectIssueTrackingSettings are the issue tracker settings of a project, e.g. the Jira project issues are opened in
+	ProjectIssueTrackingSettings struct {
+		IssueTrackingSystem IDRef             `json:"issueTrackingSystem"`
+		JiraProjectID       string            `json:"jiraProjectId"`
+		IssueType           IssueTrackingType `json:"issueType"`
+	}
+
+	IssueTrackingType struct {
+		ID     int                  `json:"id"`
+		Fields []IssueTrackingField `json:"fields"`
+	}
+
+	IssueTrackingField struct {
+		ID     int      `json:"id"`
+		Values []string `json:"values"`
+	}
+
+	// ProjectDataRetentionSettings is how many scans of a project are kept
+	ProjectDataRetentionSettings struct {
+		ScansToKeep int `json:"numOfScansToPreserve"`
+	}
 )
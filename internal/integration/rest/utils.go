@@ -52,6 +52,21 @@ func GetFilterForProjectsWithLastScan(fromDate, teamName, projectIDs string) str
 		getProjectIDsFilter(projectIDs))
 }
 
+// GetFilterForScans get filter string for the scans of the projects in the project filters
+func GetFilterForScans(fromDate, teamName, projectIDs string) string {
+	filters := []string{}
+	if fromDate != "" {
+		filters = append(filters, fmt.Sprintf("ScanCompletedOn gt %s", fromDate))
+	}
+	if teamName != "" {
+		filters = append(filters, fmt.Sprintf("Project/%s", getTeamFilter(teamName)))
+	}
+	if projectIDs != "" {
+		filters = append(filters, getIDsFilter("ProjectId", projectIDs))
+	}
+	return strings.Join(filters, " and ")
+}
+
 // getProjectFilterForEmptyDate get project filter when date empty
 func getProjectFilterForEmptyDate(projectIDs, teamName string) string {
 	if teamName == "" {
@@ -67,20 +82,25 @@ func getTeamFilter(teamName string) string {
 
 // getProjectIDsFilter get filter string for project-id option
 func getProjectIDsFilter(projectIDs string) string {
+	return getIDsFilter("Id", projectIDs)
+}
+
+// getIDsFilter get filter string for project-id option on the given project id field
+func getIDsFilter(field, projectIDs string) string {
 	if matched, _ := regexp.MatchString(`^\d+$`, projectIDs); matched {
-		return fmt.Sprintf("Id eq %s", projectIDs)
+		return fmt.Sprintf("%s eq %s", field, projectIDs)
 	}
 	if matched, _ := regexp.MatchString(`^\d+(,\s?\d+)+$`, projectIDs); matched {
-		return fmt.Sprintf("Id in (%s)", projectIDs)
+		return fmt.Sprintf("%s in (%s)", field, projectIDs)
 	}
 	if matched, _ := regexp.MatchString(`^\d+\s?-\s?\d+$`, projectIDs); matched {
 		ids := strings.Split(projectIDs, "-")
 		minValue, maxValue := getMinMax(ids)
-		return fmt.Sprintf("Id ge %d and Id le %d", minValue, maxValue)
+		return fmt.Sprintf("%s ge %d and %s le %d", field, minValue, field, maxValue)
 	}
 
 	log.Warn().Msg("--project-id has wrong param. It should be like --project-id 1 or 1,3,8 or 1-3")
-	return "Id gt 0"
+	return fmt.Sprintf("%s gt 0", field)
 }
 
 // getMinMax get min and max values
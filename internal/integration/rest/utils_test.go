@@ -115,4 +115,37 @@ func TestApiClientUtils(t *testing.T) {
 			assert.Equal(t, test.expectedResult, result)
 		}
 	})
+	t.Run("Test filter for scans", func(t *testing.T) {
+		tests := []TestObj{
+			{
+				fromDate:       fromDate,
+				teamName:       "",
+				projectIds:     "",
+				expectedResult: "ScanCompletedOn gt 2022-01-15",
+			},
+			{
+				fromDate:       fromDate,
+				teamName:       "TestTeam",
+				projectIds:     "1-5",
+				expectedResult: "ScanCompletedOn gt 2022-01-15 and Project/OwningTeam/FullName eq 'TestTeam' and ProjectId ge 1 and ProjectId le 5",
+			},
+			{
+				fromDate:       "",
+				teamName:       "",
+				projectIds:     "1,2",
+				expectedResult: "ProjectId in (1,2)",
+			},
+			{
+				fromDate:       "",
+				teamName:       "",
+				projectIds:     "",
+				expectedResult: "",
+			},
+		}
+
+		for _, test := range tests {
+			result := GetFilterForScans(test.fromDate, test.teamName, test.projectIds)
+			assert.Equal(t, test.expectedResult, result)
+		}
+	})
 }
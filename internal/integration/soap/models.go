@@ -192,10 +192,10 @@ type (
 	}
 
 	CxQueryCategory struct {
-		XMLName      xml.Name `xml:"CxQueryCategory"`
-		ID           int      `xml:"Id"`
-		CategoryName string   `xml:"CategoryName"`
-		CategoryType int      `xml:"CategoryType"`
+		XMLName      xml.Name     `xml:"CxQueryCategory"`
+		ID           int          `xml:"Id"`
+		CategoryName string       `xml:"CategoryName"`
+		CategoryType CategoryType `xml:"CategoryType"`
 	}
 
 	CategoryType struct {
@@ -234,6 +234,11 @@ type (
 		IsUserAllowToUpdate bool     `xml:"isUserAllowToUpdate"`
 		IsUserAllowToDelete bool     `xml:"isUserAllowToDelete"`
 		IsDuplicate         bool     `xml:"IsDuplicate"`
+		// Queries is not returned by SAST, it's added on export with the AST query each SAST query translates to
+		Queries *PresetQueries `xml:"queries"`
+		// MissingQueries is not returned by SAST, it's added on export with the mapped queries of the preset
+		// that aren't in the SAST query collection, which are left out of Queries
+		MissingQueries *PresetMissingQueries `xml:"missingQueries,omitempty"`
 	}
 
 	QueryIDs struct {
@@ -241,6 +246,23 @@ type (
 		Long    []int    `xml:"long"`
 	}
 
+	PresetQueries struct {
+		Query []PresetQuery `xml:"query"`
+	}
+
+	PresetMissingQueries struct {
+		SastQueryID []int `xml:"sastQueryId"`
+	}
+
+	PresetQuery struct {
+		SastQueryID int    `xml:"sastQueryId,attr"`
+		AstQueryID  string `xml:"astQueryId,attr,omitempty"`
+		Language    string `xml:"language,attr,omitempty"`
+		Group       string `xml:"group,attr,omitempty"`
+		Name        string `xml:"name,attr,omitempty"`
+		Status      string `xml:"status,attr"`
+	}
+
 	// GetInstallationSettings request types
 
 	GetInstallationSettingsRequest struct {
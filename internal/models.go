@@ -3,6 +3,17 @@ package internal
 import (
 	"encoding/xml"
 	"time"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/customfieldmapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/preset"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/redaction"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/roletranslation"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/statemapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/teammapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/usermapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
 )
 
 type Args struct {
@@ -18,15 +29,75 @@ type Args struct {
 	DBConnectionString,
 	ProjectsIDs,
 	TeamName string
-	RunTime           time.Time
-	QueryMappingFile  string
-	QueryRenamingFile string
+	RunTime               time.Time
+	QueryMappingFiles     []string
+	QueryMappingSHA256s   []string
+	StrictMapping         bool
+	BranchTriageOnce      bool
+	QueryMappingConflicts []querymapping.Conflict
+	QueryRenamingFile     string
+	QueryRenamingSHA256,
+	SignatureKeyFile string
+	Offline           bool
+	DataBundleFile    string
+	DataBundleKeyFile string
+	DataBundle        *databundle.Bundle
+
+	NestedTeams            bool
+	TeamMappingFile        string
+	TeamMapper             *teammapping.Mapper
+	UserMappingFile        string
+	UserMapper             *usermapping.Mapper
+	RoleTranslationFile    string
+	RoleTranslator         *roletranslation.Translator
+	StateMappingFile       string
+	StateMapper            *statemapping.Mapper
+	StateTranslation       *statemapping.Translation
+	CustomFieldMappingFile string
+	CustomFieldMapper      *customfieldmapping.Mapper
+	RedactionRulesFile     string
+	Redactor               *redaction.Redactor
+	PresetCatalogFile      string
+	PresetCatalog          *preset.Catalog
+	SimIDVersion           int
+	ExcludeFile            string
+	ExcludeFiles           []string
+	CustomExtensions       string
+}
+
+// ResolveQueryArgs are the arguments of the resolve-query command
+type ResolveQueryArgs struct {
+	Language,
+	Group,
+	Name,
+	SastQueryID,
+	QueryPath string
+	QueryMappingFiles  []string
+	QueryRenamingFiles []string
+	DataBundleFile,
+	DataBundleKeyFile string
+}
+
+// GenerateMappingArgs are the arguments of the generate-mapping command
+type GenerateMappingArgs struct {
+	URL,
+	Username,
+	Password,
+	QueryRenamingFile,
+	DataBundleFile,
+	DataBundleKeyFile,
+	OutputFile,
+	CompareFile string
+}
 
-	NestedTeams      bool
-	SimIDVersion     int
-	ExcludeFile      string
-	ExcludeFiles     []string
-	CustomExtensions string
+// BundleDataArgs are the arguments of the bundle-data command
+type BundleDataArgs struct {
+	QueryMappingFile,
+	QueryRenamingFile,
+	EngineConfigMappingFile,
+	SigningKeyFile,
+	OutputFile string
+	RunTime time.Time
 }
 
 type ReportJob struct {
@@ -75,6 +146,7 @@ type PresetJob struct {
 type PresetConsumeOutput struct {
 	Err      error
 	PresetID int
+	Preset   *soap.Preset
 }
 
 type EngineKeysData struct {
@@ -4,5 +4,6 @@ type (
 	Batch struct {
 		LocalFiles  []string
 		RemoteFiles []string
+		Size        int
 	}
 )
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
 	"github.com/rs/zerolog/log"
@@ -17,17 +18,29 @@ import (
 const (
 	metadataFilePerm   = 0600
 	metadataFolderPerm = 0700
-	filesPerBatch      = 10
+	// maxBytesPerBatch is the approximate amount of source code requested in a single SOAP call
+	maxBytesPerBatch = 4 * 1024 * 1024
+	// maxFilesPerBatch caps batches of files that are estimated to be very small
+	maxFilesPerBatch = 100
+	// defaultFileSizeEstimate is used for files whose size can't be estimated yet
+	defaultFileSizeEstimate = 64 * 1024
 )
 
 type Repo struct {
 	soapClient soap.Adapter
+	sizeMutex  sync.Mutex
+	fileSizes  map[string]int
+	totalBytes int
+	totalFiles int
 }
 
 func NewRepo(soapClient soap.Adapter) *Repo {
-	return &Repo{soapClient: soapClient}
+	return &Repo{soapClient: soapClient, fileSizes: map[string]int{}}
 }
 
+// DownloadSourceFiles downloads the given source files of a scan, skipping the ones that already exist locally.
+// Files are requested in batches sized by their estimated byte size; if a batch fails its files are retried one by one.
+//
 //nolint:gocyclo
 func (e *Repo) DownloadSourceFiles(scanID string, sourceFiles []interfaces.SourceFile, rmvdir string) error {
 	// Check if rmvdir is provided
@@ -56,41 +69,112 @@ func (e *Repo) DownloadSourceFiles(scanID string, sourceFiles []interfaces.Sourc
 		return false
 	}
 
-	var batches []Batch
-	currentBatch := 0
+	var filesToDownload []interfaces.SourceFile
+	requestedFiles := make(map[string]bool, len(sourceFiles))
 	for _, v := range sourceFiles {
+		if v.RemoteName == "" {
+			// this is a workaround for the empty filename issue where exists triaged results
+			log.Info().Msgf("Empty FileName with triaged results found on scanID %s, it will be ignored.", scanID)
+			continue
+		}
 		if isExcluded(v.RemoteName) {
 			log.Info().Msgf("Excluding problematic file: %s", v.RemoteName)
 			continue
 		}
-		if len(batches) < currentBatch+1 {
-			batches = append(batches, Batch{LocalFiles: []string{}, RemoteFiles: []string{}})
+		if requestedFiles[v.LocalName] {
+			continue
 		}
 		if _, statErr := os.Stat(v.LocalName); errors.Is(statErr, os.ErrNotExist) {
-			batches[currentBatch].RemoteFiles = append(batches[currentBatch].RemoteFiles, v.RemoteName)
-			batches[currentBatch].LocalFiles = append(batches[currentBatch].LocalFiles, v.LocalName)
-		}
-		if len(batches[currentBatch].RemoteFiles) >= filesPerBatch {
-			currentBatch++
+			filesToDownload = append(filesToDownload, v)
+			requestedFiles[v.LocalName] = true
 		}
 	}
 
-	for _, batch := range batches {
-		sourceResponse, sourceErr := e.soapClient.GetSourcesByScanID(scanID, batch.RemoteFiles)
-		if sourceErr != nil {
-			return errors.Wrapf(sourceErr, "could not fetch sources scanID=%s remoteFiles=%v", scanID, batch.RemoteFiles)
+	for _, batch := range e.getBatches(filesToDownload) {
+		batchErr := e.downloadBatch(scanID, batch)
+		if batchErr == nil {
+			continue
 		}
-		contents := sourceResponse.GetSourcesByScanIDResult.CxWSResponseSourcesContent.CxWSResponseSourceContents
-		for i, file := range contents {
-			createErr := createFileAndPath(batch.LocalFiles[i], []byte(file.Source), metadataFilePerm, metadataFolderPerm)
-			if createErr != nil {
-				return errors.Wrapf(createErr, "could not create local file scanID=%s localFile=%s", scanID, batch.LocalFiles[i])
+		log.Warn().Err(batchErr).
+			Str("scanID", scanID).
+			Int("files", len(batch.RemoteFiles)).
+			Int("estimatedBytes", batch.Size).
+			Msg("failed downloading sources batch, retrying files individually")
+		for i := range batch.RemoteFiles {
+			fileBatch := Batch{LocalFiles: batch.LocalFiles[i : i+1], RemoteFiles: batch.RemoteFiles[i : i+1]}
+			if fileErr := e.downloadBatch(scanID, fileBatch); fileErr != nil {
+				return fileErr
 			}
 		}
 	}
 	return nil
 }
 
+// getBatches groups files so that the estimated size of each batch stays under maxBytesPerBatch
+func (e *Repo) getBatches(sourceFiles []interfaces.SourceFile) []Batch {
+	var batches []Batch
+	var current Batch
+	for _, v := range sourceFiles {
+		fileSize := e.getFileSizeEstimate(v.RemoteName)
+		if len(current.RemoteFiles) > 0 &&
+			(current.Size+fileSize > maxBytesPerBatch || len(current.RemoteFiles) >= maxFilesPerBatch) {
+			batches = append(batches, current)
+			current = Batch{}
+		}
+		current.RemoteFiles = append(current.RemoteFiles, v.RemoteName)
+		current.LocalFiles = append(current.LocalFiles, v.LocalName)
+		current.Size += fileSize
+	}
+	if len(current.RemoteFiles) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// downloadBatch fetches a batch of files and writes them locally
+func (e *Repo) downloadBatch(scanID string, batch Batch) error {
+	sourceResponse, sourceErr := e.soapClient.GetSourcesByScanID(scanID, batch.RemoteFiles)
+	if sourceErr != nil {
+		return errors.Wrapf(sourceErr, "could not fetch sources scanID=%s remoteFiles=%v", scanID, batch.RemoteFiles)
+	}
+	contents := sourceResponse.GetSourcesByScanIDResult.CxWSResponseSourcesContent.CxWSResponseSourceContents
+	if len(contents) != len(batch.RemoteFiles) {
+		return errors.Errorf(
+			"expected %d sources but got %d scanID=%s remoteFiles=%v",
+			len(batch.RemoteFiles), len(contents), scanID, batch.RemoteFiles,
+		)
+	}
+	for i, file := range contents {
+		createErr := createFileAndPath(batch.LocalFiles[i], []byte(file.Source), metadataFilePerm, metadataFolderPerm)
+		if createErr != nil {
+			return errors.Wrapf(createErr, "could not create local file scanID=%s localFile=%s", scanID, batch.LocalFiles[i])
+		}
+		e.addFileSize(batch.RemoteFiles[i], len(file.Source))
+	}
+	return nil
+}
+
+// getFileSizeEstimate returns the last known size of a remote file, or the average size of downloaded files
+func (e *Repo) getFileSizeEstimate(remoteName string) int {
+	e.sizeMutex.Lock()
+	defer e.sizeMutex.Unlock()
+	if size, ok := e.fileSizes[remoteName]; ok {
+		return size
+	}
+	if e.totalFiles == 0 {
+		return defaultFileSizeEstimate
+	}
+	return e.totalBytes / e.totalFiles
+}
+
+func (e *Repo) addFileSize(remoteName string, size int) {
+	e.sizeMutex.Lock()
+	defer e.sizeMutex.Unlock()
+	e.fileSizes[remoteName] = size
+	e.totalBytes += size
+	e.totalFiles++
+}
+
 func createFileAndPath(filename string, content []byte, filePerm, dirPerm os.FileMode) error {
 	pathErr := os.MkdirAll(filepath.Dir(filename), dirPerm)
 	if pathErr != nil {
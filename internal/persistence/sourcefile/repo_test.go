@@ -164,3 +164,82 @@ func TestRepo_DownloadSourceFiles_WithExclusions(t *testing.T) {
 	assert.NoError(t, fileErr)
 	assert.Equal(t, "file2 content", string(fileContent))
 }
+
+func TestRepo_DownloadSourceFiles_RetriesFilesIndividually(t *testing.T) {
+	scanID := "1000002"
+	tmpDir := t.TempDir()
+	filesToDownload := []interfaces.SourceFile{
+		{RemoteName: file1, LocalName: fmt.Sprintf("%s/%s", tmpDir, file1)},
+		{RemoteName: file2, LocalName: fmt.Sprintf("%s/%s", tmpDir, file2)},
+		{RemoteName: file2, LocalName: fmt.Sprintf("%s/%s", tmpDir, file2)},
+	}
+	fileSources := map[string]string{
+		file1: "file1",
+		file2: "file2",
+	}
+	getSourcesHandler := func(_ string, files []string) (*soap.GetSourcesByScanIDResponse, error) {
+		var contents []soap.CxWSResponseSourceContent
+		if len(files) == 1 {
+			contents = append(contents, soap.CxWSResponseSourceContent{Source: fileSources[files[0]]})
+		} else {
+			// response doesn't match the request, so it can't be trusted
+			contents = append(contents, soap.CxWSResponseSourceContent{Source: fileSources[file2]})
+		}
+		return &soap.GetSourcesByScanIDResponse{
+			GetSourcesByScanIDResult: soap.GetSourcesByScanIDResult{
+				CxWSResponseSourcesContent: soap.CxWSResponseSourcesContent{CxWSResponseSourceContents: contents},
+			},
+		}, nil
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	soapClientMock := mock_integration_soap.NewMockAdapter(ctrl)
+	soapClientMock.EXPECT().GetSourcesByScanID(scanID, []string{file1, file2}).DoAndReturn(getSourcesHandler)
+	soapClientMock.EXPECT().GetSourcesByScanID(scanID, []string{file1}).DoAndReturn(getSourcesHandler)
+	soapClientMock.EXPECT().GetSourcesByScanID(scanID, []string{file2}).DoAndReturn(getSourcesHandler)
+
+	instance := NewRepo(soapClientMock)
+	err := instance.DownloadSourceFiles(scanID, filesToDownload, "")
+	assert.NoError(t, err)
+
+	assertFileExistWithContent(t, filesToDownload, fileSources)
+}
+
+func TestRepo_DownloadSourceFiles_FailsIfFileCantBeFetched(t *testing.T) {
+	scanID := "1000003"
+	tmpDir := t.TempDir()
+	filesToDownload := []interfaces.SourceFile{
+		{RemoteName: file1, LocalName: fmt.Sprintf("%s/%s", tmpDir, file1)},
+		{RemoteName: file2, LocalName: fmt.Sprintf("%s/%s", tmpDir, file2)},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	soapClientMock := mock_integration_soap.NewMockAdapter(ctrl)
+	soapClientMock.EXPECT().GetSourcesByScanID(scanID, gomock.Any()).Return(nil, fmt.Errorf("timeout")).Times(2)
+
+	instance := NewRepo(soapClientMock)
+	err := instance.DownloadSourceFiles(scanID, filesToDownload, "")
+	assert.EqualError(t, err, "could not fetch sources scanID=1000003 remoteFiles=[project/folder1/file1.go]: timeout")
+}
+
+func TestRepo_GetBatches(t *testing.T) {
+	instance := NewRepo(nil)
+	instance.addFileSize(file1, maxBytesPerBatch-100)
+	instance.addFileSize(file2, 50)
+	sourceFiles := []interfaces.SourceFile{
+		{RemoteName: file1, LocalName: file1},
+		{RemoteName: file2, LocalName: file2},
+		{RemoteName: file3, LocalName: file3},
+	}
+
+	result := instance.getBatches(sourceFiles)
+
+	// file3 was never downloaded, so it's estimated with the average size of file1 and file2
+	expected := []Batch{
+		{LocalFiles: []string{file1, file2}, RemoteFiles: []string{file1, file2}, Size: maxBytesPerBatch - 50},
+		{LocalFiles: []string{file3}, RemoteFiles: []string{file3}, Size: (maxBytesPerBatch - 50) / 2},
+	}
+	assert.Equal(t, expected, result)
+}
@@ -1,11 +1,11 @@
 package internal
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
@@ -23,13 +23,30 @@ import (
 	"github.com/checkmarxDev/ast-sast-export/internal/persistence/installation"
 
 	"github.com/checkmarxDev/ast-sast-export/internal/app/astquery"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/customfieldmapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/customquery"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/databundle"
 	export2 "github.com/checkmarxDev/ast-sast-export/internal/app/export"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/fetch"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/interfaces"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/lineage"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/metadata"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/permissions"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/preset"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/projectsettings"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/querymapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryoverride"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryresolution"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/redaction"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/report"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/roletranslation"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/scanhistory"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/statemapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/systemsettings"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/teammapping"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/triagehistory"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/usermapping"
 	"github.com/checkmarxDev/ast-sast-export/internal/app/worker"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/rest"
 	"github.com/checkmarxDev/ast-sast-export/internal/integration/similarity"
@@ -42,14 +59,17 @@ import (
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-
-	"github.com/checkmarxDev/ast-sast-export/internal/app/common"
 )
 
 const (
 	scansFileName           = "%d.xml"
 	scansMetadataFileName   = "%d.json"
+	triageHistoryFileName   = "%d.json"
+	projectSettingsFileName = "%d.json"
+	scanHistoryFileName     = "%d.json"
+	branchTriageFileName    = "%d.json"
 	resultsPageLimit        = 10000
 	httpRetryWaitMin        = 1 * time.Second //nolint:revive
 	httpRetryWaitMax        = 30 * time.Second
@@ -77,27 +97,110 @@ func RunExport(args *Args) error {
 	log.Debug().
 		Str("url", args.URL).
 		Str("export", fmt.Sprintf("%v", args.Export)).
-		Str("queryMapping", args.QueryMappingFile).
+		Strs("queryMapping", args.QueryMappingFiles).
 		Str("queryRenaming", args.QueryRenamingFile).
+		Strs("queryMappingSha256", args.QueryMappingSHA256s).
+		Bool("strictMapping", args.StrictMapping).
+		Bool("branchTriageOnce", args.BranchTriageOnce).
+		Str("queryRenamingSha256", args.QueryRenamingSHA256).
+		Str("signatureKey", args.SignatureKeyFile).
+		Bool("offline", args.Offline).
+		Str("dataBundle", args.DataBundleFile).
 		Int("projectsActiveSince", args.ProjectsActiveSince).
 		Str("projectId", args.ProjectsIDs).
 		Str("projectTeam", args.TeamName).
 		Bool("nestedTeams", args.NestedTeams).
+		Str("teamMapping", args.TeamMappingFile).
+		Str("userMapping", args.UserMappingFile).
+		Str("roleTranslation", args.RoleTranslationFile).
+		Str("presetCatalog", args.PresetCatalogFile).
+		Str("stateMapping", args.StateMappingFile).
+		Str("customFieldMapping", args.CustomFieldMappingFile).
+		Str("redactionRules", args.RedactionRulesFile).
 		Bool("debug", args.Debug).
 		Int("consumers", consumerCount).
 		Msg("starting export")
 
-	err := common.LoadRename(args.QueryRenamingFile)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize query rename mapping")
+	dataBundle, dataBundleErr := loadDataBundle(args.DataBundleFile, args.DataBundleKeyFile)
+	if dataBundleErr != nil {
+		return errors.Wrap(dataBundleErr, "could not load data bundle")
+	}
+	args.DataBundle = dataBundle
+	log.Info().Msgf("using %s", dataBundle.Origin)
+
+	if args.Offline {
+		if offlineErr := checkOffline(append([]string{args.QueryRenamingFile}, args.QueryMappingFiles...)...); offlineErr != nil {
+			return offlineErr
+		}
+	}
+
+	queryMappingOptions, queryMappingOptionsErr := getLayerFetchOptions(args.QueryMappingSHA256s, len(args.QueryMappingFiles),
+		args.SignatureKeyFile)
+	if queryMappingOptionsErr != nil {
+		return errors.Wrap(queryMappingOptionsErr, "could not check query mapping")
+	}
+	queryRenamingOptions, queryRenamingOptionsErr := getFetchOptions(args.QueryRenamingSHA256, args.SignatureKeyFile)
+	if queryRenamingOptionsErr != nil {
+		return errors.Wrap(queryRenamingOptionsErr, "could not load signature key")
 	}
 
 	retryHTTPClient := getRetryHTTPClient()
+	fetcher := newFetcher(retryHTTPClient)
+	queryIDResolver := queryid.NewResolver()
+	queryRenaming, queryRenamingErr := addQueryRenaming(queryIDResolver, fetcher, args.QueryRenamingFile, args.DataBundle,
+		queryRenamingOptions)
+	if queryRenamingErr != nil {
+		log.Fatal().Err(queryRenamingErr).Msg("Failed to initialize query rename mapping")
+	}
+
+	teamMapper, teamMapperErr := teammapping.Load(args.TeamMappingFile, args.NestedTeams)
+	if teamMapperErr != nil {
+		return errors.Wrap(teamMapperErr, "could not load team mapping")
+	}
+	args.TeamMapper = teamMapper
+
+	userMapper, userMapperErr := usermapping.Load(args.UserMappingFile)
+	if userMapperErr != nil {
+		return errors.Wrap(userMapperErr, "could not load user mapping")
+	}
+	args.UserMapper = userMapper
+
+	roleTranslator, roleTranslatorErr := roletranslation.Load(args.RoleTranslationFile)
+	if roleTranslatorErr != nil {
+		return errors.Wrap(roleTranslatorErr, "could not load role translation")
+	}
+	args.RoleTranslator = roleTranslator
+
+	customFieldMapper, customFieldMapperErr := customfieldmapping.Load(args.CustomFieldMappingFile)
+	if customFieldMapperErr != nil {
+		return errors.Wrap(customFieldMapperErr, "could not load custom field mapping")
+	}
+	args.CustomFieldMapper = customFieldMapper
+
+	redactor, redactorErr := redaction.Load(args.RedactionRulesFile)
+	if redactorErr != nil {
+		return errors.Wrap(redactorErr, "could not load redaction rules")
+	}
+	args.Redactor = redactor
+
+	stateMapper, stateMapperErr := statemapping.Load(args.StateMappingFile)
+	if stateMapperErr != nil {
+		return errors.Wrap(stateMapperErr, "could not load state mapping")
+	}
+	args.StateMapper = stateMapper
+
+	presetCatalog, presetCatalogErr := preset.LoadCatalog(args.PresetCatalogFile)
+	if presetCatalogErr != nil {
+		return errors.Wrap(presetCatalogErr, "could not load preset catalog")
+	}
+	args.PresetCatalog = presetCatalog
+
 	// create api client
 	client, clientErr := rest.NewSASTClient(args.URL, retryHTTPClient)
 	if clientErr != nil {
 		return errors.Wrap(clientErr, "could not create REST client")
 	}
+	client.EngineConfigMapping = args.DataBundle.GetFile(databundle.EngineConfigMappingFileName)
 
 	// authenticate
 	log.Info().Msg("connecting to SAST")
@@ -149,17 +252,28 @@ func RunExport(args *Args) error {
 		return errors.Wrap(fetchInstallationErr, "could not fetch installation data")
 	}
 
-	astQueryMappingProvider, astQueryMappingProviderErr := querymapping.NewProvider(args.QueryMappingFile, retryHTTPClient)
+	astQueryMappingProvider, queryMappings, astQueryMappingProviderErr := loadQueryMappings(
+		fetcher, args.QueryMappingFiles, args.DataBundle, queryMappingOptions, queryIDResolver)
 	if astQueryMappingProviderErr != nil {
 		return errors.Wrap(astQueryMappingProviderErr, "could not create AST query mapping provider")
 	}
+	args.QueryMappingConflicts = astQueryMappingProvider.GetConflicts()
 
-	astQueryProvider, astQueryProviderErr := astquery.NewProvider(queriesRepo, astQueryMappingProvider)
+	astQueryProvider, astQueryProviderErr := astquery.NewProvider(queriesRepo, queryIDResolver)
 	if astQueryProviderErr != nil {
 		return errors.Wrap(astQueryProviderErr, "could not create AST query provider")
 	}
 
-	presetProvider := preset.NewProvider(presetRepo)
+	presetProvider := preset.NewProvider(presetRepo, astQueryProvider)
+
+	options := sliceutils.ConvertStringToInterface(args.Export)
+	if sliceutils.Contains(export2.ResultsOption, options) || sliceutils.Contains(export2.CustomStatesOption, options) {
+		stateTranslation, stateTranslationErr := addStateMappingFile(astQueryProvider, args.StateMapper, &exportValues)
+		if stateTranslationErr != nil {
+			return errors.Wrap(stateTranslationErr, "could not translate result states")
+		}
+		args.StateTranslation = stateTranslation
+	}
 
 	similarityIDCalculator, similarityIDCalculatorErr := similarity.NewSimilarityIDCalculator()
 	if similarityIDCalculatorErr != nil {
@@ -187,6 +301,7 @@ func RunExport(args *Args) error {
 		args.ExcludeFile,
 		args.CustomExtensions,
 	)
+	defer metadataSource.Close()
 
 	addErr := addCustomQueryIDs(astQueryProvider, astQueryMappingProvider)
 	if addErr != nil {
@@ -198,12 +313,36 @@ func RunExport(args *Args) error {
 		log.Error().Err(addFileErr).Msg("error adding query mapping file")
 	}
 
+	if conflictsErr := addQueryMappingConflictsFile(astQueryMappingProvider, &exportValues); conflictsErr != nil {
+		log.Error().Err(conflictsErr).Msg("error adding query mapping conflicts file")
+	}
+
+	dataSources := make([]DataSource, 0, len(queryMappings)+2)
+	for _, queryMapping := range queryMappings {
+		dataSources = append(dataSources, newDataSource(databundle.QueryMappingFileName, queryMapping))
+	}
+	dataSources = append(dataSources,
+		newDataSource(databundle.QueryRenamingFileName, queryRenaming),
+		newDataSource(databundle.EngineConfigMappingFileName, fetch.NewResource(args.DataBundle.Origin,
+			args.DataBundle.GetFile(databundle.EngineConfigMappingFileName))),
+	)
+	if dataSourcesErr := exportValues.AddFileWithDataSource(export2.DataSourcesFileName,
+		export2.NewJSONDataSource(dataSources)); dataSourcesErr != nil {
+		log.Error().Err(dataSourcesErr).Msg("error adding data sources file")
+	}
+
 	fetchErr := fetchSelectedData(client, &exportValues, args, scanReportCreateAttempts, scanReportCreateMinSleep,
 		scanReportCreateMaxSleep, metadataSource, astQueryProvider, presetProvider)
-	if fetchErr != nil {
+	var conflictErr *querymapping.ConflictError
+	isConflictErr := errors.As(fetchErr, &conflictErr)
+	if fetchErr != nil && !isConflictErr {
 		log.Error().Err(fetchErr).Msg("error fetching selected data")
 	}
 
+	if unmappedTeamsErr := addUnmappedTeamsFile(args.TeamMapper, &exportValues); unmappedTeamsErr != nil {
+		log.Error().Err(unmappedTeamsErr).Msg("error adding unmapped teams file")
+	}
+
 	// export data to file
 	log.Info().Msg("exporting collected data")
 	exportFileName, exportErr := exportResultsToFile(args, &exportValues)
@@ -211,6 +350,12 @@ func RunExport(args *Args) error {
 		log.Error().Err(exportErr).Msg("error exporting collected data")
 	}
 
+	if isConflictErr {
+		// the package is still written, so the conflicts can be reviewed without downloading every report again
+		log.Error().Msgf("export written to %s for review, but it can't be imported as query mapping conflicts affect triage",
+			exportFileName)
+		return fetchErr
+	}
 	log.Info().Msgf("export completed to %s", exportFileName)
 	return nil
 }
@@ -261,13 +406,23 @@ func fetchSelectedData(client rest.Client, exporter export2.Exporter, args *Args
 ) error {
 	var errProjects error
 	var projects []*rest.Project
+	// conflictErr fails the export once everything else is collected, so the package can still be written for review
+	var conflictErr *querymapping.ConflictError
+	// the lineage of branched projects is only resolved to export their triage once
+	var lineages *lineageFetcher
+	if args.BranchTriageOnce {
+		lineages = newLineageFetcher(client)
+	}
 	options := sliceutils.ConvertStringToInterface(args.Export)
 	if sliceutils.Contains(export2.ProjectsOption, options) {
 		projects, errProjects = fetchProjectsData(client, exporter, args.ProjectsActiveSince, args.TeamName, args.ProjectsIDs,
-			args.IsDefaultProjectActiveSince)
+			args.IsDefaultProjectActiveSince, getTransformOptions(args), lineages)
 		if errProjects != nil {
 			return errProjects
 		}
+		if err := addCustomFieldsFile(client, exporter, args.CustomFieldMapper); err != nil {
+			return err
+		}
 	}
 	for _, exportOption := range export2.GetOptions() {
 		if sliceutils.Contains(exportOption, options) {
@@ -281,11 +436,18 @@ func fetchSelectedData(client rest.Client, exporter export2.Exporter, args *Args
 					return err
 				}
 			case export2.QueriesOption:
-				if err := fetchQueriesData(astQueryProvider, exporter); err != nil {
+				var teams []*rest.Team
+				if len(projects) > 0 {
+					var teamsErr error
+					if teams, teamsErr = client.GetTeams(); teamsErr != nil {
+						return errors.Wrap(teamsErr, "failed getting teams")
+					}
+				}
+				if err := fetchQueriesData(astQueryProvider, exporter, projects, teams); err != nil {
 					return err
 				}
 			case export2.PresetsOption:
-				if err := fetchPresetsData(client, presetProvider, exporter, projects, args.ProjectsIDs); err != nil {
+				if err := fetchPresetsData(client, presetProvider, exporter, projects, args.ProjectsIDs, args.PresetCatalog); err != nil {
 					return err
 				}
 			case export2.FiltersOption:
@@ -297,17 +459,52 @@ func fetchSelectedData(client rest.Client, exporter export2.Exporter, args *Args
 					return err
 				}
 			case export2.ResultsOption:
-				if err := fetchResultsData(client, astQueryProvider, exporter, args.ProjectsActiveSince, retryAttempts, retryMinSleep,
-					retryMaxSleep, metadataProvider, args.TeamName, args.ProjectsIDs, args); err != nil {
+				err := fetchResultsData(client, astQueryProvider, exporter, args.ProjectsActiveSince, retryAttempts, retryMinSleep,
+					retryMaxSleep, metadataProvider, args.TeamName, args.ProjectsIDs, args, lineages)
+				if err != nil && !errors.As(err, &conflictErr) {
 					return err
 				}
 			case export2.CustomStatesOption:
 				if err := fetchCustomStateData(astQueryProvider, exporter); err != nil {
 					return err
 				}
+			case export2.ProjectSettingsOption:
+				settingsProjects := projects
+				if !sliceutils.Contains(export2.ProjectsOption, options) {
+					var windowErr error
+					if settingsProjects, windowErr = getProjectsInWindow(client, args); windowErr != nil {
+						return windowErr
+					}
+				}
+				if err := fetchProjectSettingsData(client, exporter, settingsProjects, getRedactor(args)); err != nil {
+					return err
+				}
+			case export2.ScansOption:
+				if err := fetchScansData(client, exporter, args); err != nil {
+					return err
+				}
+			case export2.SystemOption:
+				if err := fetchSystemData(client, exporter, getRedactor(args)); err != nil {
+					return err
+				}
 			}
 		}
 	}
+	// users and teams share the identity providers, so they are exported once
+	if sliceutils.Contains(export2.UsersOption, options) || sliceutils.Contains(export2.TeamsOption, options) {
+		if err := addIdentityProvidersFiles(client, exporter, getRedactor(args)); err != nil {
+			return err
+		}
+	}
+	if sliceutils.Contains(export2.UsersOption, options) || sliceutils.Contains(export2.TeamsOption, options) ||
+		sliceutils.Contains(export2.SystemOption, options) || sliceutils.Contains(export2.ProjectSettingsOption, options) {
+		if err := addRedactionsFile(exporter, getRedactor(args)); err != nil {
+			return err
+		}
+	}
+	if conflictErr != nil {
+		return conflictErr
+	}
 	return nil
 }
 
@@ -322,12 +519,24 @@ func fetchUsersData(client rest.Client, exporter export2.Exporter, args *Args) e
 		return errors.Wrap(teamsErr, "failed getting teams")
 	}
 	usersDataSource := export2.NewJSONDataSource(
-		export2.TransformUsers(users, teams, export2.TransformOptions{NestedTeams: args.NestedTeams}),
+		export2.TransformUsers(users, teams, getTransformOptions(args)),
 	)
 	if err := exporter.AddFileWithDataSource(export2.UsersFileName, usersDataSource); err != nil {
 		return err
 	}
-	if err := exporter.AddFileWithDataSource(export2.RolesFileName, client.GetRoles); err != nil {
+	if args.UserMapper != nil && (args.UserMappingFile != "" || args.UserMapper.HasMerges()) {
+		userMappingReport := resultsmapping.WriteAllToSanitizedCsv(args.UserMapper.GenerateReportCSV())
+		if err := exporter.AddFile(export2.UserMappingReportFileName, userMappingReport); err != nil {
+			return err
+		}
+	}
+	var roles []byte
+	rolesDataSource := func() ([]byte, error) {
+		var rolesErr error
+		roles, rolesErr = client.GetRoles()
+		return roles, rolesErr
+	}
+	if err := exporter.AddFileWithDataSource(export2.RolesFileName, rolesDataSource); err != nil {
 		return err
 	}
 	if err := exporter.AddFileWithDataSource(export2.LdapRoleMappingsFileName, client.GetLdapRoleMappings); err != nil {
@@ -336,26 +545,86 @@ func fetchUsersData(client rest.Client, exporter export2.Exporter, args *Args) e
 	if err := exporter.AddFileWithDataSource(export2.SamlRoleMappingsFileName, client.GetSamlRoleMappings); err != nil {
 		return err
 	}
-	if _, fileErr := os.Stat(export2.LdapServersFileName); os.IsNotExist(fileErr) {
-		if err := exporter.AddFileWithDataSource(export2.LdapServersFileName, client.GetLdapServers); err != nil {
-			return err
-		}
-	}
-	if _, fileErr := os.Stat(export2.SamlIdpFileName); os.IsNotExist(fileErr) {
-		if err := exporter.AddFileWithDataSource(export2.SamlIdpFileName, client.GetSamlIdentityProviders); err != nil {
-			return err
-		}
+	if args.RoleTranslator != nil {
+		return addRoleTranslationFile(client, exporter, args.RoleTranslator, roles)
 	}
 	return nil
 }
 
+// addIdentityProvidersFiles exports the LDAP servers and SAML identity providers with their credentials redacted
+func addIdentityProvidersFiles(client rest.Client, exporter export2.Exporter, redactor *redaction.Redactor) error {
+	ldapServers, ldapServersErr := client.GetLdapServers()
+	if ldapServersErr != nil {
+		return errors.Wrap(ldapServersErr, "failed getting ldap servers")
+	}
+	if err := addRedactedFile(exporter, redactor, export2.LdapServersFileName, ldapServers); err != nil {
+		return err
+	}
+	identityProviders, identityProvidersErr := client.GetSamlIdentityProviders()
+	if identityProvidersErr != nil {
+		return errors.Wrap(identityProvidersErr, "failed getting saml identity providers")
+	}
+	return addRedactedFile(exporter, redactor, export2.SamlIdpFileName, identityProviders)
+}
+
+// addRedactedFile exports data as JSON after applying the redaction rules
+func addRedactedFile(exporter export2.Exporter, redactor *redaction.Redactor, fileName string, data interface{}) error {
+	content, marshalErr := json.Marshal(data)
+	if marshalErr != nil {
+		return errors.Wrapf(marshalErr, "could not marshal %s", fileName)
+	}
+	redactedContent, redactErr := redactor.Redact(fileName, content)
+	if redactErr != nil {
+		return redactErr
+	}
+	return exporter.AddFile(fileName, redactedContent)
+}
+
+// addRedactionsFile exports the fields redacted in the package, which have to be re-entered after importing it
+func addRedactionsFile(exporter export2.Exporter, redactor *redaction.Redactor) error {
+	redactions := redactor.Redactions()
+	if len(redactions) > 0 {
+		log.Warn().Int("fields", len(redactions)).Msgf("redacted fields are listed in %s", export2.RedactionsFileName)
+	}
+	return exporter.AddFileWithDataSource(export2.RedactionsFileName, export2.NewJSONDataSource(redactions))
+}
+
+func getRedactor(args *Args) *redaction.Redactor {
+	if args.Redactor == nil {
+		args.Redactor = redaction.NewRedactor(nil)
+	}
+	return args.Redactor
+}
+
+// addRoleTranslationFile adds the translation of roles to CxOne, so admins can review permissions without equivalent.
+// The translation is only a review aid, so it's skipped if roles or permissions can't be read.
+func addRoleTranslationFile(client rest.Client, exporter export2.Exporter, roleTranslator *roletranslation.Translator,
+	rolesData []byte,
+) error {
+	roles, rolesErr := roletranslation.ParseRoles(rolesData)
+	if rolesErr != nil {
+		log.Warn().Err(rolesErr).Msg("could not parse roles, role translation is not exported")
+		return nil
+	}
+	permissions, permissionsErr := client.GetPermissions()
+	if permissionsErr != nil {
+		log.Warn().Err(permissionsErr).Msg("could not get permissions, role translation is not exported")
+		return nil
+	}
+	translation := roleTranslator.Translate(roles, permissions)
+	if len(translation.UnmappedPermissions) > 0 {
+		log.Warn().Strs("permissions", translation.UnmappedPermissions).Msg("permissions without CxOne equivalent")
+	}
+	return exporter.AddFileWithDataSource(export2.RoleTranslationFileName, export2.NewJSONDataSource(translation))
+}
+
 func fetchTeamsData(client rest.Client, exporter export2.Exporter, args *Args) error {
 	log.Info().Msg("collecting teams")
 	teams, teamsErr := client.GetTeams()
 	if teamsErr != nil {
 		return errors.Wrap(teamsErr, "failed getting teams")
 	}
-	transformOptions := export2.TransformOptions{NestedTeams: args.NestedTeams}
+	transformOptions := getTransformOptions(args)
 	teamsDataSource := export2.NewJSONDataSource(export2.TransformTeams(teams, transformOptions))
 	if err := exporter.AddFileWithDataSource(export2.TeamsFileName, teamsDataSource); err != nil {
 		return err
@@ -367,25 +636,14 @@ func fetchTeamsData(client rest.Client, exporter export2.Exporter, args *Args) e
 	if samlTeamMappingsErr != nil {
 		return errors.Wrap(samlTeamMappingsErr, "failed getting saml team mappings")
 	}
-	samlTeamMappingsDataSource := export2.NewJSONDataSource(export2.TransformSamlTeamMappings(samlTeamMappings, transformOptions))
-	if err := exporter.AddFileWithDataSource(export2.SamlTeamMappingsFileName, samlTeamMappingsDataSource); err != nil {
-		return err
-	}
-	if _, fileErr := os.Stat(export2.LdapServersFileName); os.IsNotExist(fileErr) {
-		if err := exporter.AddFileWithDataSource(export2.LdapServersFileName, client.GetLdapServers); err != nil {
-			return err
-		}
-	}
-	if _, fileErr := os.Stat(export2.SamlIdpFileName); os.IsNotExist(fileErr) {
-		if err := exporter.AddFileWithDataSource(export2.SamlIdpFileName, client.GetSamlIdentityProviders); err != nil {
-			return err
-		}
-	}
-	return nil
+	samlTeamMappingsDataSource := export2.NewJSONDataSource(export2.TransformSamlTeamMappings(samlTeamMappings, teams, transformOptions))
+	return exporter.AddFileWithDataSource(export2.SamlTeamMappingsFileName, samlTeamMappingsDataSource)
 }
 
 func fetchProjectsData(client rest.Client, exporter export2.Exporter, resultsProjectActiveSince int,
-	teamName, projectsIDs string, isDefaultProjectActiveSince bool) ([]*rest.Project, error) {
+	teamName, projectsIDs string, isDefaultProjectActiveSince bool, transformOptions export2.TransformOptions,
+	lineages *lineageFetcher,
+) ([]*rest.Project, error) {
 	log.Info().Msg("collecting projects")
 	projects := []*rest.Project{}
 	projectOffset := 0
@@ -412,6 +670,27 @@ func fetchProjectsData(client rest.Client, exporter export2.Exporter, resultsPro
 		// prepare to fetch next page
 		projectOffset += projectLimit
 	}
+	export2.TagProjects(projects, transformOptions)
+	if lineages != nil {
+		projectIDs := make([]int, 0, len(projects))
+		for _, project := range projects {
+			projectIDs = append(projectIDs, project.ID)
+		}
+		projectLineage := lineages.get(projectIDs)
+		for _, project := range projects {
+			if branch, ok := projectLineage.GetBranch(project.ID); ok {
+				project.OriginalProjectID = branch.OriginalProjectID
+				project.BranchedOnScanID = branch.BranchedOnScanID
+			}
+		}
+	}
+	if transformOptions.TeamMapper != nil && transformOptions.TeamMapper.HasRules() {
+		teams, teamsErr := client.GetTeams()
+		if teamsErr != nil {
+			return nil, errors.Wrap(teamsErr, "failed getting teams")
+		}
+		projects = export2.TransformProjects(projects, teams, transformOptions)
+	}
 	if err := exporter.AddFileWithDataSource(export2.ProjectsFileName,
 		export2.NewJSONDataSource(projects)); err != nil {
 		return nil, err
@@ -419,6 +698,190 @@ func fetchProjectsData(client rest.Client, exporter export2.Exporter, resultsPro
 	return projects, nil
 }
 
+// fetchScansData exports the scans of each project completed in the project date window
+func fetchScansData(client rest.Client, exporter export2.Exporter, args *Args) error {
+	log.Info().Msg("collecting scans")
+	fromDate := getDateFrom(args.ProjectsActiveSince, args.IsDefaultProjectActiveSince, args.ProjectsIDs)
+	var scans []*rest.Scan
+	for offset := 0; ; offset += resultsPageLimit {
+		log.Debug().
+			Str("fromDate", fromDate).
+			Int("offset", offset).
+			Int("limit", resultsPageLimit).
+			Msg("fetching scans")
+		page, err := client.GetScans(fromDate, args.TeamName, args.ProjectsIDs, offset, resultsPageLimit)
+		if err != nil {
+			return errors.Wrap(err, "failed getting scans")
+		}
+		scans = append(scans, page...)
+		if len(page) < resultsPageLimit {
+			break
+		}
+	}
+	if err := exporter.CreateDir(export2.ScanHistoryDirName); err != nil {
+		return err
+	}
+	projects := scanhistory.GroupByProject(scans)
+	for _, project := range projects {
+		fileName := path.Join(export2.ScanHistoryDirName, fmt.Sprintf(scanHistoryFileName, project.ProjectID))
+		if err := exporter.AddFileWithDataSource(fileName, export2.NewJSONDataSource(project)); err != nil {
+			return err
+		}
+	}
+	log.Info().Int("scans", len(scans)).Int("projects", len(projects)).Msg("collected scans")
+	return nil
+}
+
+// fetchProjectSettingsData exports the settings of each project with their secrets redacted,
+// settings SAST doesn't return for a project are skipped
+func fetchProjectSettingsData(client rest.Client, exporter export2.Exporter, projects []*rest.Project,
+	redactor *redaction.Redactor,
+) error {
+	log.Info().Msg("collecting project settings")
+	if err := exporter.CreateDir(export2.ProjectSettingsDirName); err != nil {
+		return err
+	}
+	for _, project := range projects {
+		settings := projectsettings.New(project.ID, getProjectSettingsSources(client, project.ID))
+		fileName := path.Join(export2.ProjectSettingsDirName, fmt.Sprintf(projectSettingsFileName, project.ID))
+		if err := addRedactedFile(exporter, redactor, fileName, settings); err != nil {
+			return err
+		}
+	}
+	log.Info().Int("projects", len(projects)).Msg("collected project settings")
+	return nil
+}
+
+// getProjectsInWindow returns the projects scanned in the project date window, for exports that need projects
+// when the projects themselves aren't exported
+func getProjectsInWindow(client rest.Client, args *Args) ([]*rest.Project, error) {
+	fromDate := getDateFrom(args.ProjectsActiveSince, args.IsDefaultProjectActiveSince, args.ProjectsIDs)
+	var projects []*rest.Project
+	for offset := 0; ; offset += resultsPageLimit {
+		page, err := client.GetProjectsWithLastScanID(fromDate, args.TeamName, args.ProjectsIDs, offset, resultsPageLimit)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed getting projects")
+		}
+		if len(*page) == 0 {
+			return projects, nil
+		}
+		for _, project := range *page {
+			projects = append(projects, &rest.Project{ID: project.ID})
+		}
+	}
+}
+
+func getProjectSettingsSources(client rest.Client, projectID int) projectsettings.Sources {
+	l := log.With().Int("projectID", projectID).Logger()
+	var sources projectsettings.Sources
+	var err error
+	if sources.Git, err = client.GetProjectGitSettings(projectID); err != nil {
+		l.Debug().Err(err).Msg("project has no git settings")
+	}
+	if sources.ScanSettings, err = client.GetProjectScanSettings(projectID); err != nil {
+		l.Warn().Err(err).Msg("could not get project scan settings")
+	}
+	if sources.Scheduling, err = client.GetProjectScheduling(projectID); err != nil {
+		l.Warn().Err(err).Msg("could not get project scan schedule")
+	}
+	if sources.IssueTracking, err = client.GetProjectIssueTrackingSettings(projectID); err != nil {
+		l.Warn().Err(err).Msg("could not get project issue tracking settings")
+	}
+	if sources.DataRetention, err = client.GetProjectDataRetentionSettings(projectID); err != nil {
+		l.Warn().Err(err).Msg("could not get project data retention settings")
+	}
+	return sources
+}
+
+// fetchSystemData exports the system-wide settings with their secrets redacted, settings SAST doesn't return are skipped
+func fetchSystemData(client rest.Client, exporter export2.Exporter, redactor *redaction.Redactor) error {
+	log.Info().Msg("collecting system settings")
+	settings := systemsettings.New(getSystemSettingsSources(client))
+	if err := addRedactedFile(exporter, redactor, export2.SystemSettingsFileName, settings); err != nil {
+		return err
+	}
+	log.Info().Msg("collected system settings")
+	return nil
+}
+
+func getSystemSettingsSources(client rest.Client) systemsettings.Sources {
+	var sources systemsettings.Sources
+	var err error
+	if sources.SMTP, err = client.GetSMTPSettings(); err != nil {
+		log.Warn().Err(err).Msg("could not get smtp settings")
+	}
+	if sources.EngineServers, err = client.GetEngineServers(); err != nil {
+		log.Warn().Err(err).Msg("could not get engine servers")
+	}
+	if sources.DataRetention, err = client.GetDataRetentionSettings(); err != nil {
+		log.Warn().Err(err).Msg("could not get data retention settings")
+	}
+	if sources.AuthenticationProviders, err = client.GetAuthenticationProviders(); err != nil {
+		log.Warn().Err(err).Msg("could not get authentication providers")
+	}
+	if sources.ResultSeverityLevels, err = client.GetResultSeverityLevels(); err != nil {
+		log.Warn().Err(err).Msg("could not get result severity levels")
+	}
+	if sources.ScanQueue, err = client.GetScanQueueSettings(); err != nil {
+		log.Warn().Err(err).Msg("could not get scan queue settings")
+	}
+	return sources
+}
+
+// addCustomFieldsFile exports the custom field definitions and the project tags each field is exported as.
+// Custom fields only document the project tags, so the file is skipped if they can't be fetched.
+func addCustomFieldsFile(client rest.Client, exporter export2.Exporter, mapper *customfieldmapping.Mapper) error {
+	customFields, err := client.GetCustomFields()
+	if err != nil {
+		log.Warn().Err(err).Msg("could not get custom fields, custom field definitions are not exported")
+		return nil
+	}
+	if mapper == nil {
+		mapper = customfieldmapping.NewMapper(nil)
+	}
+	return exporter.AddFileWithDataSource(export2.CustomFieldsFileName, export2.NewJSONDataSource(mapper.GetDefinitions(customFields)))
+}
+
+// lineageFetcher finds which projects are branched projects, and the projects they were branched from up to their root.
+// The branch of each project is requested once per export, so the projects and results exports share it.
+type lineageFetcher struct {
+	client   rest.Client
+	branches []lineage.Branch
+	checked  map[int]bool
+}
+
+func newLineageFetcher(client rest.Client) *lineageFetcher {
+	return &lineageFetcher{client: client, checked: map[int]bool{}}
+}
+
+// get returns the lineage of the projects, a project whose branch can't be requested is exported as not branched
+func (f *lineageFetcher) get(projectIDs []int) *lineage.Lineage {
+	pending := append([]int{}, projectIDs...)
+	for len(pending) > 0 {
+		projectID := pending[0]
+		pending = pending[1:]
+		if f.checked[projectID] {
+			continue
+		}
+		f.checked[projectID] = true
+		branch, err := f.client.GetProjectBranch(projectID)
+		if err != nil {
+			log.Warn().Err(err).Int("projectID", projectID).Msg("could not get project branch, the project is exported as not branched")
+			continue
+		}
+		if branch == nil || branch.OriginalProjectID == 0 {
+			continue
+		}
+		f.branches = append(f.branches, lineage.Branch{
+			ProjectID:         projectID,
+			OriginalProjectID: branch.OriginalProjectID,
+			BranchedOnScanID:  branch.BranchedOnScanID,
+		})
+		pending = append(pending, branch.OriginalProjectID)
+	}
+	return lineage.New(f.branches)
+}
+
 func fetchInstallationData(restClient rest.Client, soapClient interfaces.InstallationProvider, exporter export2.Exporter) error {
 	log.Info().Msg("collecting installation details")
 	installationResp, errInstallations := soapClient.GetInstallationSettings()
@@ -440,7 +903,9 @@ func fetchInstallationData(restClient rest.Client, soapClient interfaces.Install
 	return exporter.AddFileWithDataSource(export2.InstallationFileName, installationMappingsDataSource)
 }
 
-func fetchQueriesData(client interfaces.ASTQueryProvider, exporter export2.Exporter) error {
+func fetchQueriesData(client interfaces.ASTQueryProvider, exporter export2.Exporter, projects []*rest.Project,
+	teams []*rest.Team,
+) error {
 	log.Info().Msg("collecting custom queries")
 	queryResp, err := client.GetCustomQueriesList()
 	if err != nil {
@@ -456,6 +921,14 @@ func fetchQueriesData(client interfaces.ASTQueryProvider, exporter export2.Expor
 		return errors.Wrap(errExp, "error with exporting custom queries list to file")
 	}
 
+	if sourcesErr := addCustomQuerySourceFiles(queryResp, exporter); sourcesErr != nil {
+		return sourcesErr
+	}
+
+	if overridesErr := addQueryOverridesFile(client, queryResp, projects, teams, exporter); overridesErr != nil {
+		return overridesErr
+	}
+
 	customStateErr := fetchCustomStateData(client, exporter)
 	if customStateErr != nil {
 		return customStateErr
@@ -464,6 +937,94 @@ func fetchQueriesData(client interfaces.ASTQueryProvider, exporter export2.Expor
 	return nil
 }
 
+// addCustomQuerySourceFiles exports the source code of each custom query as a file and an index of the queries
+func addCustomQuerySourceFiles(queryResp *soap.GetQueryCollectionResponse, exporter export2.Exporter) error {
+	queries := customquery.GetQueries(queryResp.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup, export2.QueriesDirName)
+	for _, dir := range customquery.GetDirs(queries) {
+		if err := exporter.CreateDir(dir); err != nil {
+			return errors.Wrap(err, "error with creating custom queries folder")
+		}
+	}
+	for _, query := range queries {
+		if query.IsEncrypted {
+			log.Warn().
+				Str("sastQueryId", query.SastQueryID).
+				Str("package", query.PackageFullName).
+				Msgf("custom query %s is encrypted, its source code is not exported", query.Name)
+			continue
+		}
+		if err := exporter.AddFile(query.File, []byte(query.Source)); err != nil {
+			return errors.Wrap(err, "error with exporting custom query source to file")
+		}
+	}
+	if err := exporter.AddFileWithDataSource(export2.QueriesIndexFileName, export2.NewJSONDataSource(queries)); err != nil {
+		return errors.Wrap(err, "error with exporting custom queries index to file")
+	}
+	return nil
+}
+
+// addQueryOverridesFile exports which custom query override applies to which team and project,
+// and warns about the overrides CxOne can't represent
+func addQueryOverridesFile(client interfaces.ASTQueryProvider, queryResp *soap.GetQueryCollectionResponse,
+	projects []*rest.Project, teams []*rest.Team, exporter export2.Exporter,
+) error {
+	projectTeams := make(map[int]int, len(projects))
+	for _, project := range projects {
+		projectTeams[project.ID] = project.TeamID
+	}
+	teamParents := make(map[int]int, len(teams))
+	for _, team := range teams {
+		teamParents[team.ID] = team.ParendID
+	}
+	hierarchy, err := queryoverride.NewHierarchy(queryResp.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup, projectTeams,
+		teamParents, client.GetQueryID)
+	if err != nil {
+		return errors.Wrap(err, "error with getting custom query overrides")
+	}
+	for _, collision := range hierarchy.Collisions {
+		log.Warn().
+			Str("language", collision.Language).
+			Str("group", collision.Group).
+			Str("name", collision.Name).
+			Strs("sastQueryIds", collision.SastQueryIDs).
+			Msgf("custom query override collision: %s", collision.Reason)
+	}
+	if errExp := exporter.AddFileWithDataSource(export2.QueryOverridesFileName, export2.NewJSONDataSource(hierarchy)); errExp != nil {
+		return errors.Wrap(errExp, "error with exporting custom query overrides to file")
+	}
+	return nil
+}
+
+// addStateMappingFile translates the SAST result states to CxOne states, exports the translation
+// and warns about the custom states no state mapping rule applies to.
+// Returns no translation if the states can't be fetched, so reports fall back to renumbering colliding states.
+func addStateMappingFile(astQueryProvider interfaces.ASTQueryProvider, stateMapper *statemapping.Mapper,
+	exporter export2.Exporter,
+) (*statemapping.Translation, error) {
+	statesResp, statesErr := astQueryProvider.GetRawCustomStatesList()
+	if statesErr != nil {
+		log.Error().Err(statesErr).Msg("could not get result states, states are not translated")
+		return nil, nil
+	}
+	renumbered, renumberedErr := astQueryProvider.GetStateMapping()
+	if renumberedErr != nil {
+		log.Error().Err(renumberedErr).Msg("could not get result state renumbering, states are not translated")
+		return nil, nil
+	}
+	translation, translateErr := stateMapper.Translate(statesResp.GetResultStateListResult.ResultStateList.ResultState, renumbered)
+	if translateErr != nil {
+		return nil, translateErr
+	}
+	if table := translation.FormatUnmapped(); table != "" {
+		log.Warn().Msgf("%d result states are not covered by the state mapping and are exported as custom states:\n%s",
+			len(translation.Unmapped), table)
+	}
+	if err := exporter.AddFileWithDataSource(export2.StateMappingFileName, export2.NewJSONDataSource(translation)); err != nil {
+		return nil, errors.Wrap(err, "error with exporting state mapping to file")
+	}
+	return translation, nil
+}
+
 func fetchCustomStateData(client interfaces.ASTQueryProvider, exporter export2.Exporter) error {
 	log.Info().Msg("collecting custom states")
 	customStateResp, err := client.GetCustomStatesList()
@@ -486,7 +1047,7 @@ func fetchPresetsData(
 	client rest.Client,
 	soapClient interfaces.PresetProvider,
 	exporter export2.Exporter,
-	projects []*rest.Project, projectsIDs string) error {
+	projects []*rest.Project, projectsIDs string, presetCatalog *preset.Catalog) error {
 	log.Info().Msg("collecting presets")
 	consumerCount := worker.GetNumCPU()
 	presetJobs := make(chan PresetJob)
@@ -519,10 +1080,12 @@ func fetchPresetsData(
 
 	presetConsumeErrorCount := 0
 	collectedPresets := []int{}
+	translatedPresets := []*soap.Preset{}
 	for i := 0; i < presetCount; i++ {
 		presetOutput := <-presetConsumeOutputs
 		if presetOutput.Err == nil {
 			collectedPresets = append(collectedPresets, presetOutput.PresetID)
+			translatedPresets = append(translatedPresets, presetOutput.Preset)
 		} else {
 			presetConsumeErrorCount++
 			log.Warn().
@@ -539,23 +1102,43 @@ func fetchPresetsData(
 		log.Warn().Msgf("failed collecting %d/%d presets", presetConsumeErrorCount, presetCount)
 	}
 
+	if presetCatalog != nil {
+		return exporter.AddFileWithDataSource(export2.PresetDiffFileName,
+			export2.NewJSONDataSource(presetCatalog.Diff(translatedPresets)))
+	}
 	return nil
 }
 
 //nolint:gocyclo,funlen
 func fetchResultsData(client rest.Client, astQueryProvider interfaces.ASTQueryProvider, exporter export2.Exporter,
 	resultsProjectActiveSince int, retryAttempts int, retryMinSleep, retryMaxSleep time.Duration,
-	metadataProvider metadata.Provider, teamName, projectsIDs string, args *Args,
+	metadataProvider metadata.Provider, teamName, projectsIDs string, args *Args, lineages *lineageFetcher,
 ) error {
 	consumerCount := worker.GetNumCPU()
 	reportJobs := make(chan ReportJob)
 
 	// Fetch the state mapping once, before starting the report consumers
-	stateMapping, err := astQueryProvider.GetStateMapping()
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to fetch state mapping for all reports")
-		// Default to an empty mapping to avoid nil pointer issues and allow processing to continue
-		stateMapping = make(map[string]string)
+	var stateMapping map[string]string
+	if args.StateTranslation != nil {
+		stateMapping = args.StateTranslation.GetStateMapping()
+	} else {
+		var err error
+		stateMapping, err = astQueryProvider.GetStateMapping()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to fetch state mapping for all reports")
+			// Default to an empty mapping to avoid nil pointer issues and allow processing to continue
+			stateMapping = make(map[string]string)
+		}
+	}
+
+	// Consolidate mapped users if they weren't exported, so reports refer to the same users as the users export would
+	if args.UserMapper != nil && args.UserMappingFile != "" && !args.UserMapper.IsConsolidated() {
+		users, usersErr := client.GetUsers()
+		if usersErr != nil {
+			log.Warn().Err(usersErr).Msg("could not get users, user references in reports are kept as they are")
+		} else {
+			args.UserMapper.Consolidate(users)
+		}
 	}
 
 	fromDate := getDateFrom(resultsProjectActiveSince, args.IsDefaultProjectActiveSince, projectsIDs)
@@ -569,11 +1152,112 @@ func fetchResultsData(client rest.Client, astQueryProvider interfaces.ASTQueryPr
 		Str("scans", fmt.Sprintf("%v", triagedScans)).
 		Msg("last scans by project")
 
+	// Branched projects whose lineage root is exported too only get the triage that differs from the root
+	projectLineage := lineage.New(nil)
+	branchesWithRoot := map[int]bool{}
+	if lineages != nil {
+		projectLineage, branchesWithRoot = getBranchesWithRoot(lineages, triagedScans)
+		if len(branchesWithRoot) > 0 {
+			if err := exporter.CreateDir(export2.BranchTriageDirName); err != nil {
+				return err
+			}
+		}
+	}
+
 	// create and fetch report for each scan
 	go produceReports(triagedScans, reportJobs)
 
 	reportCount := len(triagedScans)
 	reportConsumeOutputs := make(chan ReportConsumeOutput, reportCount)
+	queryResolution := queryresolution.NewReport(astQueryProvider)
+
+	triageHistoryOptions := triagehistory.Options{StateMapping: stateMapping}
+	if args.UserMapper != nil && args.UserMapper.IsConsolidated() {
+		triageHistoryOptions.GetUserName = args.UserMapper.GetUserName
+	}
+	triageHistoryDirErr := exporter.CreateDir(export2.TriageHistoryDirName)
+	if triageHistoryDirErr != nil {
+		log.Warn().Err(triageHistoryDirErr).Msg("could not create triage history folder, triage history is not exported")
+	}
+
+	addTriageHistory := func(reportJob ReportJob, l zerolog.Logger) {
+		if triageHistoryDirErr != nil {
+			return
+		}
+		if historyErr := addTriageHistoryFile(client, exporter, reportJob.ProjectID, reportJob.ScanID,
+			triageHistoryOptions); historyErr != nil {
+			l.Warn().Err(historyErr).Msg("failed saving triage history")
+		}
+	}
+
+	// consumeReport exports the report and metadata of a scan, the report is streamed into a temporary file first,
+	// so neither is added to the export if the metadata fails
+	consumeReport := func(reportJob ReportJob, l zerolog.Logger) ReportConsumeOutput {
+		output := ReportConsumeOutput{ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
+		reportFile, tmpFileErr := os.CreateTemp("", "scan-report-*.xml")
+		if tmpFileErr != nil {
+			output.Err = errors.Wrap(tmpFileErr, "could not create temporary report file")
+			return output
+		}
+		defer func() {
+			if closeErr := reportFile.Close(); closeErr != nil {
+				l.Debug().Err(closeErr).Msg("failed closing temporary report file")
+			}
+			if removeErr := os.Remove(reportFile.Name()); removeErr != nil {
+				l.Debug().Err(removeErr).Msg("failed removing temporary report file")
+			}
+		}()
+		reportReader, downloadErr := downloadScanReport(client, reportJob, reportFile, stateMapping, getTransformOptions(args),
+			retryAttempts, retryMinSleep, retryMaxSleep, l)
+		if downloadErr != nil {
+			l.Debug().Err(downloadErr).Msgf("failed creating scan report after %d attempts", retryAttempts)
+			output.Err = downloadErr
+			return output
+		}
+
+		metadataQueries := metadata.GetQueriesFromReport(reportReader)
+		queryResolution.Add(metadataQueries)
+		metadataRecord, metadataRecordErr := metadataProvider.GetMetadataRecord(reportReader.ScanID, metadataQueries)
+		if metadataRecordErr != nil {
+			l.Debug().Err(metadataRecordErr).Msg("failed creating metadata")
+			output.Err = metadataRecordErr
+			return output
+		}
+		metadataRecord.ProjectID = strconv.Itoa(reportJob.ProjectID)
+		if branchesWithRoot[reportJob.ProjectID] {
+			// the report is only read for the triage overrides, the report of the lineage root is exported instead
+			addTriageHistory(reportJob, l)
+			output.Record = metadataRecord
+			return output
+		}
+		metadataRecordJSON, metadataRecordJSONErr := json.Marshal(metadataRecord)
+		if metadataRecordJSONErr != nil {
+			l.Debug().Err(metadataRecordJSONErr).Msg("failed marshaling metadata")
+			output.Err = metadataRecordJSONErr
+			return output
+		}
+		copyReport := func(w io.Writer) error {
+			if _, seekErr := reportFile.Seek(0, io.SeekStart); seekErr != nil {
+				return seekErr
+			}
+			_, copyErr := io.Copy(w, reportFile)
+			return copyErr
+		}
+		if exportErr := exporter.AddFileWithWriter(fmt.Sprintf(scansFileName, reportJob.ProjectID), copyReport); exportErr != nil {
+			l.Debug().Err(exportErr).Msg("failed saving result")
+			output.Err = exportErr
+			return output
+		}
+		exportMetadataErr := exporter.AddFile(fmt.Sprintf(scansMetadataFileName, reportJob.ProjectID), metadataRecordJSON)
+		if exportMetadataErr != nil {
+			l.Debug().Err(exportMetadataErr).Msg("failed saving metadata")
+			output.Err = exportMetadataErr
+			return output
+		}
+		addTriageHistory(reportJob, l)
+		output.Record = metadataRecord
+		return output
+	}
 
 	// Define the report consumer function as a closure
 	consumeReportForWorker := func(currentWorkerID int) {
@@ -590,124 +1274,7 @@ func fetchResultsData(client rest.Client, astQueryProvider interfaces.ASTQueryPr
 				Int("worker", currentWorkerID).
 				Logger()
 
-			// Create scan report
-			var reportData []byte
-			var reportCreateErr error
-			retry := rest.Retry{ // This retry struct was locally defined in original consumeReports
-				Attempts: 10, // This is the attempts for client.CreateScanReport's internal retry mechanism
-				MinSleep: 1 * time.Second,
-				MaxSleep: 5 * time.Minute,
-			}
-			// The loop for retrying CreateScanReport uses retryAttempts from fetchResultsData
-			for i := 1; i <= retryAttempts; i++ {
-				reportData, reportCreateErr = client.CreateScanReport(reportJob.ScanID, reportJob.ReportType, retry)
-				if reportCreateErr != nil {
-					l.Debug().Err(reportCreateErr).
-						Int("attempt", i).
-						Msg("failed creating scan report")
-					time.Sleep(retryablehttp.DefaultBackoff(retryMinSleep, retryMaxSleep, i, nil))
-				} else {
-					break
-				}
-			}
-			if len(reportData) == 0 {
-				// scanReportCreateAttempts is a global constant used for the error message context
-				l.Debug().Err(reportCreateErr).Msgf("failed creating scan report after %d attempts", scanReportCreateAttempts)
-				reportConsumeOutputs <- ReportConsumeOutput{Err: reportCreateErr, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-				continue
-			}
-
-			// Generate metadata json
-			var reportReader report.CxXMLResults
-			unmarshalErr := xml.Unmarshal(reportData, &reportReader)
-			if unmarshalErr != nil {
-				l.Error().Err(unmarshalErr).Msg("Failed to unmarshal XML report data")
-				reportConsumeOutputs <- ReportConsumeOutput{Err: unmarshalErr, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-				continue
-			}
-
-			// Prepare data for transformation and export
-			dataToTransform := reportData // Default to original data
-			if len(stateMapping) > 0 {
-				statesToUpdateCount := 0
-				statesUpdated := false
-				for i := range reportReader.Queries {
-					query := &reportReader.Queries[i]
-					for j := range query.Results {
-						result := &query.Results[j]
-						if _, exists := stateMapping[result.State]; exists {
-							statesToUpdateCount++
-						}
-					}
-				}
-
-				if statesToUpdateCount > 0 {
-					l.Info().Msgf("Found %d results with states that will be updated based on the mapping", statesToUpdateCount)
-					for i := range reportReader.Queries {
-						query := &reportReader.Queries[i]
-						for j := range query.Results {
-							result := &query.Results[j]
-							if newStateID, exists := stateMapping[result.State]; exists {
-								l.Info().Msgf("Found result with state='%s' (NodeId: %s, FileName: %s, Line: %s), updating to state='%s'",
-									result.State, result.NodeID, result.FileName, result.Line, newStateID)
-								reportReader.Queries[i].Results[j].State = newStateID
-								statesUpdated = true
-							}
-						}
-					}
-
-					if statesUpdated {
-						var buf bytes.Buffer
-						buf.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
-`))
-						encoder := xml.NewEncoder(&buf)
-						encoder.Indent("", "  ")
-						if errEnc := encoder.Encode(&reportReader); errEnc != nil {
-							l.Debug().Err(errEnc).Msg("failed to encode modified report data into buffer")
-							reportConsumeOutputs <- ReportConsumeOutput{Err: errEnc, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-							continue
-						}
-						dataToTransform = buf.Bytes()
-					}
-				}
-			}
-
-			metadataQueries := metadata.GetQueriesFromReport(&reportReader)
-			metadataRecord, metadataRecordErr := metadataProvider.GetMetadataRecord(reportReader.ScanID, metadataQueries)
-			if metadataRecordErr != nil {
-				l.Debug().Err(metadataRecordErr).Msg("failed creating metadata")
-				reportConsumeOutputs <- ReportConsumeOutput{Err: metadataRecordErr, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-				continue
-			}
-			metadataRecordJSON, metadataRecordJSONErr := json.Marshal(metadataRecord)
-			if metadataRecordJSONErr != nil {
-				l.Debug().Err(metadataRecordJSONErr).Msg("failed marshaling metadata")
-				reportConsumeOutputs <- ReportConsumeOutput{Err: metadataRecordJSONErr, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-				continue
-			}
-			exportMetadataErr := exporter.AddFile(fmt.Sprintf(scansMetadataFileName, reportJob.ProjectID), metadataRecordJSON)
-			if exportMetadataErr != nil {
-				l.Debug().Err(exportMetadataErr).Msg("failed saving metadata")
-				reportConsumeOutputs <- ReportConsumeOutput{Err: exportMetadataErr, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-				continue
-			}
-
-			transformedReportData, transformErr := export2.TransformScanReport(
-				dataToTransform, export2.TransformOptions{NestedTeams: args.NestedTeams},
-			)
-			if transformErr != nil {
-				l.Debug().Err(transformErr).Msg("failed transforming report data")
-				reportConsumeOutputs <- ReportConsumeOutput{Err: transformErr, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID}
-				continue
-			}
-			exportErr := exporter.AddFile(fmt.Sprintf(scansFileName, reportJob.ProjectID), transformedReportData)
-			if exportErr != nil {
-				l.Debug().Err(exportErr).Msg("failed saving result")
-				reportConsumeOutputs <- ReportConsumeOutput{Err: exportErr, ProjectID: reportJob.ProjectID,
-					ScanID: reportJob.ScanID, Record: metadataRecord}
-			} else {
-				reportConsumeOutputs <- ReportConsumeOutput{Err: nil, ProjectID: reportJob.ProjectID, ScanID: reportJob.ScanID, Record: metadataRecord}
-			}
+			reportConsumeOutputs <- consumeReport(reportJob, l)
 		}
 	} // End of consumeReportForWorker closure
 
@@ -716,10 +1283,13 @@ func fetchResultsData(client rest.Client, astQueryProvider interfaces.ASTQueryPr
 	}
 
 	metadataRecord := make([]*metadata.Record, 0)
+	branchRecords := map[int]*metadata.Record{}
 	reportConsumeErrorCount := 0
 	for i := 0; i < reportCount; i++ {
 		consumeOutput := <-reportConsumeOutputs
-		if consumeOutput.Record != nil {
+		if consumeOutput.Record != nil && branchesWithRoot[consumeOutput.ProjectID] {
+			branchRecords[consumeOutput.ProjectID] = consumeOutput.Record
+		} else if consumeOutput.Record != nil {
 			metadataRecord = append(metadataRecord, consumeOutput.Record)
 		}
 		reportIndex := i + 1
@@ -744,6 +1314,18 @@ func fetchResultsData(client rest.Client, astQueryProvider interfaces.ASTQueryPr
 		log.Debug().Err(allResultsMappingErr).Msg("failed saving results mapping")
 	}
 
+	if branchTriageErr := addBranchTriageFiles(exporter, projectLineage, metadataRecord, branchRecords); branchTriageErr != nil {
+		log.Warn().Err(branchTriageErr).Msg("failed saving branch triage overrides")
+	}
+
+	if queryResolutionErr := addQueryResolutionFile(queryResolution, exporter); queryResolutionErr != nil {
+		log.Debug().Err(queryResolutionErr).Msg("failed saving query resolution")
+	}
+
+	if conflictsErr := checkQueryMappingConflicts(args.QueryMappingConflicts, queryResolution, args.StrictMapping); conflictsErr != nil {
+		return conflictsErr
+	}
+
 	if reportConsumeErrorCount > 0 {
 		log.Warn().Msgf("failed collecting %d/%d results", reportConsumeErrorCount, reportCount)
 	}
@@ -751,6 +1333,54 @@ func fetchResultsData(client rest.Client, astQueryProvider interfaces.ASTQueryPr
 	return nil
 }
 
+// downloadScanReport creates the report of a scan and streams it into file, remapping states and teams.
+// Reports failing while created or while read are created again, up to retryAttempts times.
+func downloadScanReport(client rest.Client, reportJob ReportJob, file *os.File, stateMapping map[string]string,
+	transformOptions export2.TransformOptions, retryAttempts int, retryMinSleep, retryMaxSleep time.Duration, l zerolog.Logger,
+) (*report.CxXMLResults, error) {
+	retry := rest.Retry{
+		Attempts: 10, // attempts of the report status polling of each report creation
+		MinSleep: 1 * time.Second,
+		MaxSleep: 5 * time.Minute,
+	}
+	var lastErr error
+	for i := 1; i <= retryAttempts; i++ {
+		if i > 1 {
+			time.Sleep(retryablehttp.DefaultBackoff(retryMinSleep, retryMaxSleep, i-1, nil))
+		}
+		reportStream, reportCreateErr := client.CreateScanReport(reportJob.ScanID, reportJob.ReportType, retry)
+		if reportCreateErr != nil {
+			l.Debug().Err(reportCreateErr).Int("attempt", i).Msg("failed creating scan report")
+			lastErr = reportCreateErr
+			continue
+		}
+		reportReader, transformErr := writeScanReport(reportStream, file, stateMapping, transformOptions)
+		if closeErr := reportStream.Close(); closeErr != nil {
+			l.Debug().Err(closeErr).Msg("failed closing scan report")
+		}
+		if transformErr != nil {
+			l.Debug().Err(transformErr).Int("attempt", i).Msg("failed reading scan report")
+			lastErr = transformErr
+			continue
+		}
+		return reportReader, nil
+	}
+	return nil, lastErr
+}
+
+// writeScanReport replaces the content of file with the transformed report
+func writeScanReport(reportStream io.Reader, file *os.File, stateMapping map[string]string,
+	transformOptions export2.TransformOptions,
+) (*report.CxXMLResults, error) {
+	if err := file.Truncate(0); err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return export2.TransformScanReport(reportStream, file, stateMapping, transformOptions)
+}
+
 // sortMetadataRecords sorts metadata records and their nested structures for deterministic output
 func sortMetadataRecords(records []*metadata.Record) {
 	// Sort records by the first query's first result's first path's ResultID (if available)
@@ -814,6 +1444,85 @@ func sortMetadataRecords(records []*metadata.Record) {
 	}
 }
 
+// addUnmappedTeamsFile reports the teams no team mapping rule applied to, if there are rules
+func addUnmappedTeamsFile(teamMapper *teammapping.Mapper, exporter export2.Exporter) error {
+	if teamMapper == nil || !teamMapper.HasRules() {
+		return nil
+	}
+	for _, team := range teamMapper.GetUnmappedTeams() {
+		log.Warn().Str("team", team[0]).Str("group", team[1]).Msg("team not covered by team mapping")
+	}
+	unmappedTeamsCSV := resultsmapping.WriteAllToSanitizedCsv(teamMapper.GenerateUnmappedTeamsCSV())
+	return exporter.AddFile(export2.UnmappedTeamsFileName, unmappedTeamsCSV)
+}
+
+// addTriageHistoryFile exports the triage history of the triaged results of a scan
+func addTriageHistoryFile(client rest.Client, exporter export2.Exporter, projectID, scanID int,
+	options triagehistory.Options,
+) error {
+	var results []*rest.ResultWithHistory
+	for offset := 0; ; offset += resultsPageLimit {
+		page, err := client.GetResultsHistoryByScanID(scanID, offset, resultsPageLimit)
+		if err != nil {
+			return errors.Wrap(err, "could not get triage history")
+		}
+		results = append(results, page...)
+		if len(page) < resultsPageLimit {
+			break
+		}
+	}
+	history := triagehistory.New(projectID, scanID, results, options)
+	fileName := path.Join(export2.TriageHistoryDirName, fmt.Sprintf(triageHistoryFileName, projectID))
+	return exporter.AddFileWithDataSource(fileName, export2.NewJSONDataSource(history))
+}
+
+func getTransformOptions(args *Args) export2.TransformOptions {
+	return export2.TransformOptions{
+		NestedTeams:       args.NestedTeams,
+		TeamMapper:        args.TeamMapper,
+		UserMapper:        args.UserMapper,
+		CustomFieldMapper: args.CustomFieldMapper,
+	}
+}
+
+// getBranchesWithRoot returns the lineage of the triaged projects and which of them are branches of another triaged project
+func getBranchesWithRoot(lineages *lineageFetcher, triagedScans []TriagedScan) (*lineage.Lineage, map[int]bool) {
+	triagedProjects := map[int]bool{}
+	projectIDs := make([]int, 0, len(triagedScans))
+	for _, scan := range triagedScans {
+		triagedProjects[scan.ProjectID] = true
+		projectIDs = append(projectIDs, scan.ProjectID)
+	}
+	projectLineage := lineages.get(projectIDs)
+	branchesWithRoot := map[int]bool{}
+	for _, projectID := range projectIDs {
+		if root := projectLineage.GetRoot(projectID); root != projectID && triagedProjects[root] {
+			branchesWithRoot[projectID] = true
+		}
+	}
+	return projectLineage, branchesWithRoot
+}
+
+// addBranchTriageFiles exports the triage of each branched project that differs from the triage of its lineage root
+func addBranchTriageFiles(exporter export2.Exporter, projectLineage *lineage.Lineage, records []*metadata.Record,
+	branchRecords map[int]*metadata.Record,
+) error {
+	entriesByProject := map[string][]*resultsmapping.Entry{}
+	for _, record := range records {
+		entriesByProject[record.ProjectID] = resultsmapping.GetEntries([]*metadata.Record{record})
+	}
+	for projectID, record := range branchRecords {
+		rootEntries := entriesByProject[strconv.Itoa(projectLineage.GetRoot(projectID))]
+		overrides := projectLineage.GetOverrides(projectID, rootEntries, resultsmapping.GetEntries([]*metadata.Record{record}))
+		fileName := path.Join(export2.BranchTriageDirName, fmt.Sprintf(branchTriageFileName, projectID))
+		if err := exporter.AddFileWithDataSource(fileName, export2.NewJSONDataSource(overrides)); err != nil {
+			return err
+		}
+		log.Info().Int("projectID", projectID).Int("overrides", len(overrides.Results)).Msg("collected branch triage overrides")
+	}
+	return nil
+}
+
 func addAllResultsMappingToFile(metadataRecord []*metadata.Record, exporter export2.Exporter) error {
 	// Sort metadata records to ensure deterministic output
 	sortMetadataRecords(metadataRecord)
@@ -824,10 +1533,72 @@ func addAllResultsMappingToFile(metadataRecord []*metadata.Record, exporter expo
 	if exportResultsErr != nil {
 		return exportResultsErr
 	}
+	exportResultsJSONErr := exporter.AddFileWithDataSource(export2.ResultsMappingJSONFileName,
+		export2.NewJSONDataSource(resultsmapping.GetEntries(metadataRecord)))
+	if exportResultsJSONErr != nil {
+		return exportResultsJSONErr
+	}
 	log.Info().Msg("collected results mapping")
 	return nil
 }
 
+// addQueryResolutionFile reports how the AST query id of each triaged query was resolved,
+// warning about the queries whose triage is likely to be lost
+func addQueryResolutionFile(queryResolution *queryresolution.Report, exporter export2.Exporter) error {
+	for _, e := range queryResolution.GetEntries() {
+		if e.Warning != "" {
+			log.Warn().
+				Str("language", e.Language).
+				Str("group", e.Group).
+				Str("query", e.Name).
+				Int("triagedPaths", e.TriagedPaths).
+				Msg(e.Warning)
+		}
+	}
+	queryResolutionCSV := resultsmapping.WriteAllToSanitizedCsv(queryResolution.GenerateCSV())
+	return exporter.AddFile(export2.QueryResolutionFileName, queryResolutionCSV)
+}
+
+// addQueryMappingConflictsFile reports the SAST queries assigned different AST query ids by the query mappings
+func addQueryMappingConflictsFile(queryMappingProvider *querymapping.Provider, exporter export2.Exporter) error {
+	for _, c := range queryMappingProvider.GetConflicts() {
+		log.Warn().
+			Str("sastQueryId", c.SastID).
+			Str("astQueryId", c.AstID).
+			Str("origin", c.Origin).
+			Str("overriddenAstQueryId", c.OverriddenAstID).
+			Str("overriddenOrigin", c.OverriddenOrigin).
+			Msg("query mapping conflict")
+	}
+	conflictsCSV := resultsmapping.WriteAllToSanitizedCsv(queryMappingProvider.GenerateConflictsCSV())
+	return exporter.AddFile(export2.QueryMappingConflictsFileName, conflictsCSV)
+}
+
+// checkQueryMappingConflicts warns about the query mapping conflicts affecting triaged queries,
+// and fails on them if the mapping is strict
+func checkQueryMappingConflicts(conflicts []querymapping.Conflict, queryResolution *queryresolution.Report, strict bool) error {
+	triaged := map[string]int{}
+	for _, e := range queryResolution.GetEntries() {
+		triaged[e.SastQueryID] += e.TriagedPaths
+	}
+	var affected []querymapping.Conflict
+	for _, c := range conflicts {
+		if triagedPaths, exists := triaged[c.SastID]; exists {
+			log.Warn().
+				Str("sastQueryId", c.SastID).
+				Str("astQueryId", c.AstID).
+				Str("overriddenAstQueryId", c.OverriddenAstID).
+				Int("triagedPaths", triagedPaths).
+				Msg("query mapping conflict affects triaged query")
+			affected = append(affected, c)
+		}
+	}
+	if strict && len(affected) > 0 {
+		return &querymapping.ConflictError{Conflicts: affected}
+	}
+	return nil
+}
+
 func getTriagedScans(client rest.Client, fromDate, teamName, projectsIDs string) ([]TriagedScan, error) {
 	var output []TriagedScan
 	projectOffset := 0
@@ -1033,7 +1804,7 @@ func consumePresets(soapClient interfaces.PresetProvider, exporter export2.Expor
 			Int("PresetID", presetJob.PresetID).
 			Int("worker", workerID).
 			Logger()
-		presetData, presetErr := getPresetData(soapClient, presetJob.PresetID)
+		presetDetails, presetData, presetErr := getPresetData(soapClient, presetJob.PresetID)
 		if presetErr != nil {
 			l.Debug().Err(presetErr).Msgf("failed creating preset %d", presetJob.PresetID)
 			done <- PresetConsumeOutput{Err: presetErr, PresetID: presetJob.PresetID}
@@ -1046,21 +1817,21 @@ func consumePresets(soapClient interfaces.PresetProvider, exporter export2.Expor
 			continue
 		}
 
-		done <- PresetConsumeOutput{Err: nil, PresetID: presetJob.PresetID}
+		done <- PresetConsumeOutput{Err: nil, PresetID: presetJob.PresetID, Preset: presetDetails}
 	}
 }
 
-func getPresetData(soapClient interfaces.PresetProvider, presetID int) ([]byte, error) {
+func getPresetData(soapClient interfaces.PresetProvider, presetID int) (*soap.Preset, []byte, error) {
 	presetResponse, err := soapClient.GetPresetDetails(presetID)
 	if err != nil {
-		return nil, errors.Wrap(err, "error with getting getPresetDetails")
+		return nil, nil, errors.Wrap(err, "error with getting getPresetDetails")
 	}
 
 	presetData, marshalErr := xml.MarshalIndent(presetResponse, "  ", "    ")
 	if marshalErr != nil {
-		return nil, errors.Wrapf(marshalErr, "marshal error with getting preset %d", presetID)
+		return nil, nil, errors.Wrapf(marshalErr, "marshal error with getting preset %d", presetID)
 	}
-	return presetData, nil
+	return &presetResponse.GetPresetDetailsResult.Preset, presetData, nil
 }
 
 func getRetryHTTPClient() *retryablehttp.Client {
@@ -1172,10 +1943,12 @@ func addCustomQueryIDs(astQueryProvider interfaces.ASTQueryProvider, astQueryMap
 		queryGroup := customQueryResp.GetQueryCollectionResult.QueryGroups.CxWSQueryGroup[i]
 		for j := range queryGroup.Queries.CxWSQuery {
 			query := queryGroup.Queries.CxWSQuery[j]
-			if err := astQueryMappingProvider.AddQueryMapping(queryGroup.LanguageName, query.Name,
-				queryGroup.Name, strconv.Itoa(query.QueryID)); err != nil {
+			sastQueryID := strconv.Itoa(query.QueryID)
+			astQueryID, err := astQueryProvider.GetQueryID(queryid.NewCollectionQuery(&queryGroup, &query))
+			if err != nil {
 				return err
 			}
+			astQueryMappingProvider.AddQueryMapping(sastQueryID, astQueryID)
 		}
 	}
 
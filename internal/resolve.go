@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/fetch"
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/pkg/errors"
+)
+
+// ResolveQuery writes the AST query id of a query and how it was resolved, without connecting to SAST.
+// Query mappings and renames are layered in the given order, later ones taking precedence.
+func ResolveQuery(args *ResolveQueryArgs, output io.Writer) error {
+	dataBundle, dataBundleErr := loadDataBundle(args.DataBundleFile, args.DataBundleKeyFile)
+	if dataBundleErr != nil {
+		return errors.Wrap(dataBundleErr, "could not load data bundle")
+	}
+	fetcher := newFetcher(getRetryHTTPClient())
+	resolver := queryid.NewResolver()
+	if _, _, queryMappingErr := loadQueryMappings(fetcher, args.QueryMappingFiles, dataBundle, nil, resolver); queryMappingErr != nil {
+		return errors.Wrap(queryMappingErr, "could not load query mapping")
+	}
+	for _, queryRenamingFile := range args.QueryRenamingFiles {
+		if _, renameErr := addQueryRenaming(resolver, fetcher, queryRenamingFile, dataBundle, fetch.Options{}); renameErr != nil {
+			return errors.Wrap(renameErr, "could not load query renaming")
+		}
+	}
+	resolution, resolveErr := resolver.Resolve(queryid.Query{
+		Language:    args.Language,
+		Group:       args.Group,
+		Name:        args.Name,
+		SastQueryID: args.SastQueryID,
+		QueryPath:   args.QueryPath,
+	})
+	if resolveErr != nil {
+		return resolveErr
+	}
+	data, marshalErr := json.MarshalIndent(resolution, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+	_, writeErr := fmt.Fprintln(output, string(data))
+	return writeErr
+}
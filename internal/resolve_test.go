@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveQuery(t *testing.T) {
+	t.Run("resolves query from files", func(t *testing.T) {
+		args := &ResolveQueryArgs{
+			Language:           "Kotlin",
+			Group:              "Kotlin_High_Risk",
+			Name:               "Code_Injection",
+			SastQueryID:        "999999",
+			QueryMappingFiles:  []string{"../data/mapping.json"},
+			QueryRenamingFiles: []string{"../data/renames.json"},
+		}
+		var output bytes.Buffer
+
+		err := ResolveQuery(args, &output)
+
+		assert.NoError(t, err)
+		var result queryid.Resolution
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &result))
+		assert.Equal(t, "15158446363146771540", result.AstQueryID)
+		assert.Equal(t, queryid.HashSource, result.Source)
+	})
+	t.Run("fails if query mapping can't be loaded", func(t *testing.T) {
+		args := &ResolveQueryArgs{SastQueryID: "1", QueryMappingFiles: []string{"does_not_exist.json"}}
+
+		err := ResolveQuery(args, &bytes.Buffer{})
+
+		assert.ErrorContains(t, err, "could not load query mapping: could not read does_not_exist.json")
+	})
+	t.Run("fails if query renaming can't be loaded", func(t *testing.T) {
+		args := &ResolveQueryArgs{SastQueryID: "1", QueryRenamingFiles: []string{"does_not_exist.json"}}
+
+		err := ResolveQuery(args, &bytes.Buffer{})
+
+		assert.ErrorContains(t, err, "could not load query renaming")
+	})
+	t.Run("fails without query", func(t *testing.T) {
+		err := ResolveQuery(&ResolveQueryArgs{SastQueryID: "1"}, &bytes.Buffer{})
+
+		assert.EqualError(t, err, "query 1 must have a language, group and name, or a query path")
+	})
+}
@@ -12,6 +12,7 @@ package mock_interfaces
 import (
 	reflect "reflect"
 
+	queryid "github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	soap "github.com/checkmarxDev/ast-sast-export/internal/integration/soap"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -69,19 +70,34 @@ func (mr *MockASTQueryProviderMockRecorder) GetCustomStatesList() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomStatesList", reflect.TypeOf((*MockASTQueryProvider)(nil).GetCustomStatesList))
 }
 
+// GetQueriesList mocks base method.
+func (m *MockASTQueryProvider) GetQueriesList() (*soap.GetQueryCollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueriesList")
+	ret0, _ := ret[0].(*soap.GetQueryCollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueriesList indicates an expected call of GetQueriesList.
+func (mr *MockASTQueryProviderMockRecorder) GetQueriesList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueriesList", reflect.TypeOf((*MockASTQueryProvider)(nil).GetQueriesList))
+}
+
 // GetQueryID mocks base method.
-func (m *MockASTQueryProvider) GetQueryID(arg0, arg1, arg2, arg3 string) (string, error) {
+func (m *MockASTQueryProvider) GetQueryID(arg0 queryid.Query) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetQueryID", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "GetQueryID", arg0)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetQueryID indicates an expected call of GetQueryID.
-func (mr *MockASTQueryProviderMockRecorder) GetQueryID(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockASTQueryProviderMockRecorder) GetQueryID(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryID", reflect.TypeOf((*MockASTQueryProvider)(nil).GetQueryID), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryID", reflect.TypeOf((*MockASTQueryProvider)(nil).GetQueryID), arg0)
 }
 
 // GetRawCustomStatesList mocks base method.
@@ -113,3 +129,32 @@ func (mr *MockASTQueryProviderMockRecorder) GetStateMapping() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateMapping", reflect.TypeOf((*MockASTQueryProvider)(nil).GetStateMapping))
 }
+
+// IsMappedQuery mocks base method.
+func (m *MockASTQueryProvider) IsMappedQuery(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMappedQuery", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsMappedQuery indicates an expected call of IsMappedQuery.
+func (mr *MockASTQueryProviderMockRecorder) IsMappedQuery(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMappedQuery", reflect.TypeOf((*MockASTQueryProvider)(nil).IsMappedQuery), arg0)
+}
+
+// ResolveQuery mocks base method.
+func (m *MockASTQueryProvider) ResolveQuery(arg0 queryid.Query) (*queryid.Resolution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveQuery", arg0)
+	ret0, _ := ret[0].(*queryid.Resolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveQuery indicates an expected call of ResolveQuery.
+func (mr *MockASTQueryProviderMockRecorder) ResolveQuery(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveQuery", reflect.TypeOf((*MockASTQueryProvider)(nil).ResolveQuery), arg0)
+}
@@ -12,6 +12,7 @@ package mock_app_ast_query_id
 import (
 	reflect "reflect"
 
+	queryid "github.com/checkmarxDev/ast-sast-export/internal/app/queryid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,16 +40,31 @@ func (m *MockASTQueryIDProvider) EXPECT() *MockASTQueryIDProviderMockRecorder {
 }
 
 // GetQueryID mocks base method.
-func (m *MockASTQueryIDProvider) GetQueryID(arg0, arg1, arg2, arg3 string) (string, error) {
+func (m *MockASTQueryIDProvider) GetQueryID(arg0 queryid.Query) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetQueryID", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "GetQueryID", arg0)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetQueryID indicates an expected call of GetQueryID.
-func (mr *MockASTQueryIDProviderMockRecorder) GetQueryID(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockASTQueryIDProviderMockRecorder) GetQueryID(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryID", reflect.TypeOf((*MockASTQueryIDProvider)(nil).GetQueryID), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryID", reflect.TypeOf((*MockASTQueryIDProvider)(nil).GetQueryID), arg0)
+}
+
+// ResolveQuery mocks base method.
+func (m *MockASTQueryIDProvider) ResolveQuery(arg0 queryid.Query) (*queryid.Resolution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveQuery", arg0)
+	ret0, _ := ret[0].(*queryid.Resolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveQuery indicates an expected call of ResolveQuery.
+func (mr *MockASTQueryIDProviderMockRecorder) ResolveQuery(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveQuery", reflect.TypeOf((*MockASTQueryIDProvider)(nil).ResolveQuery), arg0)
 }
@@ -40,17 +40,15 @@ func (m *MockQueryMappingRepo) EXPECT() *MockQueryMappingRepoMockRecorder {
 }
 
 // AddQueryMapping mocks base method.
-func (m *MockQueryMappingRepo) AddQueryMapping(arg0, arg1, arg2, arg3 string) error {
+func (m *MockQueryMappingRepo) AddQueryMapping(arg0, arg1 string) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddQueryMapping", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(error)
-	return ret0
+	m.ctrl.Call(m, "AddQueryMapping", arg0, arg1)
 }
 
 // AddQueryMapping indicates an expected call of AddQueryMapping.
-func (mr *MockQueryMappingRepoMockRecorder) AddQueryMapping(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockQueryMappingRepoMockRecorder) AddQueryMapping(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQueryMapping", reflect.TypeOf((*MockQueryMappingRepo)(nil).AddQueryMapping), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQueryMapping", reflect.TypeOf((*MockQueryMappingRepo)(nil).AddQueryMapping), arg0, arg1)
 }
 
 // GetMapping mocks base method.
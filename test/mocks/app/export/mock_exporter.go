@@ -10,6 +10,7 @@
 package mock_app_export
 
 import (
+	io "io"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -66,6 +67,20 @@ func (mr *MockExporterMockRecorder) AddFileWithDataSource(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFileWithDataSource", reflect.TypeOf((*MockExporter)(nil).AddFileWithDataSource), arg0, arg1)
 }
 
+// AddFileWithWriter mocks base method.
+func (m *MockExporter) AddFileWithWriter(arg0 string, arg1 func(io.Writer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFileWithWriter", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFileWithWriter indicates an expected call of AddFileWithWriter.
+func (mr *MockExporterMockRecorder) AddFileWithWriter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFileWithWriter", reflect.TypeOf((*MockExporter)(nil).AddFileWithWriter), arg0, arg1)
+}
+
 // Clean mocks base method.
 func (m *MockExporter) Clean() error {
 	m.ctrl.T.Helper()
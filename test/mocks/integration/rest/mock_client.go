@@ -55,10 +55,10 @@ func (mr *MockClientMockRecorder) Authenticate(arg0, arg1 any) *gomock.Call {
 }
 
 // CreateScanReport mocks base method.
-func (m *MockClient) CreateScanReport(arg0 int, arg1 string, arg2 rest.Retry) ([]byte, error) {
+func (m *MockClient) CreateScanReport(arg0 int, arg1 string, arg2 rest.Retry) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateScanReport", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]byte)
+	ret0, _ := ret[0].(io.ReadCloser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -69,6 +69,21 @@ func (mr *MockClientMockRecorder) CreateScanReport(arg0, arg1, arg2 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScanReport", reflect.TypeOf((*MockClient)(nil).CreateScanReport), arg0, arg1, arg2)
 }
 
+// GetAuthenticationProviders mocks base method.
+func (m *MockClient) GetAuthenticationProviders() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthenticationProviders")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthenticationProviders indicates an expected call of GetAuthenticationProviders.
+func (mr *MockClientMockRecorder) GetAuthenticationProviders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthenticationProviders", reflect.TypeOf((*MockClient)(nil).GetAuthenticationProviders))
+}
+
 // GetConfigurationsKeys mocks base method.
 func (m *MockClient) GetConfigurationsKeys() (*rest.EngineKeysConfigMapping, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +99,36 @@ func (mr *MockClientMockRecorder) GetConfigurationsKeys() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigurationsKeys", reflect.TypeOf((*MockClient)(nil).GetConfigurationsKeys))
 }
 
+// GetCustomFields mocks base method.
+func (m *MockClient) GetCustomFields() ([]*rest.CustomFieldDefinition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomFields")
+	ret0, _ := ret[0].([]*rest.CustomFieldDefinition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomFields indicates an expected call of GetCustomFields.
+func (mr *MockClientMockRecorder) GetCustomFields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomFields", reflect.TypeOf((*MockClient)(nil).GetCustomFields))
+}
+
+// GetDataRetentionSettings mocks base method.
+func (m *MockClient) GetDataRetentionSettings() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataRetentionSettings")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataRetentionSettings indicates an expected call of GetDataRetentionSettings.
+func (mr *MockClientMockRecorder) GetDataRetentionSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataRetentionSettings", reflect.TypeOf((*MockClient)(nil).GetDataRetentionSettings))
+}
+
 // GetEngineConfigurationMappings mocks base method.
 func (m *MockClient) GetEngineConfigurationMappings() ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -145,10 +190,10 @@ func (mr *MockClientMockRecorder) GetLdapRoleMappings() *gomock.Call {
 }
 
 // GetLdapServers mocks base method.
-func (m *MockClient) GetLdapServers() ([]byte, error) {
+func (m *MockClient) GetLdapServers() ([]*rest.LdapServer, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetLdapServers")
-	ret0, _ := ret[0].([]byte)
+	ret0, _ := ret[0].([]*rest.LdapServer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -174,6 +219,21 @@ func (mr *MockClientMockRecorder) GetLdapTeamMappings() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLdapTeamMappings", reflect.TypeOf((*MockClient)(nil).GetLdapTeamMappings))
 }
 
+// GetPermissions mocks base method.
+func (m *MockClient) GetPermissions() ([]*rest.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions")
+	ret0, _ := ret[0].([]*rest.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockClientMockRecorder) GetPermissions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockClient)(nil).GetPermissions))
+}
+
 // GetPresets mocks base method.
 func (m *MockClient) GetPresets() ([]*rest.PresetShort, error) {
 	m.ctrl.T.Helper()
@@ -189,6 +249,36 @@ func (mr *MockClientMockRecorder) GetPresets() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPresets", reflect.TypeOf((*MockClient)(nil).GetPresets))
 }
 
+// GetProjectBranch mocks base method.
+func (m *MockClient) GetProjectBranch(arg0 int) (*rest.ProjectBranch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectBranch", arg0)
+	ret0, _ := ret[0].(*rest.ProjectBranch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectBranch indicates an expected call of GetProjectBranch.
+func (mr *MockClientMockRecorder) GetProjectBranch(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectBranch", reflect.TypeOf((*MockClient)(nil).GetProjectBranch), arg0)
+}
+
+// GetProjectDataRetentionSettings mocks base method.
+func (m *MockClient) GetProjectDataRetentionSettings(arg0 int) (*rest.ProjectDataRetentionSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectDataRetentionSettings", arg0)
+	ret0, _ := ret[0].(*rest.ProjectDataRetentionSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectDataRetentionSettings indicates an expected call of GetProjectDataRetentionSettings.
+func (mr *MockClientMockRecorder) GetProjectDataRetentionSettings(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectDataRetentionSettings", reflect.TypeOf((*MockClient)(nil).GetProjectDataRetentionSettings), arg0)
+}
+
 // GetProjectExcludeSettings mocks base method.
 func (m *MockClient) GetProjectExcludeSettings(arg0 int) (*rest.ProjectExcludeSettings, error) {
 	m.ctrl.T.Helper()
@@ -204,6 +294,66 @@ func (mr *MockClientMockRecorder) GetProjectExcludeSettings(arg0 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectExcludeSettings", reflect.TypeOf((*MockClient)(nil).GetProjectExcludeSettings), arg0)
 }
 
+// GetProjectGitSettings mocks base method.
+func (m *MockClient) GetProjectGitSettings(arg0 int) (*rest.ProjectGitSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectGitSettings", arg0)
+	ret0, _ := ret[0].(*rest.ProjectGitSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectGitSettings indicates an expected call of GetProjectGitSettings.
+func (mr *MockClientMockRecorder) GetProjectGitSettings(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectGitSettings", reflect.TypeOf((*MockClient)(nil).GetProjectGitSettings), arg0)
+}
+
+// GetProjectIssueTrackingSettings mocks base method.
+func (m *MockClient) GetProjectIssueTrackingSettings(arg0 int) (*rest.ProjectIssueTrackingSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectIssueTrackingSettings", arg0)
+	ret0, _ := ret[0].(*rest.ProjectIssueTrackingSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectIssueTrackingSettings indicates an expected call of GetProjectIssueTrackingSettings.
+func (mr *MockClientMockRecorder) GetProjectIssueTrackingSettings(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectIssueTrackingSettings", reflect.TypeOf((*MockClient)(nil).GetProjectIssueTrackingSettings), arg0)
+}
+
+// GetProjectScanSettings mocks base method.
+func (m *MockClient) GetProjectScanSettings(arg0 int) (*rest.ProjectScanSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectScanSettings", arg0)
+	ret0, _ := ret[0].(*rest.ProjectScanSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectScanSettings indicates an expected call of GetProjectScanSettings.
+func (mr *MockClientMockRecorder) GetProjectScanSettings(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectScanSettings", reflect.TypeOf((*MockClient)(nil).GetProjectScanSettings), arg0)
+}
+
+// GetProjectScheduling mocks base method.
+func (m *MockClient) GetProjectScheduling(arg0 int) (*rest.ProjectScheduling, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectScheduling", arg0)
+	ret0, _ := ret[0].(*rest.ProjectScheduling)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectScheduling indicates an expected call of GetProjectScheduling.
+func (mr *MockClientMockRecorder) GetProjectScheduling(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectScheduling", reflect.TypeOf((*MockClient)(nil).GetProjectScheduling), arg0)
+}
+
 // GetProjects mocks base method.
 func (m *MockClient) GetProjects(arg0, arg1, arg2 string, arg3, arg4 int) ([]*rest.Project, error) {
 	m.ctrl.T.Helper()
@@ -234,6 +384,21 @@ func (mr *MockClientMockRecorder) GetProjectsWithLastScanID(arg0, arg1, arg2, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectsWithLastScanID", reflect.TypeOf((*MockClient)(nil).GetProjectsWithLastScanID), arg0, arg1, arg2, arg3, arg4)
 }
 
+// GetResultSeverityLevels mocks base method.
+func (m *MockClient) GetResultSeverityLevels() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResultSeverityLevels")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResultSeverityLevels indicates an expected call of GetResultSeverityLevels.
+func (mr *MockClientMockRecorder) GetResultSeverityLevels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultSeverityLevels", reflect.TypeOf((*MockClient)(nil).GetResultSeverityLevels))
+}
+
 // GetRoles mocks base method.
 func (m *MockClient) GetRoles() ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -249,11 +414,26 @@ func (mr *MockClientMockRecorder) GetRoles() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockClient)(nil).GetRoles))
 }
 
+// GetSMTPSettings mocks base method.
+func (m *MockClient) GetSMTPSettings() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSMTPSettings")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSMTPSettings indicates an expected call of GetSMTPSettings.
+func (mr *MockClientMockRecorder) GetSMTPSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSMTPSettings", reflect.TypeOf((*MockClient)(nil).GetSMTPSettings))
+}
+
 // GetSamlIdentityProviders mocks base method.
-func (m *MockClient) GetSamlIdentityProviders() ([]byte, error) {
+func (m *MockClient) GetSamlIdentityProviders() ([]*rest.SamlIdentityProvider, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSamlIdentityProviders")
-	ret0, _ := ret[0].([]byte)
+	ret0, _ := ret[0].([]*rest.SamlIdentityProvider)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -294,6 +474,36 @@ func (mr *MockClientMockRecorder) GetSamlTeamMappings() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSamlTeamMappings", reflect.TypeOf((*MockClient)(nil).GetSamlTeamMappings))
 }
 
+// GetScanQueueSettings mocks base method.
+func (m *MockClient) GetScanQueueSettings() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScanQueueSettings")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScanQueueSettings indicates an expected call of GetScanQueueSettings.
+func (mr *MockClientMockRecorder) GetScanQueueSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScanQueueSettings", reflect.TypeOf((*MockClient)(nil).GetScanQueueSettings))
+}
+
+// GetScans mocks base method.
+func (m *MockClient) GetScans(arg0, arg1, arg2 string, arg3, arg4 int) ([]*rest.Scan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScans", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*rest.Scan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScans indicates an expected call of GetScans.
+func (mr *MockClientMockRecorder) GetScans(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScans", reflect.TypeOf((*MockClient)(nil).GetScans), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetTeams mocks base method.
 func (m *MockClient) GetTeams() ([]*rest.Team, error) {
 	m.ctrl.T.Helper()
@@ -309,6 +519,21 @@ func (mr *MockClientMockRecorder) GetTeams() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeams", reflect.TypeOf((*MockClient)(nil).GetTeams))
 }
 
+// GetResultsHistoryByScanID mocks base method.
+func (m *MockClient) GetResultsHistoryByScanID(arg0, arg1, arg2 int) ([]*rest.ResultWithHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResultsHistoryByScanID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*rest.ResultWithHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResultsHistoryByScanID indicates an expected call of GetResultsHistoryByScanID.
+func (mr *MockClientMockRecorder) GetResultsHistoryByScanID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultsHistoryByScanID", reflect.TypeOf((*MockClient)(nil).GetResultsHistoryByScanID), arg0, arg1, arg2)
+}
+
 // GetTriagedResultsByScanID mocks base method.
 func (m *MockClient) GetTriagedResultsByScanID(arg0 int) (*[]rest.TriagedScanResult, error) {
 	m.ctrl.T.Helper()